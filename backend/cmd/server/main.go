@@ -4,6 +4,8 @@ import (
 	"flag"
 	"log"
 	"os"
+	"time"
+	_ "time/tzdata" // event time zones must resolve even without system tzdata
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -14,11 +16,20 @@ import (
 	_ "online-task/docs"
 	"online-task/internal/auth"
 	"online-task/internal/booking"
+	"online-task/internal/checkin"
 	"online-task/internal/event"
+	"online-task/internal/idempotency"
+	"online-task/internal/notification"
+	"online-task/internal/promo"
+	"online-task/internal/seating"
 	"online-task/internal/tag"
 	"online-task/internal/upload"
+	"online-task/internal/venue"
 	"online-task/pkg/database"
+	"online-task/pkg/payment"
+	"online-task/pkg/scanner"
 	"online-task/pkg/seed"
+	"online-task/pkg/ticket"
 )
 
 // @title           Event Booking API
@@ -62,6 +73,50 @@ func main() {
 		}
 	}
 
+	// Scan uploads with clamd when CLAMAV_ADDRESS is set (e.g. localhost:3310)
+	upload.SetScanner(scanner.New(os.Getenv("CLAMAV_ADDRESS")))
+
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+
+	// Take payments with Stripe when PAYMENT_PROVIDER=stripe; the fake
+	// provider delivers its webhooks to this server
+	provider, err := payment.New(payment.Config{
+		Provider:            os.Getenv("PAYMENT_PROVIDER"),
+		StripeSecretKey:     os.Getenv("STRIPE_SECRET_KEY"),
+		StripeWebhookSecret: os.Getenv("STRIPE_WEBHOOK_SECRET"),
+		StripeBaseURL:       os.Getenv("STRIPE_API_BASE"),
+		FakeWebhookURL:      "http://localhost:" + port + "/api/payments/webhook",
+	})
+	if err != nil {
+		log.Fatalf("Failed to configure payments: %v", err)
+	}
+	booking.SetPaymentProvider(provider)
+
+	// Sign e-tickets with TICKET_SIGNING_KEY, a base64 Ed25519 seed, so they
+	// and offline manifests stay valid across restarts. Only development
+	// servers, outside gin's release mode, fall back to a temporary key.
+	var signer *ticket.Signer
+	switch key := os.Getenv("TICKET_SIGNING_KEY"); {
+	case key != "":
+		privateKey, err := ticket.ParsePrivateKey(key)
+		if err != nil {
+			log.Fatalf("Failed to configure ticket signing: %v", err)
+		}
+		signer = ticket.NewSigner(privateKey)
+	case gin.Mode() == gin.ReleaseMode:
+		log.Fatalf("TICKET_SIGNING_KEY must be set in release mode")
+	default:
+		signer, err = ticket.GenerateSigner()
+		if err != nil {
+			log.Fatalf("Failed to generate a ticket signing key: %v", err)
+		}
+		log.Printf("TICKET_SIGNING_KEY is not set; e-tickets are signed with a temporary key")
+	}
+	booking.SetTicketSigner(signer)
+
 	// Initialize router
 	r := gin.Default()
 
@@ -72,7 +127,9 @@ func main() {
 	config := cors.DefaultConfig()
 	config.AllowOrigins = []string{"http://localhost:5173"} // Vite default port
 	config.AllowCredentials = true
-	config.AllowHeaders = append(config.AllowHeaders, "Authorization")
+	config.AllowHeaders = append(config.AllowHeaders, "Authorization", booking.GuestTokenHeader, idempotency.Header)
+	config.AllowHeaders = append(config.AllowHeaders, upload.TusHeaders...)
+	config.ExposeHeaders = append(upload.TusHeaders, idempotency.ReplayedHeader)
 	r.Use(cors.New(config))
 
 	// Swagger documentation
@@ -91,45 +148,166 @@ func main() {
 		// Events routes
 		eventsGroup := api.Group("/events")
 		{
-			eventsGroup.GET("", event.GetAllEventsHandler)
-			eventsGroup.GET("/:id", event.GetEventHandler)
+			eventsGroup.GET("", auth.OptionalAuthMiddleware(), event.GetAllEventsHandler)
+			eventsGroup.GET("/:id", auth.OptionalAuthMiddleware(), event.GetEventHandler)
 			eventsGroup.POST("", auth.AuthMiddleware(), auth.AdminMiddleware(), event.CreateEventHandler)
 			eventsGroup.PUT("/:id", auth.AuthMiddleware(), auth.AdminMiddleware(), event.UpdateEventHandler)
 			eventsGroup.DELETE("/:id", auth.AuthMiddleware(), auth.AdminMiddleware(), event.DeleteEventHandler)
+			eventsGroup.PUT("/:id/following", auth.AuthMiddleware(), auth.AdminMiddleware(), event.UpdateFollowingOccurrencesHandler)
+			eventsGroup.POST("/:id/publish", auth.AuthMiddleware(), auth.AdminMiddleware(), event.PublishEventHandler)
+			eventsGroup.POST("/:id/unpublish", auth.AuthMiddleware(), auth.AdminMiddleware(), event.UnpublishEventHandler)
+			eventsGroup.POST("/:id/cancel", auth.AuthMiddleware(), auth.AdminMiddleware(), event.CancelEventHandler)
+			eventsGroup.POST("/:id/postpone", auth.AuthMiddleware(), auth.AdminMiddleware(), event.PostponeEventHandler)
+			eventsGroup.POST("/:id/complete", auth.AuthMiddleware(), auth.AdminMiddleware(), event.CompleteEventHandler)
+			eventsGroup.POST("/:id/media", auth.AuthMiddleware(), auth.AdminMiddleware(), event.AddEventMediaHandler)
+			eventsGroup.PUT("/:id/media", auth.AuthMiddleware(), auth.AdminMiddleware(), event.ReorderEventMediaHandler)
+			eventsGroup.PUT("/:id/media/:mediaId", auth.AuthMiddleware(), auth.AdminMiddleware(), event.UpdateEventMediaHandler)
+			eventsGroup.DELETE("/:id/media/:mediaId", auth.AuthMiddleware(), auth.AdminMiddleware(), event.DeleteEventMediaHandler)
+			eventsGroup.GET("/:id/ticket-types", event.GetTicketTypesHandler)
+			eventsGroup.POST("/:id/ticket-types", auth.AuthMiddleware(), auth.AdminMiddleware(), event.CreateTicketTypeHandler)
+			eventsGroup.PUT("/:id/ticket-types/:typeId", auth.AuthMiddleware(), auth.AdminMiddleware(), event.UpdateTicketTypeHandler)
+			eventsGroup.DELETE("/:id/ticket-types/:typeId", auth.AuthMiddleware(), auth.AdminMiddleware(), event.DeleteTicketTypeHandler)
+			eventsGroup.GET("/:id/seats", seating.GetEventSeatsHandler)
+			eventsGroup.PUT("/:id/seat-map", auth.AuthMiddleware(), auth.AdminMiddleware(), seating.SaveEventSeatMapHandler)
+			eventsGroup.DELETE("/:id/seat-map", auth.AuthMiddleware(), auth.AdminMiddleware(), seating.DeleteEventSeatMapHandler)
+			eventsGroup.GET("/:id/checkin/stats", auth.AuthMiddleware(), auth.StaffMiddleware(), checkin.CheckInStatsHandler)
+			eventsGroup.GET("/:id/checkin/manifest", auth.AuthMiddleware(), auth.StaffMiddleware(), checkin.ManifestHandler)
+			eventsGroup.POST("/:id/checkin/sync", auth.AuthMiddleware(), auth.StaffMiddleware(), checkin.SyncHandler)
+		}
+
+		// Recurring series routes
+		seriesGroup := api.Group("/series")
+		{
+			seriesGroup.GET("/:id", auth.OptionalAuthMiddleware(), event.GetSeriesHandler)
+			seriesGroup.PUT("/:id", auth.AuthMiddleware(), auth.AdminMiddleware(), event.UpdateSeriesHandler)
+		}
+
+		// Promo code routes
+		promoGroup := api.Group("/promo-codes")
+		{
+			promoGroup.Use(auth.AuthMiddleware(), auth.AdminMiddleware())
+			promoGroup.GET("", promo.GetAllPromoCodesHandler)
+			promoGroup.POST("", promo.CreatePromoCodeHandler)
+			promoGroup.PUT("/:id", promo.UpdatePromoCodeHandler)
+			promoGroup.DELETE("/:id", promo.DeletePromoCodeHandler)
+		}
+
+		// Venues routes
+		venuesGroup := api.Group("/venues")
+		{
+			venuesGroup.GET("", venue.GetAllVenuesHandler)
+			venuesGroup.GET("/:id", venue.GetVenueHandler)
+			venuesGroup.POST("", auth.AuthMiddleware(), auth.AdminMiddleware(), venue.CreateVenueHandler)
+			venuesGroup.PUT("/:id", auth.AuthMiddleware(), auth.AdminMiddleware(), venue.UpdateVenueHandler)
+			venuesGroup.DELETE("/:id", auth.AuthMiddleware(), auth.AdminMiddleware(), venue.DeleteVenueHandler)
+			venuesGroup.GET("/:id/seat-map", seating.GetVenueSeatMapHandler)
+			venuesGroup.PUT("/:id/seat-map", auth.AuthMiddleware(), auth.AdminMiddleware(), seating.SaveVenueSeatMapHandler)
+			venuesGroup.DELETE("/:id/seat-map", auth.AuthMiddleware(), auth.AdminMiddleware(), seating.DeleteVenueSeatMapHandler)
 		}
 
 		// Tags routes
 		tagsGroup := api.Group("/tags")
 		{
 			tagsGroup.GET("", tag.GetAllTagsHandler)
+			tagsGroup.GET("/tree", tag.GetTagTreeHandler)
+			tagsGroup.GET("/autocomplete", tag.AutocompleteTagsHandler)
 			tagsGroup.GET("/:id", tag.GetTagHandler)
 			tagsGroup.POST("", auth.AuthMiddleware(), auth.AdminMiddleware(), tag.CreateTagHandler)
 			tagsGroup.PUT("/:id", auth.AuthMiddleware(), auth.AdminMiddleware(), tag.UpdateTagHandler)
 			tagsGroup.DELETE("/:id", auth.AuthMiddleware(), auth.AdminMiddleware(), tag.DeleteTagHandler)
+			tagsGroup.POST("/:id/merge", auth.AuthMiddleware(), auth.AdminMiddleware(), tag.MergeTagHandler)
 		}
 
 		// Bookings routes
 		bookingsGroup := api.Group("/bookings")
 		{
-			bookingsGroup.Use(auth.AuthMiddleware())
+			bookingsGroup.Use(auth.AuthMiddleware(), idempotency.Middleware())
 			bookingsGroup.POST("", booking.CreateBookingHandler)
+			bookingsGroup.POST("/quote", booking.QuoteBookingHandler)
+			bookingsGroup.POST("/holds", booking.CreateHoldHandler)
+			bookingsGroup.DELETE("/holds/:id", booking.ReleaseHoldHandler)
 			bookingsGroup.GET("/user", booking.GetUserBookingsHandler)
+			bookingsGroup.GET("/:id", booking.GetBookingHandler)
+			bookingsGroup.POST("/:id/pay", booking.PayBookingHandler)
+			bookingsGroup.GET("/:id/ticket.png", booking.TicketQRHandler)
+			bookingsGroup.GET("/:id/ticket.pdf", booking.TicketPDFHandler)
+			bookingsGroup.POST("/:id/transfer", booking.CreateTransferHandler)
+			bookingsGroup.GET("/:id/transfers", booking.GetBookingTransfersHandler)
+			bookingsGroup.DELETE("/:id", booking.CancelBookingHandler)
 		}
 
+		// Guests book without an account and manage their bookings through
+		// the magic link emailed to them
+		guestGroup := api.Group("/guest/bookings")
+		{
+			guestGroup.Use(idempotency.Middleware())
+			guestGroup.POST("", booking.CreateGuestBookingHandler)
+			guestGroup.POST("/quote", booking.QuoteGuestBookingHandler)
+			guestGroup.POST("/link", booking.ResendGuestLinksHandler)
+
+			guestBooking := guestGroup.Group("/:id", booking.GuestAccessMiddleware())
+			guestBooking.GET("", booking.GetBookingHandler)
+			guestBooking.POST("/pay", booking.PayBookingHandler)
+			guestBooking.GET("/ticket.png", booking.TicketQRHandler)
+			guestBooking.GET("/ticket.pdf", booking.TicketPDFHandler)
+			guestBooking.DELETE("", booking.CancelBookingHandler)
+		}
+
+		transfersGroup := api.Group("/transfers")
+		{
+			transfersGroup.Use(auth.AuthMiddleware(), idempotency.Middleware())
+			transfersGroup.GET("", booking.GetUserTransfersHandler)
+			transfersGroup.POST("/accept", booking.AcceptTransferHandler)
+			transfersGroup.DELETE("/:id", booking.CancelTransferHandler)
+		}
+
+		// Admins manage the bookings of events
+		adminGroup := api.Group("/admin")
+		{
+			adminGroup.Use(auth.AuthMiddleware(), auth.AdminMiddleware(), idempotency.Middleware())
+			adminGroup.GET("/events/:id/bookings", booking.ListEventBookingsHandler)
+			adminGroup.GET("/events/:id/bookings/export", booking.ExportEventBookingsHandler)
+			adminGroup.POST("/bookings/:id/cancel", booking.AdminCancelBookingHandler)
+			adminGroup.POST("/bookings/:id/move", booking.MoveBookingHandler)
+		}
+
+		// Payment provider webhooks are authenticated by their signature
+		api.POST("/payments/webhook", booking.PaymentWebhookHandler)
+
+		// Door staff scan tickets
+		api.POST("/checkin", auth.AuthMiddleware(), auth.StaffMiddleware(), checkin.CheckInHandler)
+
+		// Admins grant the staff and admin roles
+		api.PUT("/users/:id/role", auth.AuthMiddleware(), auth.AdminMiddleware(), auth.UpdateUserRoleHandler)
+
+		// Scanners verify e-tickets offline with this key
+		api.GET("/tickets/public-key", booking.TicketPublicKeyHandler)
+
 		// Upload routes
+		api.OPTIONS("/upload/tus", upload.TusOptionsHandler)
 		uploadGroup := api.Group("/upload")
 		{
 			uploadGroup.Use(auth.AuthMiddleware(), auth.AdminMiddleware())
 			uploadGroup.POST("/image", upload.UploadImageHandler)
+			uploadGroup.POST("/tus", upload.TusCreateHandler)
+			uploadGroup.HEAD("/tus/:id", upload.TusHeadHandler)
+			uploadGroup.PATCH("/tus/:id", upload.TusPatchHandler)
+			uploadGroup.DELETE("/tus/:id", upload.TusDeleteHandler)
+			uploadGroup.GET("/tus/:id", upload.GetUploadHandler)
 		}
 	}
 
-	// Start server
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
-	}
+	// Deliver queued notifications
+	notification.StartDispatcher(30 * time.Second)
+	event.StartScheduler(time.Minute)
+	booking.StartPaymentReaper(time.Minute)
+	booking.StartHoldReaper(30 * time.Second)
 
+	// Remove abandoned resumable uploads
+	upload.StartExpiredUploadCleaner(time.Hour)
+	idempotency.StartCleaner(time.Hour)
+
+	// Start server
 	if err := r.Run(":" + port); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
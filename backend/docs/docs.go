@@ -22,9 +22,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
+        "/admin/bookings/{id}/cancel": {
             "post": {
-                "description": "Authenticate user and return JWT token",
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Cancel any pending or confirmed booking and release its tickets (admin only). Paid bookings are refunded refundPercent of their total, in full by default, and the holder is notified with the reason.",
                 "consumes": [
                     "application/json"
                 ],
@@ -32,17 +37,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "Login user",
+                "summary": "Cancel a booking as an admin",
                 "parameters": [
                     {
-                        "description": "Login credentials",
+                        "type": "string",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason and refund",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.LoginRequest"
+                            "$ref": "#/definitions/models.AdminCancelBookingRequest"
                         }
                     }
                 ],
@@ -50,15 +61,32 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.AuthResponse"
+                            "$ref": "#/definitions/models.AdminBooking"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/auth/register": {
+        "/admin/bookings/{id}/move": {
             "post": {
-                "description": "Register a new user and return JWT token",
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Move a pending or confirmed booking to another event, or another ticket type of its event, keeping its price and attendees (admin only). The tickets come out of the target's capacity and quota regardless of its sales window, and events with a seat map need seats, one per ticket. The old tickets stop admitting and the holder, or each attendee, is sent the new ones.",
                 "consumes": [
                     "application/json"
                 ],
@@ -66,103 +94,209 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "Register a new user",
+                "summary": "Move a booking",
                 "parameters": [
                     {
-                        "description": "Register credentials",
+                        "type": "string",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Target event",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.RegisterRequest"
+                            "$ref": "#/definitions/models.MoveBookingRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.AuthResponse"
+                            "$ref": "#/definitions/models.AdminBooking"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.CodedErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/bookings": {
-            "post": {
+        "/admin/events/{id}/bookings": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Book an event for the authenticated user",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "List the bookings of an event, newest first, with who holds them (admin only). Filter by status and search with q for a booking ID or part of the name or email of the holder or an attendee.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "bookings"
+                    "admin"
                 ],
-                "summary": "Create a booking",
+                "summary": "List an event's bookings",
                 "parameters": [
                     {
-                        "description": "Booking details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateBookingRequest"
-                        }
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending_payment",
+                            "confirmed",
+                            "cancelled",
+                            "attended"
+                        ],
+                        "type": "string",
+                        "description": "Booking status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search text",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page, from 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Bookings per page, up to 200 (default 50)",
+                        "name": "pageSize",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.BookingResponse"
+                            "$ref": "#/definitions/models.AdminBookingList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/bookings/user": {
+        "/admin/events/{id}/bookings/export": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get all bookings for the authenticated user",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Download the attendees of an event as CSV or XLSX (admin only): a row for each attendee of a group booking and one for the holder of any other booking. Cancelled bookings are left out unless asked for by status; q searches as in the booking list.",
                 "produces": [
-                    "application/json"
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
                 ],
                 "tags": [
-                    "bookings"
+                    "admin"
+                ],
+                "summary": "Export an event's attendees",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "description": "File format (default csv)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pending_payment",
+                            "confirmed",
+                            "cancelled",
+                            "attended"
+                        ],
+                        "type": "string",
+                        "description": "Booking status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search text",
+                        "name": "q",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get user bookings",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.BookingResponse"
-                            }
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/events": {
-            "get": {
-                "description": "Get a list of all events",
+        "/auth/login": {
+            "post": {
+                "description": "Authenticate user and return JWT token",
                 "consumes": [
                     "application/json"
                 ],
@@ -170,28 +304,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "events"
+                    "auth"
+                ],
+                "summary": "Login user",
+                "parameters": [
+                    {
+                        "description": "Login credentials",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginRequest"
+                        }
+                    }
                 ],
-                "summary": "Get all events",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Event"
-                            }
+                            "$ref": "#/definitions/models.AuthResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/register": {
             "post": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Create a new event (admin only)",
+                "description": "Register a new user and return JWT token. Registering from a guest booking's magic link, with its guestToken, proves the email is the guest's, so every guest booking made with that email joins the new account.",
                 "consumes": [
                     "application/json"
                 ],
@@ -199,17 +338,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "events"
+                    "auth"
                 ],
-                "summary": "Create a new event",
+                "summary": "Register a new user",
                 "parameters": [
                     {
-                        "description": "Event details",
+                        "description": "Register credentials",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateEventRequest"
+                            "$ref": "#/definitions/models.RegisterRequest"
                         }
                     }
                 ],
@@ -217,15 +356,20 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Event"
+                            "$ref": "#/definitions/models.AuthResponse"
                         }
                     }
                 }
             }
         },
-        "/events/{id}": {
-            "get": {
-                "description": "Get details of a specific event",
+        "/bookings": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Book one or more tickets of an event for the authenticated user. Events with ticket types need a ticketTypeId. Bookings outside the event's or ticket type's sales window or for past events are rejected with a code of sales_not_started, sales_ended or event_passed, and bookings beyond the ticket type's quota or the event's capacity with sold_out. A promoCode is redeemed atomically with the booking; see the quote endpoint for its errors. A holdId books the tickets of the user's seat hold, failing with code hold_expired once it has expired or been used. Events with a seat map need seats, one per ticket and in the ticket type's zone, and fail with code seat_taken if one is held or booked. Bookings with a price are created as pending_payment with a payment intent and are released if not paid in time.",
                 "consumes": [
                     "application/json"
                 ],
@@ -233,34 +377,56 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "events"
+                    "bookings"
                 ],
-                "summary": "Get event by ID",
+                "summary": "Create a booking",
                 "parameters": [
+                    {
+                        "description": "Booking details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateBookingRequest"
+                        }
+                    },
                     {
                         "type": "string",
-                        "description": "Event ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Unique key that makes a retry of the request return its first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Event"
+                            "$ref": "#/definitions/models.BookingResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.CodedErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/bookings/holds": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Update an existing event (admin only)",
+                "description": "Set tickets aside for the authenticated user while they check out. The hold keeps its tickets for HOLD_TTL_MINUTES (default 10); book them by passing its id as holdId when creating the booking. Holds count against the event's capacity, the ticket type's quota and the user's ticket limit, and are refused with the booking errors of POST /bookings. At events with a seat map the hold keeps the chosen seats.",
                 "consumes": [
                     "application/json"
                 ],
@@ -268,97 +434,102 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "events"
+                    "bookings"
                 ],
-                "summary": "Update an event",
+                "summary": "Hold tickets",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Event ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Event details",
+                        "description": "Tickets to hold",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateEventRequest"
+                            "$ref": "#/definitions/models.CreateHoldRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key that makes a retry of the request return its first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Event"
+                            "$ref": "#/definitions/models.SeatHold"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.CodedErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.CodedErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/bookings/holds/{id}": {
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Delete an existing event (admin only)",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Give the tickets of one of the authenticated user's active seat holds back before it expires",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "events"
+                    "bookings"
                 ],
-                "summary": "Delete an event",
+                "summary": "Release a seat hold",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Event ID",
+                        "description": "Hold ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
-                "responses": {}
-            }
-        },
-        "/tags": {
-            "get": {
-                "description": "Get a list of all tags",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "tags"
-                ],
-                "summary": "Get all tags",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Tag"
-                            }
+                            "$ref": "#/definitions/models.SeatHold"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.CodedErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/bookings/quote": {
             "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Create a new tag (admin only)",
+                "description": "Preview the price of a booking, with the discount of an optional promo code, without booking. Promo codes that are unknown or outside their validity window are rejected with code promo_invalid, codes for other events or currencies with promo_not_applicable and used-up codes with promo_exhausted.",
                 "consumes": [
                     "application/json"
                 ],
@@ -366,33 +537,50 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tags"
+                    "bookings"
                 ],
-                "summary": "Create a new tag",
+                "summary": "Quote a booking",
                 "parameters": [
                     {
-                        "description": "Tag details",
+                        "description": "Booking details",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateTagRequest"
+                            "$ref": "#/definitions/models.CreateBookingRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Tag"
+                            "$ref": "#/definitions/models.BookingQuote"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.CodedErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.CodedErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/tags/{id}": {
+        "/bookings/user": {
             "get": {
-                "description": "Get details of a specific tag",
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get all bookings for the authenticated user",
                 "consumes": [
                     "application/json"
                 ],
@@ -400,34 +588,74 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tags"
+                    "bookings"
                 ],
-                "summary": "Get tag by ID",
+                "summary": "Get user bookings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.BookingResponse"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/bookings/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get a booking of the authenticated user, or a guest booking with the token from its magic link",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Get a booking",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Tag ID",
+                        "description": "Booking ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Guest booking token, also accepted in the X-Booking-Token header",
+                        "name": "token",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Tag"
+                            "$ref": "#/definitions/models.BookingResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             },
-            "put": {
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Update an existing tag (admin only)",
+                "description": "Cancel a booking of the authenticated user, or a guest booking with the token from its magic link, and release its tickets. Paid bookings are refunded according to the event's cancellation policy, by default in full up to 7 days before the event, half up to 48 hours before and nothing after. The refund is listed on the booking. Bookings cannot be cancelled once the event has started.",
                 "consumes": [
                     "application/json"
                 ],
@@ -435,43 +663,60 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tags"
+                    "bookings"
                 ],
-                "summary": "Update a tag",
+                "summary": "Cancel a booking",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Tag ID",
+                        "description": "Booking ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Tag details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateTagRequest"
-                        }
+                        "type": "string",
+                        "description": "Guest booking token, also accepted in the X-Booking-Token header",
+                        "name": "token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key that makes a retry of the request return its first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Tag"
+                            "$ref": "#/definitions/models.BookingResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.CodedErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/bookings/{id}/pay": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Delete an existing tag (admin only)",
+                "description": "Charge a payment method for a booking awaiting payment. The booking is confirmed once the provider reports the payment by webhook.",
                 "consumes": [
                     "application/json"
                 ],
@@ -479,217 +724,5340 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tags"
+                    "bookings"
                 ],
-                "summary": "Delete a tag",
+                "summary": "Pay for a booking",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Tag ID",
+                        "description": "Booking ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Payment method",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.PayBookingRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Guest booking token, also accepted in the X-Booking-Token header",
+                        "name": "token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key that makes a retry of the request return its first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/models.SuccessResponse"
+                            "$ref": "#/definitions/models.BookingResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/upload/image": {
-            "post": {
+        "/bookings/{id}/ticket.pdf": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Upload an image file (max 3MB, formats: jpg, jpeg, png, gif)",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+                "description": "Get the e-ticket of a confirmed booking of the authenticated user as a PDF with the event details and QR code. Group bookings need the attendee whose ticket to get.",
                 "produces": [
-                    "application/json"
+                    "application/pdf"
                 ],
                 "tags": [
-                    "upload"
+                    "bookings"
                 ],
-                "summary": "Upload an image",
+                "summary": "Get a printable ticket",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "Image file",
-                        "name": "image",
-                        "in": "formData",
+                        "type": "string",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Attendee ID, for group bookings",
+                        "name": "attendee",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Guest booking token, also accepted in the X-Booking-Token header",
+                        "name": "token",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/upload.UploadResponse"
+                            "type": "file"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/upload.ErrorResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
-                            "$ref": "#/definitions/upload.ErrorResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.AuthResponse": {
-            "type": "object",
-            "properties": {
-                "token": {
-                    "type": "string"
-                },
-                "user": {
-                    "$ref": "#/definitions/models.User"
-                }
-            }
         },
-        "models.BookingResponse": {
-            "type": "object",
-            "properties": {
-                "createdAt": {
-                    "type": "string"
-                },
-                "event": {
-                    "$ref": "#/definitions/models.Event"
-                },
-                "eventId": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "userId": {
-                    "type": "string"
+        "/bookings/{id}/ticket.png": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get the e-ticket of a confirmed booking of the authenticated user as a QR code. The code holds the signed ticket token also returned as the booking's ticket field. Group bookings need the attendee whose ticket to get.",
+                "produces": [
+                    "image/png"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Get a booking's QR code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Attendee ID, for group bookings",
+                        "name": "attendee",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Guest booking token, also accepted in the X-Booking-Token header",
+                        "name": "token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/bookings/{id}/transfer": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Offer a confirmed booking of the authenticated user to the holder of an email address. The recipient is sent a token to accept the transfer with, valid for 72 hours or until the event starts. Starting a new transfer cancels a pending one. Refused with transfers_disabled for events that do not allow transfers, not_transferable for bookings that are not confirmed or have named attendees, and event_passed once the event has started.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "Transfer a ticket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Recipient",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateTransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.TicketTransfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.CodedErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.CodedErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/bookings/{id}/transfers": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "List every transfer of a booking, oldest first, for its current owner or an admin",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "Get a booking's transfer history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TicketTransfer"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/checkin": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Verify a scanned ticket and mark its booking as attended (staff only). Refused tickets return a code: ticket_invalid for bad signatures or unknown bookings, wrong_event for tickets of another event, ticket_superseded for tickets replaced by a transfer, ticket_cancelled, ticket_not_paid, and already_checked_in with when and by whom the ticket was used.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "checkin"
+                ],
+                "summary": "Check in a ticket",
+                "parameters": [
+                    {
+                        "description": "Scanned ticket",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CheckInRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CheckInResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.CheckInErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.CheckInErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events": {
+            "get": {
+                "description": "Get a list of all events",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Get all events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag ID or slug; includes events tagged with any of its descendants",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "draft",
+                            "published",
+                            "cancelled",
+                            "postponed",
+                            "completed"
+                        ],
+                        "type": "string",
+                        "description": "Only events in this status; drafts are only listed for admins",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events still running at or after this RFC 3339 time",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events starting at or before this RFC 3339 time",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "30.0444,31.2357",
+                        "description": "Only events at venues near this lat,lng, nearest first",
+                        "name": "near",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Search radius in km for near (default 25)",
+                        "name": "radius",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Event"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Create a new event (admin only). Events start as drafts unless status is \"published\". With an rrule, one event is created per occurrence and the series is returned instead. Other events overlapping it at the same venue are listed in venueConflicts.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Create a new event",
+                "parameters": [
+                    {
+                        "description": "Event details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateEventRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "When rrule is set",
+                        "schema": {
+                            "$ref": "#/definitions/models.EventSeries"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}": {
+            "get": {
+                "description": "Get details of a specific event. Drafts are only visible to admins.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Get event by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Event"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update an existing event (admin only). For an occurrence of a series only this occurrence changes. Other events overlapping it at the same venue are listed in venueConflicts. The status is changed through the publish, unpublish, cancel and postpone endpoints.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Update an event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Event details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateEventRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Event"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete an existing event with its ticket types, seat holds and own seat map (admin only). Events with any bookings, even cancelled ones, must be cancelled instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Delete an event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Call off an event (admin only). Its bookings are kept but marked cancelled, paid bookings are refunded in full and attendees are notified.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Cancel an event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason shown to attendees",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.EventStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Event"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/checkin/manifest": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get every paid booking of an event, signed with the e-ticket key, for scanners to cache and check tickets without a connection (staff only). Scanners verify the signature of \"manifest:\" followed by the payload with the ticket public key, then decode the base64url JSON payload. Tickets issued after the manifest still verify by their own signature.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "checkin"
+                ],
+                "summary": "Get an event's check-in manifest",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SignedManifest"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/checkin/stats": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get live counts of an event's bookings and tickets and how many have been checked in (staff only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "checkin"
+                ],
+                "summary": "Get check-in statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CheckInStats"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/checkin/sync": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Upload check-ins a scanner recorded offline (staff only). When a ticket was scanned more than once, on any devices or online, the earliest scan wins and ties go to the lower device ID, so every upload order gives the same result. Each scan is reported as accepted, duplicate (naming the winning scan) or rejected with the same codes as online check-in, plus invalid_scan_time for scans dated more than 5 minutes in the future. Re-uploading a batch is safe.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "checkin"
+                ],
+                "summary": "Sync offline check-ins",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Offline scans",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SyncRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SyncResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/complete": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Mark a published event as having taken place (admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Complete an event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Event"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/following": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Apply changes to an occurrence and every later occurrence of its series (admin only). Unless it is the first occurrence, they are split off into a new series so later whole-series edits of the original leave them alone.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Update this and following occurrences",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID of the first occurrence to change",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Event details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateEventRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.EventSeries"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/media": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Set the display order of an event's media. The request must list every media ID of the event exactly once (admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Reorder event media",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Media IDs in display order",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReorderEventMediaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.EventMedia"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Add a gallery image or downloadable attachment to the end of an event's media list (admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Attach media to an event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Media details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AddEventMediaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.EventMedia"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/media/{mediaId}": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update the caption and alt text of an event's media item (admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Update event media",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Media ID",
+                        "name": "mediaId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Media details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateEventMediaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.EventMedia"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Remove a gallery image or attachment from an event (admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Remove media from an event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Media ID",
+                        "name": "mediaId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/postpone": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Mark a published event as postponed until a new date is set and it is published again (admin only). Attendees keep their bookings and are notified.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Postpone an event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason shown to attendees",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.EventStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Event"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/publish": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Make a draft or postponed event public and open for booking (admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Publish an event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Event"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/seat-map": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Give an event a seat map of its own, in place of its venue's (admin only). The map can only be replaced while none of its seats are held or booked.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "seating"
+                ],
+                "summary": "Set an event's seat map",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Seat map",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SeatMapRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SeatMap"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Remove an event's own seat map while none of its seats are held or booked, falling back to its venue's (admin only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "seating"
+                ],
+                "summary": "Delete an event's seat map",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/seats": {
+            "get": {
+                "description": "Get every seat of the event's seat map with whether it is available, held during someone's checkout or booked",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "seating"
+                ],
+                "summary": "Get seat availability",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SeatAvailability"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/ticket-types": {
+            "get": {
+                "description": "Get the ticket types of an event with their prices and how many are sold",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "List ticket types",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TicketType"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Add a ticket type such as early-bird or VIP to an event (admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Add a ticket type",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Ticket type details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateTicketTypeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.TicketType"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/ticket-types/{typeId}": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update a ticket type of an event (admin only). The quota cannot be lowered below the number already sold.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Update a ticket type",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ticket type ID",
+                        "name": "typeId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Ticket type details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateTicketTypeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TicketType"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Remove a ticket type from an event (admin only). Ticket types with sold tickets cannot be deleted; set their quota to what was sold to stop sales instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Delete a ticket type",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ticket type ID",
+                        "name": "typeId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/unpublish": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Move a published event back to draft, hiding it from public listings (admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Unpublish an event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Event"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/guest/bookings": {
+            "post": {
+                "description": "Book tickets without an account, with only a name and email. Booking rules and errors are those of POST /bookings; an email that belongs to an account is rejected with code account_exists. The guest is emailed a magic link to view, pay for or cancel the booking, and the response carries its guestToken so the booking can be paid straight away. Registering from the link with its token moves the guest's bookings to the new account.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "guest"
+                ],
+                "summary": "Book as a guest",
+                "parameters": [
+                    {
+                        "description": "Guest and booking details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.GuestBookingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.BookingResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.CodedErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/guest/bookings/link": {
+            "post": {
+                "description": "Email fresh magic links to the active guest bookings made with an email. Links sent before stop working. The response is the same whether or not there are any bookings.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "guest"
+                ],
+                "summary": "Resend guest booking links",
+                "parameters": [
+                    {
+                        "description": "Guest email",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.GuestLinkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/guest/bookings/quote": {
+            "post": {
+                "description": "Preview the price of a guest booking, with the errors of POST /guest/bookings",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "guest"
+                ],
+                "summary": "Quote a guest booking",
+                "parameters": [
+                    {
+                        "description": "Guest and booking details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.GuestBookingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BookingQuote"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.CodedErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.CodedErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/guest/bookings/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get a booking of the authenticated user, or a guest booking with the token from its magic link",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Get a booking",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Guest booking token, also accepted in the X-Booking-Token header",
+                        "name": "token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BookingResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Cancel a booking of the authenticated user, or a guest booking with the token from its magic link, and release its tickets. Paid bookings are refunded according to the event's cancellation policy, by default in full up to 7 days before the event, half up to 48 hours before and nothing after. The refund is listed on the booking. Bookings cannot be cancelled once the event has started.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Cancel a booking",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Guest booking token, also accepted in the X-Booking-Token header",
+                        "name": "token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key that makes a retry of the request return its first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BookingResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.CodedErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/guest/bookings/{id}/pay": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Charge a payment method for a booking awaiting payment. The booking is confirmed once the provider reports the payment by webhook.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Pay for a booking",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payment method",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.PayBookingRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Guest booking token, also accepted in the X-Booking-Token header",
+                        "name": "token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key that makes a retry of the request return its first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.BookingResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/guest/bookings/{id}/ticket.pdf": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get the e-ticket of a confirmed booking of the authenticated user as a PDF with the event details and QR code. Group bookings need the attendee whose ticket to get.",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Get a printable ticket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Attendee ID, for group bookings",
+                        "name": "attendee",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Guest booking token, also accepted in the X-Booking-Token header",
+                        "name": "token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/guest/bookings/{id}/ticket.png": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get the e-ticket of a confirmed booking of the authenticated user as a QR code. The code holds the signed ticket token also returned as the booking's ticket field. Group bookings need the attendee whose ticket to get.",
+                "produces": [
+                    "image/png"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Get a booking's QR code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Attendee ID, for group bookings",
+                        "name": "attendee",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Guest booking token, also accepted in the X-Booking-Token header",
+                        "name": "token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/webhook": {
+            "post": {
+                "description": "Receives signed payment notifications from the payment provider. A succeeded payment confirms its pending booking; payments for bookings that were already released are refunded.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Payment webhook",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/promo-codes": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get all promo codes with their usage, newest first (admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promo-codes"
+                ],
+                "summary": "Get all promo codes",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PromoCode"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Create a percentage or fixed-amount discount code (admin only). Codes are stored in upper case and matched ignoring case. Leaving eventIds and tagIds empty makes the code valid for every event.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promo-codes"
+                ],
+                "summary": "Create a promo code",
+                "parameters": [
+                    {
+                        "description": "Promo code details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PromoCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.PromoCode"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/promo-codes/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update an existing promo code (admin only). Its use count is kept.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promo-codes"
+                ],
+                "summary": "Update a promo code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Promo code ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Promo code details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PromoCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PromoCode"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a promo code (admin only). Bookings made with it keep their discount.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promo-codes"
+                ],
+                "summary": "Delete a promo code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Promo code ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/{id}": {
+            "get": {
+                "description": "Get a recurring series with its occurrences in date order. Draft occurrences are only listed for admins.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Get an event series",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Series ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.EventSeries"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Apply changes to every occurrence of a series (admin only). The request's date and endDate are for the first occurrence; the others move by the same number of days and take the same local start time and duration. Cancelled and completed occurrences are not changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Update a whole series",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Series ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Event details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateEventRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.EventSeries"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "description": "Get a flat list of all tags, optionally with the number of events using each",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Get all tags",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Include eventCount for each tag",
+                        "name": "withCounts",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TagWithCount"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Create a new tag, optionally below a parent tag (admin only). Names are unique ignoring case.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Create a new tag",
+                "parameters": [
+                    {
+                        "description": "Tag details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tag"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/autocomplete": {
+            "get": {
+                "description": "Find tags whose name starts with the given prefix, most used first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Autocomplete tags",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name prefix",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of results (default 10, max 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TagWithCount"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/tree": {
+            "get": {
+                "description": "Get all tags nested under their parents",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Get tag tree",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Tag"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{id}": {
+            "get": {
+                "description": "Get details of a specific tag by ID or slug",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Get tag by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag ID or slug",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tag"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Rename a tag or move it below another parent (admin only). The slug follows the new name.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Update a tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tag details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tag"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete an existing tag (admin only). Its children move up to the deleted tag's parent. A tag that is still used by events is only deleted with force=true; consider merging it instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Delete a tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Unlink the tag from its events and delete it anyway",
+                        "name": "force",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.TagWithCount"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{id}/merge": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Move every event of a tag to the target tag and delete it, in one transaction (admin only). Child tags move below the target.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Merge tags",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the tag to merge away",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Target tag",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MergeTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MergeTagResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tickets/public-key": {
+            "get": {
+                "description": "Get the Ed25519 public key that verifies ticket tokens, so scanners can check tickets offline. A token is the base64url JSON claims and the base64url signature of those claims joined by a dot. Signatures cover a context prefix followed by the base64url payload: \"ticket:\" for ticket tokens and \"manifest:\" for check-in manifests, so one is never accepted as the other.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Get the ticket verification key",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TicketKeyResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "List the transfers the authenticated user sent or was sent, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "List my ticket transfers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TicketTransfer"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers/accept": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Take over the booking of a transfer sent to the authenticated user's email address. Ownership moves in one step and the booking gets a new ticket, so the previous owner's ticket no longer admits anyone. Refused with transfers_disabled if the event no longer allows transfers, transfer_expired after the deadline or once the event has started, and transfer_closed if the transfer was cancelled or already accepted, the event is no longer published or the booking can no longer be transferred.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "Accept a ticket transfer",
+                "parameters": [
+                    {
+                        "description": "Transfer token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AcceptTransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BookingResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.CodedErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.CodedErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Withdraw a pending transfer started by the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "Cancel a ticket transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TicketTransfer"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.CodedErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/upload/image": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Upload an image file (max 3MB by default, formats: jpg, jpeg, png, gif). Files are scanned for malware before they are published.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "upload"
+                ],
+                "summary": "Upload an image",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Image file",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/upload.UploadResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/upload.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/upload.ScanErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/upload.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/upload.ScanErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/upload/tus": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Start a tus upload. Upload-Metadata must contain a base64 encoded filename; its extension selects the media type and size limit.",
+                "tags": [
+                    "upload"
+                ],
+                "summary": "Create a resumable upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "1.0.0",
+                        "description": "Protocol version",
+                        "name": "Tus-Resumable",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Total size in bytes",
+                        "name": "Upload-Length",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "tus metadata, e.g. filename dmlkZW8ubXA0",
+                        "name": "Upload-Metadata",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/upload.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/upload.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Report the supported tus version, extensions and maximum upload size",
+                "tags": [
+                    "upload"
+                ],
+                "summary": "Resumable upload capabilities",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/upload/tus/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get progress of a tus upload and the URL of the stored file once complete",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "upload"
+                ],
+                "summary": "Get resumable upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Upload ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Upload"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/upload.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Discard an incomplete tus upload and its data",
+                "tags": [
+                    "upload"
+                ],
+                "summary": "Terminate a resumable upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Upload ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "1.0.0",
+                        "description": "Protocol version",
+                        "name": "Tus-Resumable",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/upload.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "head": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Return the current offset of a tus upload in the Upload-Offset header",
+                "tags": [
+                    "upload"
+                ],
+                "summary": "Resumable upload status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Upload ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "1.0.0",
+                        "description": "Protocol version",
+                        "name": "Tus-Resumable",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/upload.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {
+                            "$ref": "#/definitions/upload.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Write the request body at Upload-Offset. Once all bytes have arrived the file is validated and scanned, then published or quarantined. A file that no longer passes validation, e.g. after its size limit was lowered, is refused with 400 or 413 and the upload discarded.",
+                "consumes": [
+                    "application/offset+octet-stream"
+                ],
+                "tags": [
+                    "upload"
+                ],
+                "summary": "Append to a resumable upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Upload ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "1.0.0",
+                        "description": "Protocol version",
+                        "name": "Tus-Resumable",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset the body starts at",
+                        "name": "Upload-Offset",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/upload.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/upload.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/upload.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {
+                            "$ref": "#/definitions/upload.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/upload.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/upload.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/upload.ScanErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/upload.ScanErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/role": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Make a user door staff, an admin or a regular user again (admin only). The new role applies from the user's next login.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Change a user's role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New role",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/venues": {
+            "get": {
+                "description": "Get a list of all venues ordered by name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "venues"
+                ],
+                "summary": "Get all venues",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Venue"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Create a new venue (admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "venues"
+                ],
+                "summary": "Create a new venue",
+                "parameters": [
+                    {
+                        "description": "Venue details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateVenueRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Venue"
+                        }
+                    }
+                }
+            }
+        },
+        "/venues/{id}": {
+            "get": {
+                "description": "Get details of a specific venue",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "venues"
+                ],
+                "summary": "Get venue by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Venue ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Venue"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update an existing venue (admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "venues"
+                ],
+                "summary": "Update a venue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Venue ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Venue details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateVenueRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Venue"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete an existing venue (admin only). Venues that events are still held at cannot be deleted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "venues"
+                ],
+                "summary": "Delete a venue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Venue ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/venues/{id}/seat-map": {
+            "get": {
+                "description": "Get the seat map of a venue with its seats in order",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "seating"
+                ],
+                "summary": "Get a venue's seat map",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Venue ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SeatMap"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Define the sections, rows and seats of a venue, with accessible seats and price zones (admin only). Events at the venue sell reserved seats from it unless they have their own map. The map can only be replaced while none of its seats are held or booked.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "seating"
+                ],
+                "summary": "Set a venue's seat map",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Venue ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Seat map",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SeatMapRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SeatMap"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Remove the seat map of a venue while none of its seats are held or booked (admin only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "seating"
+                ],
+                "summary": "Delete a venue's seat map",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Venue ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.AcceptTransferRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AddEventMediaRequest": {
+            "type": "object",
+            "required": [
+                "type",
+                "url"
+            ],
+            "properties": {
+                "altText": {
+                    "type": "string",
+                    "example": "Crowd in front of the main stage"
+                },
+                "caption": {
+                    "type": "string",
+                    "example": "Main stage"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "image",
+                        "attachment"
+                    ],
+                    "example": "image"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "/uploads/images/1710928800000000000.jpg"
+                }
+            }
+        },
+        "models.AdminBooking": {
+            "type": "object",
+            "properties": {
+                "attendees": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AttendeeResponse"
+                    }
+                },
+                "cancelledAt": {
+                    "type": "string"
+                },
+                "checkedInAt": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "discount": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "event": {
+                    "$ref": "#/definitions/models.Event"
+                },
+                "eventId": {
+                    "type": "string"
+                },
+                "guestEmail": {
+                    "type": "string"
+                },
+                "guestName": {
+                    "type": "string"
+                },
+                "guestToken": {
+                    "description": "GuestToken opens a new guest booking, as the link emailed to the\nguest does. It is only returned when the booking is created.",
+                    "type": "string",
+                    "example": "q8J2nD4xY0..."
+                },
+                "holderEmail": {
+                    "type": "string",
+                    "example": "ada@example.com"
+                },
+                "holderName": {
+                    "type": "string",
+                    "example": "Ada Lovelace"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "paidAt": {
+                    "type": "string"
+                },
+                "payment": {
+                    "description": "Payment is included while the booking awaits payment",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/payment.Intent"
+                        }
+                    ]
+                },
+                "paymentExpiresAt": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "refunds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Refund"
+                    }
+                },
+                "seats": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Seat"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "ticket": {
+                    "description": "Ticket is the signed e-ticket token of a confirmed booking, as encoded\nin its QR code. Group bookings have a ticket per attendee instead.",
+                    "type": "string",
+                    "example": "eyJiIjoiNmY...In0.kq3v..."
+                },
+                "ticketType": {
+                    "$ref": "#/definitions/models.TicketType"
+                },
+                "total": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "unitPrice": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AdminBookingList": {
+            "type": "object",
+            "properties": {
+                "bookings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AdminBooking"
+                    }
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "pageSize": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 120
+                }
+            }
+        },
+        "models.AdminCancelBookingRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "Duplicate booking"
+                },
+                "refundPercent": {
+                    "description": "RefundPercent of a paid booking's total is refunded, all of it by\ndefault",
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0,
+                    "example": 100
+                }
+            }
+        },
+        "models.AttendeeRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "name"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "grace@example.com"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Grace Hopper"
+                }
+            }
+        },
+        "models.AttendeeResponse": {
+            "type": "object",
+            "properties": {
+                "checkedInAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-25T18:45:00Z"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "grace@example.com"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Grace Hopper"
+                },
+                "ticket": {
+                    "description": "Ticket is the attendee's signed e-ticket token once the booking is\nconfirmed",
+                    "type": "string",
+                    "example": "eyJhIjoiM2Q...In0.Zx8p..."
+                }
+            }
+        },
+        "models.AuthResponse": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/models.User"
+                }
+            }
+        },
+        "models.BookingQuote": {
+            "type": "object",
+            "properties": {
+                "discount": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "eventId": {
+                    "type": "string"
+                },
+                "promoCode": {
+                    "type": "string",
+                    "example": "SPRING25"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "subtotal": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "ticketTypeId": {
+                    "type": "string"
+                },
+                "total": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "unitPrice": {
+                    "$ref": "#/definitions/money.Money"
+                }
+            }
+        },
+        "models.BookingResponse": {
+            "type": "object",
+            "properties": {
+                "attendees": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AttendeeResponse"
+                    }
+                },
+                "cancelledAt": {
+                    "type": "string"
+                },
+                "checkedInAt": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "discount": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "event": {
+                    "$ref": "#/definitions/models.Event"
+                },
+                "eventId": {
+                    "type": "string"
+                },
+                "guestEmail": {
+                    "type": "string"
+                },
+                "guestName": {
+                    "type": "string"
+                },
+                "guestToken": {
+                    "description": "GuestToken opens a new guest booking, as the link emailed to the\nguest does. It is only returned when the booking is created.",
+                    "type": "string",
+                    "example": "q8J2nD4xY0..."
+                },
+                "id": {
+                    "type": "string"
+                },
+                "paidAt": {
+                    "type": "string"
+                },
+                "payment": {
+                    "description": "Payment is included while the booking awaits payment",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/payment.Intent"
+                        }
+                    ]
+                },
+                "paymentExpiresAt": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "refunds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Refund"
+                    }
+                },
+                "seats": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Seat"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "ticket": {
+                    "description": "Ticket is the signed e-ticket token of a confirmed booking, as encoded\nin its QR code. Group bookings have a ticket per attendee instead.",
+                    "type": "string",
+                    "example": "eyJiIjoiNmY...In0.kq3v..."
+                },
+                "ticketType": {
+                    "$ref": "#/definitions/models.TicketType"
+                },
+                "total": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "unitPrice": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CancellationRule": {
+            "type": "object",
+            "properties": {
+                "hoursBefore": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 168
+                },
+                "refundPercent": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0,
+                    "example": 100
+                }
+            }
+        },
+        "models.CheckInErrorResponse": {
+            "type": "object",
+            "properties": {
+                "checkedInAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-25T18:45:00Z"
+                },
+                "checkedInBy": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string",
+                    "example": "already_checked_in"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "Ticket has already been used"
+                }
+            }
+        },
+        "models.CheckInRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "eventId": {
+                    "description": "EventID, when given, rejects tickets for other events",
+                    "type": "string"
+                },
+                "token": {
+                    "description": "Token is the content of the ticket's QR code",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CheckInResponse": {
+            "type": "object",
+            "properties": {
+                "attendee": {
+                    "type": "string",
+                    "example": "johndoe"
+                },
+                "attendeeId": {
+                    "description": "AttendeeID is set when the ticket of a group booking's attendee was\nscanned; Attendee is then their name",
+                    "type": "string"
+                },
+                "bookingId": {
+                    "type": "string"
+                },
+                "checkedInAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-25T18:45:00Z"
+                },
+                "checkedInBy": {
+                    "type": "string"
+                },
+                "eventId": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "ticketType": {
+                    "type": "string",
+                    "example": "VIP"
+                }
+            }
+        },
+        "models.CheckInStats": {
+            "type": "object",
+            "properties": {
+                "bookings": {
+                    "description": "Bookings and Tickets count confirmed and attended bookings",
+                    "type": "integer",
+                    "example": 120
+                },
+                "checkedInBookings": {
+                    "type": "integer",
+                    "example": 80
+                },
+                "checkedInTickets": {
+                    "type": "integer",
+                    "example": 170
+                },
+                "eventId": {
+                    "type": "string"
+                },
+                "lastCheckInAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-25T18:45:00Z"
+                },
+                "remainingTickets": {
+                    "type": "integer",
+                    "example": 80
+                },
+                "tickets": {
+                    "type": "integer",
+                    "example": 250
+                }
+            }
+        },
+        "models.CodedErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "sales_not_started"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "Ticket sales have not started yet"
                 }
             }
         },
         "models.CreateBookingRequest": {
             "type": "object",
             "required": [
-                "eventId"
+                "eventId"
+            ],
+            "properties": {
+                "attendees": {
+                    "description": "Attendees, when given, name the holder of every ticket; each gets\ntheir own ticket instead of one for the whole booking",
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "$ref": "#/definitions/models.AttendeeRequest"
+                    }
+                },
+                "eventId": {
+                    "type": "string"
+                },
+                "holdId": {
+                    "description": "HoldID books the tickets of a seat hold of the user, which must be\nfor the same ticket type and quantity",
+                    "type": "string"
+                },
+                "promoCode": {
+                    "type": "string",
+                    "example": "SPRING25"
+                },
+                "quantity": {
+                    "description": "Quantity defaults to the number of attendees, or 1. The event's\nmaxTicketsPerOrder limits it.",
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1,
+                    "example": 2
+                },
+                "seats": {
+                    "description": "Seats are the seat IDs to book at events with a seat map, one per\nticket",
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "ticketTypeId": {
+                    "description": "TicketTypeID is required for events that sell ticket types",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateEventRequest": {
+            "type": "object",
+            "required": [
+                "category",
+                "date",
+                "description",
+                "endDate",
+                "image",
+                "name",
+                "price"
+            ],
+            "properties": {
+                "cancellationPolicy": {
+                    "description": "CancellationPolicy replaces the default refund rules for this event",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CancellationRule"
+                    }
+                },
+                "capacity": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 50
+                },
+                "category": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T15:00:00Z"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "endDate": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T18:00:00Z"
+                },
+                "exdates": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "image": {
+                    "type": "string"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "maxTicketsPerOrder": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 10
+                },
+                "maxTicketsPerUser": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 4
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "publishAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-01T09:00:00Z"
+                },
+                "rrule": {
+                    "description": "RRule makes the request create a recurring series (RFC 5545, e.g.\nFREQ=WEEKLY;BYDAY=TU;COUNT=10) whose first occurrence is Date to EndDate",
+                    "type": "string",
+                    "example": "FREQ=WEEKLY;BYDAY=TU;COUNT=10"
+                },
+                "salesEndAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T12:00:00Z"
+                },
+                "salesStartAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-05T09:00:00Z"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "published"
+                    ],
+                    "example": "draft"
+                },
+                "tagIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "timeZone": {
+                    "type": "string",
+                    "example": "Europe/Berlin"
+                },
+                "transfersDisabled": {
+                    "type": "boolean"
+                },
+                "venueId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateHoldRequest": {
+            "type": "object",
+            "required": [
+                "eventId"
+            ],
+            "properties": {
+                "eventId": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1,
+                    "example": 2
+                },
+                "seats": {
+                    "description": "Seats are the seat IDs to hold at events with a seat map",
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "ticketTypeId": {
+                    "description": "TicketTypeID is required for events that sell ticket types",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateTagRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "conference"
+                },
+                "parentId": {
+                    "type": "string",
+                    "example": "5f0c7c3e-8a52-4bde-9a55-0d1f6b1c2a10"
+                }
+            }
+        },
+        "models.CreateTicketTypeRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "price"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Limited discounted tickets"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Early bird"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "price": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "quota": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 100
+                },
+                "salesEndAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-10T23:59:00Z"
+                },
+                "salesStartAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-01T09:00:00Z"
+                },
+                "zone": {
+                    "type": "string",
+                    "example": "premium"
+                }
+            }
+        },
+        "models.CreateTransferRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "colleague@example.com"
+                }
+            }
+        },
+        "models.CreateVenueRequest": {
+            "type": "object",
+            "required": [
+                "address",
+                "latitude",
+                "longitude",
+                "name"
+            ],
+            "properties": {
+                "accessibility": {
+                    "type": "string",
+                    "example": "Step-free entrance, accessible toilets"
+                },
+                "address": {
+                    "type": "string",
+                    "example": "El Borg, Zamalek, Cairo"
+                },
+                "defaultCapacity": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1200
+                },
+                "latitude": {
+                    "type": "number",
+                    "maximum": 90,
+                    "minimum": -90,
+                    "example": 30.0425
+                },
+                "longitude": {
+                    "type": "number",
+                    "maximum": 180,
+                    "minimum": -180,
+                    "example": 31.2243
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Cairo Opera House"
+                },
+                "photos": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "/uploads/images/1710928800000000000.jpg"
+                    ]
+                }
+            }
+        },
+        "models.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "error message"
+                }
+            }
+        },
+        "models.Event": {
+            "type": "object",
+            "properties": {
+                "cancellationPolicy": {
+                    "description": "CancellationPolicy decides refunds for cancelled bookings;\nDefaultCancellationPolicy applies when it is empty",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CancellationRule"
+                    }
+                },
+                "capacity": {
+                    "description": "Capacity is the number of bookings the event accepts; 0 means unlimited",
+                    "type": "integer",
+                    "example": 50
+                },
+                "category": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T10:00:00Z"
+                },
+                "date": {
+                    "description": "Date is when the event starts. Date and EndDate are stored in UTC;\nTimeZone is used to present them in local time.",
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T15:00:00Z"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "distanceKm": {
+                    "description": "Computed for proximity searches and venue conflict checks, not stored",
+                    "type": "number",
+                    "example": 2.4
+                },
+                "durationMinutes": {
+                    "type": "integer",
+                    "example": 180
+                },
+                "endDate": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T18:00:00Z"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "localDate": {
+                    "description": "Computed after loading from TimeZone, not stored",
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T16:00:00+01:00"
+                },
+                "localEndDate": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T19:00:00+01:00"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "maxTicketsPerOrder": {
+                    "description": "MaxTicketsPerOrder limits a single booking; 0 means\nDefaultMaxTicketsPerOrder. MaxTicketsPerUser limits all of a user's\nactive bookings together; 0 means unlimited.",
+                    "type": "integer",
+                    "example": 10
+                },
+                "maxTicketsPerUser": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "media": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EventMedia"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "publishAt": {
+                    "description": "PublishAt schedules a draft to be published automatically",
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-01T09:00:00Z"
+                },
+                "salesEndAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T12:00:00Z"
+                },
+                "salesStartAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-05T09:00:00Z"
+                },
+                "seriesId": {
+                    "description": "SeriesID links an occurrence to the recurring series it was generated from",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "published"
+                },
+                "statusNote": {
+                    "type": "string",
+                    "example": "Rescheduled due to weather"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Tag"
+                    }
+                },
+                "ticketTypes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TicketType"
+                    }
+                },
+                "timeZone": {
+                    "type": "string",
+                    "example": "Europe/Berlin"
+                },
+                "transfersDisabled": {
+                    "description": "TransfersDisabled stops attendees from passing their tickets on",
+                    "type": "boolean"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T10:00:00Z"
+                },
+                "venue": {
+                    "$ref": "#/definitions/models.Venue"
+                },
+                "venueConflicts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.VenueConflict"
+                    }
+                },
+                "venueId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EventMedia": {
+            "type": "object",
+            "properties": {
+                "altText": {
+                    "type": "string"
+                },
+                "caption": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T10:00:00Z"
+                },
+                "eventId": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "image"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T10:00:00Z"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "/uploads/images/1710928800000000000.jpg"
+                }
+            }
+        },
+        "models.EventSeries": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T10:00:00Z"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Event"
+                    }
+                },
+                "exdates": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "rrule": {
+                    "type": "string",
+                    "example": "FREQ=WEEKLY;BYDAY=TU;COUNT=10"
+                },
+                "timeZone": {
+                    "type": "string",
+                    "example": "Europe/Berlin"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T10:00:00Z"
+                }
+            }
+        },
+        "models.EventStatusRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "example": "Rescheduled due to weather"
+                }
+            }
+        },
+        "models.GuestBookingRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "eventId",
+                "name"
+            ],
+            "properties": {
+                "attendees": {
+                    "description": "Attendees, when given, name the holder of every ticket; each gets\ntheir own ticket instead of one for the whole booking",
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "$ref": "#/definitions/models.AttendeeRequest"
+                    }
+                },
+                "email": {
+                    "type": "string",
+                    "example": "ada@example.com"
+                },
+                "eventId": {
+                    "type": "string"
+                },
+                "holdId": {
+                    "description": "HoldID books the tickets of a seat hold of the user, which must be\nfor the same ticket type and quantity",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Ada Lovelace"
+                },
+                "promoCode": {
+                    "type": "string",
+                    "example": "SPRING25"
+                },
+                "quantity": {
+                    "description": "Quantity defaults to the number of attendees, or 1. The event's\nmaxTicketsPerOrder limits it.",
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1,
+                    "example": 2
+                },
+                "seats": {
+                    "description": "Seats are the seat IDs to book at events with a seat map, one per\nticket",
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "ticketTypeId": {
+                    "description": "TicketTypeID is required for events that sell ticket types",
+                    "type": "string"
+                }
+            }
+        },
+        "models.GuestLinkRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "ada@example.com"
+                }
+            }
+        },
+        "models.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6
+                }
+            }
+        },
+        "models.MergeTagRequest": {
+            "type": "object",
+            "required": [
+                "targetId"
+            ],
+            "properties": {
+                "targetId": {
+                    "type": "string",
+                    "example": "5f0c7c3e-8a52-4bde-9a55-0d1f6b1c2a10"
+                }
+            }
+        },
+        "models.MergeTagResponse": {
+            "type": "object",
+            "properties": {
+                "movedEvents": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "tag": {
+                    "$ref": "#/definitions/models.Tag"
+                }
+            }
+        },
+        "models.MoveBookingRequest": {
+            "type": "object",
+            "required": [
+                "eventId"
+            ],
+            "properties": {
+                "eventId": {
+                    "type": "string"
+                },
+                "seats": {
+                    "description": "Seats are the seat IDs at events with a seat map, one per ticket",
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "ticketTypeId": {
+                    "description": "TicketTypeID is required for events that sell ticket types",
+                    "type": "string"
+                }
+            }
+        },
+        "models.OfflineScan": {
+            "type": "object",
+            "required": [
+                "scannedAt",
+                "token"
+            ],
+            "properties": {
+                "scannedAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-25T18:45:00Z"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PayBookingRequest": {
+            "type": "object",
+            "properties": {
+                "paymentMethod": {
+                    "description": "PaymentMethod is a provider payment method; with the fake provider\n\"fake_card_declined\" simulates a declined card",
+                    "type": "string",
+                    "example": "pm_card_visa"
+                }
+            }
+        },
+        "models.PromoCode": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "amountOff": {
+                    "description": "AmountOff is the discount of fixed codes, taken off the booking total",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/money.Money"
+                        }
+                    ]
+                },
+                "code": {
+                    "description": "Code is what customers enter, stored in upper case",
+                    "type": "string",
+                    "example": "SPRING25"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T10:00:00Z"
+                },
+                "eventIds": {
+                    "description": "EventIDs and TagIDs restrict the code to those events and events with\nthose tags; a code without either applies to every event",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "maxUses": {
+                    "description": "MaxUses and MaxUsesPerUser limit redemptions; 0 means unlimited",
+                    "type": "integer",
+                    "example": 100
+                },
+                "maxUsesPerUser": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "percentOff": {
+                    "description": "PercentOff is the discount of percent codes",
+                    "type": "integer",
+                    "example": 25
+                },
+                "tagIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "example": "percent"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T10:00:00Z"
+                },
+                "uses": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "validFrom": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-01T00:00:00Z"
+                },
+                "validUntil": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-04-01T00:00:00Z"
+                }
+            }
+        },
+        "models.PromoCodeRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "type"
+            ],
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "amountOff": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "code": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "SPRING25"
+                },
+                "eventIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "maxUses": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 100
+                },
+                "maxUsesPerUser": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1
+                },
+                "percentOff": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0,
+                    "example": 25
+                },
+                "tagIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "percent",
+                        "fixed"
+                    ],
+                    "example": "percent"
+                },
+                "validFrom": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-01T00:00:00Z"
+                },
+                "validUntil": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-04-01T00:00:00Z"
+                }
+            }
+        },
+        "models.Refund": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "bookingId": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T10:00:00Z"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "percent": {
+                    "description": "Percent is the share of the booking total refunded under the policy",
+                    "type": "integer",
+                    "example": 50
+                },
+                "providerRefundId": {
+                    "type": "string",
+                    "example": "re_3MtwBwLkdIwHu7ix0Z0T1pCk"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "customer_cancelled"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "succeeded"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T10:00:00Z"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RegisterRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "guestToken": {
+                    "description": "GuestToken is the token of a guest booking's magic link, when the\nguest registers from it",
+                    "type": "string",
+                    "example": "q8J2nD4xY0..."
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6
+                },
+                "username": {
+                    "type": "string",
+                    "minLength": 3
+                }
+            }
+        },
+        "models.ReorderEventMediaRequest": {
+            "type": "object",
+            "required": [
+                "mediaIds"
+            ],
+            "properties": {
+                "mediaIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.Seat": {
+            "type": "object",
+            "properties": {
+                "accessible": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "string",
+                    "example": "12"
+                },
+                "row": {
+                    "type": "string",
+                    "example": "A"
+                },
+                "section": {
+                    "type": "string",
+                    "example": "Stalls"
+                },
+                "zone": {
+                    "description": "Zone is the seat's price zone; ticket types with the same zone sell it",
+                    "type": "string",
+                    "example": "premium"
+                }
+            }
+        },
+        "models.SeatAvailability": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "integer",
+                    "example": 180
+                },
+                "eventId": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Main hall"
+                },
+                "seatMapId": {
+                    "type": "string"
+                },
+                "seats": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SeatState"
+                    }
+                }
+            }
+        },
+        "models.SeatHold": {
+            "type": "object",
+            "properties": {
+                "bookingId": {
+                    "description": "BookingID is the booking that consumed the hold",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T10:00:00Z"
+                },
+                "eventId": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "description": "ExpiresAt is when the hold's tickets are released unless booked",
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T10:10:00Z"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "seats": {
+                    "description": "Seats are the reserved seats the hold keeps, for seated events",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SeatReservation"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "active"
+                },
+                "ticketTypeId": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T10:00:00Z"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SeatMap": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T10:00:00Z"
+                },
+                "eventId": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Main hall"
+                },
+                "seats": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Seat"
+                    }
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T10:00:00Z"
+                },
+                "venueId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SeatMapRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "sections"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Main hall"
+                },
+                "sections": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/models.SeatSectionRequest"
+                    }
+                }
+            }
+        },
+        "models.SeatRequest": {
+            "type": "object",
+            "required": [
+                "number"
             ],
             "properties": {
+                "accessible": {
+                    "type": "boolean"
+                },
+                "number": {
+                    "type": "string",
+                    "example": "12"
+                },
+                "zone": {
+                    "type": "string",
+                    "example": "premium"
+                }
+            }
+        },
+        "models.SeatReservation": {
+            "type": "object",
+            "properties": {
+                "bookingId": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T10:00:00Z"
+                },
                 "eventId": {
                     "type": "string"
+                },
+                "holdId": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "seat": {
+                    "$ref": "#/definitions/models.Seat"
+                },
+                "seatId": {
+                    "type": "string"
                 }
             }
         },
-        "models.CreateEventRequest": {
+        "models.SeatRowRequest": {
+            "type": "object",
+            "required": [
+                "label"
+            ],
+            "properties": {
+                "accessible": {
+                    "type": "boolean"
+                },
+                "count": {
+                    "type": "integer",
+                    "maximum": 500,
+                    "minimum": 1,
+                    "example": 20
+                },
+                "label": {
+                    "type": "string",
+                    "example": "A"
+                },
+                "seats": {
+                    "type": "array",
+                    "maxItems": 500,
+                    "items": {
+                        "$ref": "#/definitions/models.SeatRequest"
+                    }
+                },
+                "zone": {
+                    "type": "string",
+                    "example": "premium"
+                }
+            }
+        },
+        "models.SeatSectionRequest": {
             "type": "object",
             "required": [
-                "category",
-                "date",
-                "description",
-                "image",
-                "location",
                 "name",
-                "price"
+                "rows"
             ],
             "properties": {
-                "category": {
+                "name": {
+                    "type": "string",
+                    "example": "Stalls"
+                },
+                "rows": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/models.SeatRowRequest"
+                    }
+                },
+                "zone": {
+                    "description": "Zone is the price zone of the section's seats unless a row or seat\nsets its own",
+                    "type": "string",
+                    "example": "standard"
+                }
+            }
+        },
+        "models.SeatState": {
+            "type": "object",
+            "properties": {
+                "accessible": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "string",
+                    "example": "12"
+                },
+                "row": {
+                    "type": "string",
+                    "example": "A"
+                },
+                "section": {
+                    "type": "string",
+                    "example": "Stalls"
+                },
+                "state": {
+                    "type": "string",
+                    "example": "available"
+                },
+                "zone": {
+                    "description": "Zone is the seat's price zone; ticket types with the same zone sell it",
+                    "type": "string",
+                    "example": "premium"
+                }
+            }
+        },
+        "models.SignedManifest": {
+            "type": "object",
+            "properties": {
+                "payload": {
+                    "type": "string"
+                },
+                "signature": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SuccessResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "operation successful"
+                }
+            }
+        },
+        "models.SyncRequest": {
+            "type": "object",
+            "required": [
+                "deviceId",
+                "scans"
+            ],
+            "properties": {
+                "deviceId": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "gate-2"
+                },
+                "scans": {
+                    "type": "array",
+                    "maxItems": 500,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/models.OfflineScan"
+                    }
+                }
+            }
+        },
+        "models.SyncResponse": {
+            "type": "object",
+            "properties": {
+                "accepted": {
+                    "type": "integer",
+                    "example": 48
+                },
+                "duplicates": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "rejected": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SyncResult"
+                    }
+                }
+            }
+        },
+        "models.SyncResult": {
+            "type": "object",
+            "properties": {
+                "bookingId": {
+                    "type": "string"
+                },
+                "checkedInAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-25T18:44:10Z"
+                },
+                "code": {
+                    "type": "string",
+                    "example": "already_checked_in"
+                },
+                "deviceId": {
+                    "type": "string",
+                    "example": "gate-1"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "Ticket has already been used"
+                },
+                "result": {
+                    "type": "string",
+                    "example": "duplicate"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Tag": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Tag"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "string"
                 },
-                "date": {
+                "name": {
+                    "type": "string"
+                },
+                "parentId": {
+                    "type": "string"
+                },
+                "slug": {
                     "type": "string",
-                    "format": "date-time",
-                    "example": "2024-03-20T15:00:00Z"
+                    "example": "live-music"
                 },
-                "description": {
+                "updatedAt": {
                     "type": "string"
+                }
+            }
+        },
+        "models.TagWithCount": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Tag"
+                    }
                 },
-                "image": {
+                "createdAt": {
                     "type": "string"
                 },
-                "location": {
+                "eventCount": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "id": {
                     "type": "string"
                 },
                 "name": {
                     "type": "string"
                 },
-                "price": {
-                    "type": "number",
-                    "minimum": 0
+                "parentId": {
+                    "type": "string"
                 },
-                "tagIds": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "slug": {
+                    "type": "string",
+                    "example": "live-music"
+                },
+                "updatedAt": {
+                    "type": "string"
                 }
             }
         },
-        "models.CreateTagRequest": {
+        "models.TicketKeyResponse": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
-                "name": {
+                "algorithm": {
                     "type": "string",
-                    "example": "conference"
+                    "example": "Ed25519"
+                },
+                "publicKey": {
+                    "description": "PublicKey is the base64 encoded raw public key",
+                    "type": "string",
+                    "example": "MCowBQYDK2VwAyEA..."
                 }
             }
         },
-        "models.Event": {
+        "models.TicketTransfer": {
             "type": "object",
             "properties": {
-                "category": {
+                "acceptedAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-21T10:00:00Z"
+                },
+                "bookingId": {
                     "type": "string"
                 },
+                "cancelledAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-21T10:00:00Z"
+                },
                 "createdAt": {
                     "type": "string",
                     "format": "date-time",
                     "example": "2024-03-20T10:00:00Z"
                 },
-                "date": {
+                "event": {
+                    "$ref": "#/definitions/models.Event"
+                },
+                "eventId": {
+                    "type": "string"
+                },
+                "expiresAt": {
                     "type": "string",
                     "format": "date-time",
-                    "example": "2024-03-20T15:00:00Z"
+                    "example": "2024-03-23T10:00:00Z"
                 },
-                "description": {
+                "fromUserId": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "image": {
-                    "type": "string"
+                "status": {
+                    "type": "string",
+                    "example": "pending"
                 },
-                "location": {
-                    "type": "string"
+                "toEmail": {
+                    "type": "string",
+                    "example": "colleague@example.com"
                 },
-                "name": {
+                "toUserId": {
                     "type": "string"
                 },
-                "price": {
-                    "type": "number"
-                },
-                "tags": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Tag"
-                    }
-                },
                 "updatedAt": {
                     "type": "string",
                     "format": "date-time",
@@ -697,65 +6065,151 @@ const docTemplate = `{
                 }
             }
         },
-        "models.LoginRequest": {
+        "models.TicketType": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
+                "createdAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T10:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Limited discounted tickets"
+                },
+                "eventId": {
                     "type": "string"
                 },
-                "password": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
                     "type": "string",
-                    "minLength": 6
+                    "example": "Early bird"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "price": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "quota": {
+                    "description": "Quota is the number of tickets of this type for sale; 0 means unlimited",
+                    "type": "integer",
+                    "example": 100
+                },
+                "salesEndAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-10T23:59:00Z"
+                },
+                "salesStartAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-01T09:00:00Z"
+                },
+                "sold": {
+                    "description": "Sold counts tickets taken by active bookings and seat holds",
+                    "type": "integer",
+                    "example": 42
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T10:00:00Z"
+                },
+                "zone": {
+                    "description": "Zone limits the ticket type to seats of the seat map's price zone",
+                    "type": "string",
+                    "example": "premium"
                 }
             }
         },
-        "models.RegisterRequest": {
+        "models.UpdateEventMediaRequest": {
             "type": "object",
-            "required": [
-                "email",
-                "password",
-                "username"
-            ],
             "properties": {
-                "email": {
-                    "type": "string"
-                },
-                "password": {
+                "altText": {
                     "type": "string",
-                    "minLength": 6
+                    "example": "Crowd in front of the main stage"
                 },
-                "username": {
+                "caption": {
                     "type": "string",
-                    "minLength": 3
+                    "example": "Main stage"
                 }
             }
         },
-        "models.SuccessResponse": {
+        "models.UpdateRoleRequest": {
             "type": "object",
+            "required": [
+                "role"
+            ],
             "properties": {
-                "message": {
+                "role": {
                     "type": "string",
-                    "example": "operation successful"
+                    "enum": [
+                        "user",
+                        "staff",
+                        "admin"
+                    ],
+                    "example": "staff"
                 }
             }
         },
-        "models.Tag": {
+        "models.Upload": {
             "type": "object",
             "properties": {
+                "completedAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T10:05:00Z"
+                },
                 "createdAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T10:00:00Z"
+                },
+                "expiresAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-21T10:00:00Z"
+                },
+                "filename": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "name": {
-                    "type": "string"
+                "length": {
+                    "type": "integer"
+                },
+                "mediaType": {
+                    "type": "string",
+                    "example": "video"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "scanSignature": {
+                    "type": "string",
+                    "example": "Eicar-Test-Signature"
+                },
+                "scanStatus": {
+                    "type": "string",
+                    "example": "clean"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "published"
                 },
                 "updatedAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T10:00:00Z"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "userId": {
                     "type": "string"
                 }
             }
@@ -783,6 +6237,132 @@ const docTemplate = `{
                 }
             }
         },
+        "models.Venue": {
+            "type": "object",
+            "properties": {
+                "accessibility": {
+                    "type": "string",
+                    "example": "Step-free entrance, accessible toilets"
+                },
+                "address": {
+                    "type": "string",
+                    "example": "El Borg, Zamalek, Cairo"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T10:00:00Z"
+                },
+                "defaultCapacity": {
+                    "type": "integer",
+                    "example": 1200
+                },
+                "id": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number",
+                    "example": 30.0425
+                },
+                "longitude": {
+                    "type": "number",
+                    "example": 31.2243
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Cairo Opera House"
+                },
+                "photos": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T10:00:00Z"
+                }
+            }
+        },
+        "models.VenueConflict": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T15:00:00Z"
+                },
+                "endDate": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-03-20T18:00:00Z"
+                },
+                "eventId": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Jazz night"
+                }
+            }
+        },
+        "money.Money": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 2550
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "decimal": {
+                    "description": "Decimal is the amount in major units, filled in when encoding to JSON",
+                    "type": "string",
+                    "example": "25.50"
+                }
+            }
+        },
+        "payment.Intent": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "clientSecret": {
+                    "description": "ClientSecret lets the client complete the payment with the provider",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "pi_3MtwBwLkdIwHu7ix28a3tqPa"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "requires_payment"
+                }
+            }
+        },
+        "scanner.Result": {
+            "type": "object",
+            "properties": {
+                "engine": {
+                    "type": "string",
+                    "example": "clamav"
+                },
+                "signature": {
+                    "type": "string",
+                    "example": "Eicar-Test-Signature"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "clean"
+                }
+            }
+        },
         "upload.ErrorResponse": {
             "type": "object",
             "properties": {
@@ -791,11 +6371,26 @@ const docTemplate = `{
                 }
             }
         },
+        "upload.ScanErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "File failed malware scan"
+                },
+                "scan": {
+                    "$ref": "#/definitions/scanner.Result"
+                }
+            }
+        },
         "upload.UploadResponse": {
             "type": "object",
             "properties": {
                 "imageUrl": {
                     "type": "string"
+                },
+                "scan": {
+                    "$ref": "#/definitions/scanner.Result"
                 }
             }
         }
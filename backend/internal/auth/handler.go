@@ -1,11 +1,15 @@
 package auth
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 
 	"online-task/internal/models"
 	"online-task/pkg/database"
@@ -13,7 +17,7 @@ import (
 )
 
 // @Summary Register a new user
-// @Description Register a new user and return JWT token
+// @Description Register a new user and return JWT token. Registering from a guest booking's magic link, with its guestToken, proves the email is the guest's, so every guest booking made with that email joins the new account.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -42,7 +46,13 @@ func RegisterHandler(c *gin.Context) {
 		Role:     "user", // Default role
 	}
 
-	if err := database.GetDB().Create(&user).Error; err != nil {
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		return linkGuestBookings(tx, &user, req.GuestToken)
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
 		return
 	}
@@ -60,6 +70,30 @@ func RegisterHandler(c *gin.Context) {
 	})
 }
 
+// linkGuestBookings moves the guest bookings made with the user's email to
+// their account. The token of one of those bookings' magic links shows the
+// user can read mail sent to the address; without it nothing is linked, so
+// registering with someone else's email does not reveal their bookings.
+func linkGuestBookings(tx *gorm.DB, user *models.User, token string) error {
+	if token == "" {
+		return nil
+	}
+	email := strings.ToLower(strings.TrimSpace(user.Email))
+	// Magic link tokens are stored as their hex SHA-256
+	sum := sha256.Sum256([]byte(token))
+
+	var proven int64
+	if err := tx.Model(&models.Booking{}).
+		Where("user_id = '' AND guest_email = ? AND guest_token_hash = ?", email, hex.EncodeToString(sum[:])).
+		Count(&proven).Error; err != nil || proven == 0 {
+		return err
+	}
+	// The bookings are the account's now, so their links stop working
+	return tx.Model(&models.Booking{}).
+		Where("user_id = '' AND guest_email = ?", email).
+		Updates(map[string]interface{}{"user_id": user.ID, "guest_token_hash": ""}).Error
+}
+
 // @Summary Login user
 // @Description Authenticate user and return JWT token
 // @Tags auth
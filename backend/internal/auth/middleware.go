@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"online-task/internal/models"
 	"online-task/pkg/jwt"
 )
 
@@ -46,7 +47,7 @@ func AdminMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		if role != "admin" {
+		if role != models.RoleAdmin {
 			c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
 			c.Abort()
 			return
@@ -54,4 +55,45 @@ func AdminMiddleware() gin.HandlerFunc {
 
 		c.Next()
 	}
-} 
\ No newline at end of file
+}
+
+// OptionalAuthMiddleware identifies the caller when a valid bearer token is
+// sent, without rejecting anonymous requests
+func OptionalAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parts := strings.Split(c.GetHeader("Authorization"), " ")
+		if len(parts) == 2 && parts[0] == "Bearer" {
+			if claims, err := jwt.ValidateToken(parts[1]); err == nil {
+				c.Set("userID", claims.UserID)
+				c.Set("role", claims.Role)
+			}
+		}
+		c.Next()
+	}
+}
+
+// IsAdmin reports whether the authenticated caller has the admin role
+func IsAdmin(c *gin.Context) bool {
+	role, _ := c.Get("role")
+	return role == models.RoleAdmin
+}
+
+// StaffMiddleware admits door staff and admins
+func StaffMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, exists := c.Get("role")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User role not found"})
+			c.Abort()
+			return
+		}
+
+		if role != models.RoleStaff && role != models.RoleAdmin {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Staff access required"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,393 @@
+package booking
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"online-task/internal/models"
+	"online-task/internal/notification"
+	"online-task/pkg/database"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+var errBookingChanged = errors.New("booking changed concurrently")
+
+var bookingStatuses = map[string]bool{
+	models.BookingStatusPendingPayment: true,
+	models.BookingStatusConfirmed:      true,
+	models.BookingStatusCancelled:      true,
+	models.BookingStatusAttended:       true,
+}
+
+// eventBookings returns the bookings of the event in the path matching the
+// status and q query parameters, newest first. It responds with the error
+// and returns nil if the event does not exist or a filter is invalid.
+func eventBookings(c *gin.Context) *gorm.DB {
+	var event models.Event
+	if err := database.GetDB().First(&event, "id = ?", c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+			return nil
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch event"})
+		return nil
+	}
+
+	query := database.GetDB().Model(&models.Booking{}).Where("bookings.event_id = ?", event.ID)
+	if status := c.Query("status"); status != "" {
+		if !bookingStatuses[status] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown booking status " + status})
+			return nil
+		}
+		query = query.Where("bookings.status = ?", status)
+	}
+
+	// Search the booking ID and the names and emails of its holder and attendees
+	if q := strings.TrimSpace(c.Query("q")); q != "" {
+		like := "%" + strings.ToLower(q) + "%"
+		attendees := database.GetDB().Model(&models.Attendee{}).Select("booking_id").
+			Where("LOWER(name) LIKE ? OR LOWER(email) LIKE ?", like, like)
+		query = query.Joins("LEFT JOIN users ON users.id = bookings.user_id").
+			Where("bookings.id = ? OR LOWER(users.username) LIKE ? OR LOWER(users.email) LIKE ? OR LOWER(bookings.guest_name) LIKE ? OR LOWER(bookings.guest_email) LIKE ? OR bookings.id IN (?)",
+				q, like, like, like, like, attendees)
+	}
+	return query.Order("bookings.created_at DESC").Order("bookings.id")
+}
+
+// pageParams reads the page and pageSize query parameters
+func pageParams(c *gin.Context) (page, pageSize int, ok bool) {
+	page, pageSize = 1, defaultPageSize
+	var err error
+	if value := c.Query("page"); value != "" {
+		if page, err = strconv.Atoi(value); err != nil || page < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "page must be a positive number"})
+			return 0, 0, false
+		}
+	}
+	if value := c.Query("pageSize"); value != "" {
+		if pageSize, err = strconv.Atoi(value); err != nil || pageSize < 1 || pageSize > maxPageSize {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("pageSize must be between 1 and %d", maxPageSize)})
+			return 0, 0, false
+		}
+	}
+	return page, pageSize, true
+}
+
+func preloadBooking(db *gorm.DB) *gorm.DB {
+	return db.Preload("Event").Preload("TicketType").Preload("User").Preload("Refunds").Preload("Attendees").Preload("Seats.Seat")
+}
+
+func toAdminBooking(booking models.Booking) models.AdminBooking {
+	return models.AdminBooking{
+		BookingResponse: toResponse(booking),
+		HolderName:      booking.HolderName(),
+		HolderEmail:     booking.HolderEmail(),
+	}
+}
+
+// notifyHolder queues a message to the holder of a booking
+func notifyHolder(tx *gorm.DB, booking *models.Booking, notificationType, message string) error {
+	return notification.Emit(tx, models.Notification{
+		Type:      notificationType,
+		UserID:    booking.UserID,
+		Email:     booking.GuestEmail,
+		EventID:   booking.EventID,
+		BookingID: booking.ID,
+		Message:   message,
+	})
+}
+
+// @Summary List an event's bookings
+// @Description List the bookings of an event, newest first, with who holds them (admin only). Filter by status and search with q for a booking ID or part of the name or email of the holder or an attendee.
+// @Tags admin
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param status query string false "Booking status" Enums(pending_payment, confirmed, cancelled, attended)
+// @Param q query string false "Search text"
+// @Param page query int false "Page, from 1"
+// @Param pageSize query int false "Bookings per page, up to 200 (default 50)"
+// @Security Bearer
+// @Success 200 {object} models.AdminBookingList
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/events/{id}/bookings [get]
+func ListEventBookingsHandler(c *gin.Context) {
+	page, pageSize, ok := pageParams(c)
+	if !ok {
+		return
+	}
+	query := eventBookings(c)
+	if query == nil {
+		return
+	}
+
+	list := models.AdminBookingList{Bookings: []models.AdminBooking{}, Page: page, PageSize: pageSize}
+	if err := query.Session(&gorm.Session{}).Count(&list.Total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count bookings"})
+		return
+	}
+
+	var bookings []models.Booking
+	if err := query.Scopes(preloadBooking).Select("bookings.*").
+		Offset((page - 1) * pageSize).Limit(pageSize).Find(&bookings).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch bookings"})
+		return
+	}
+	for _, booking := range bookings {
+		list.Bookings = append(list.Bookings, toAdminBooking(booking))
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// loadAdminBooking fetches the booking in the path, responding 404 if there
+// is none
+func loadAdminBooking(c *gin.Context) (*models.Booking, bool) {
+	var booking models.Booking
+	if err := database.GetDB().Preload("Event").Preload("Attendees").First(&booking, "id = ?", c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch booking"})
+		return nil, false
+	}
+	return &booking, true
+}
+
+// respondAdminBooking reloads a booking an admin changed and responds with it
+func respondAdminBooking(c *gin.Context, id string) {
+	var booking models.Booking
+	if err := database.GetDB().Scopes(preloadBooking).First(&booking, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch booking details"})
+		return
+	}
+	c.JSON(http.StatusOK, toAdminBooking(booking))
+}
+
+// @Summary Cancel a booking as an admin
+// @Description Cancel any pending or confirmed booking and release its tickets (admin only). Paid bookings are refunded refundPercent of their total, in full by default, and the holder is notified with the reason.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Param request body models.AdminCancelBookingRequest false "Reason and refund"
+// @Security Bearer
+// @Success 200 {object} models.AdminBooking
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/bookings/{id}/cancel [post]
+func AdminCancelBookingHandler(c *gin.Context) {
+	var req models.AdminCancelBookingRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	percent := 100
+	if req.RefundPercent != nil {
+		percent = *req.RefundPercent
+	}
+
+	booking, ok := loadAdminBooking(c)
+	if !ok {
+		return
+	}
+	switch booking.Status {
+	case models.BookingStatusCancelled:
+		c.JSON(http.StatusConflict, gin.H{"error": "Booking is already cancelled"})
+		return
+	case models.BookingStatusAttended:
+		c.JSON(http.StatusConflict, gin.H{"error": "Booking has already been used"})
+		return
+	}
+
+	now := time.Now()
+	var refund *models.Refund
+	err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		var err error
+		refund, err = cancelBooking(tx, booking, now, percent, models.RefundReasonAdminCancelled)
+		if err != nil {
+			return err
+		}
+
+		message := fmt.Sprintf("Your booking for %s has been cancelled.", booking.Event.Name)
+		if req.Reason != "" {
+			message += " " + req.Reason
+		}
+		if refund != nil {
+			message += fmt.Sprintf(" %s will be refunded.", refund.Amount)
+		}
+		return notifyHolder(tx, booking, models.NotificationBookingCancelled, message)
+	})
+	if err == errAlreadyCancelled {
+		c.JSON(http.StatusConflict, gin.H{"error": "Booking is already cancelled"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel booking"})
+		return
+	}
+
+	if refund != nil {
+		issueRefund(c.Request.Context(), refund, *booking.PaymentIntentID)
+	}
+	respondAdminBooking(c, booking.ID)
+}
+
+// @Summary Move a booking
+// @Description Move a pending or confirmed booking to another event, or another ticket type of its event, keeping its price and attendees (admin only). The tickets come out of the target's capacity and quota regardless of its sales window, and events with a seat map need seats, one per ticket. The old tickets stop admitting and the holder, or each attendee, is sent the new ones.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Param request body models.MoveBookingRequest true "Target event"
+// @Security Bearer
+// @Success 200 {object} models.AdminBooking
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.CodedErrorResponse
+// @Router /admin/bookings/{id}/move [post]
+func MoveBookingHandler(c *gin.Context) {
+	var req models.MoveBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	booking, ok := loadAdminBooking(c)
+	if !ok {
+		return
+	}
+	if booking.Status != models.BookingStatusPendingPayment && booking.Status != models.BookingStatusConfirmed {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only pending or confirmed bookings can be moved"})
+		return
+	}
+	for _, attendee := range booking.Attendees {
+		if attendee.CheckedInAt != nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "Booking has already been used"})
+			return
+		}
+	}
+
+	co, checkoutErr := moveTarget(&req, booking, time.Now())
+	if checkoutErr != nil {
+		checkoutErr.respond(c)
+		return
+	}
+
+	var ticketTypeID *string
+	if co.ticketType != nil {
+		ticketTypeID = &co.ticketType.ID
+	}
+	err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := releaseSeats(tx, booking); err != nil {
+			return err
+		}
+		if err := reserveTickets(tx, &co.event, co.ticketType, booking.Quantity); err != nil {
+			return err
+		}
+		if err := reserveSeats(tx, co.event.ID, co.seats, &booking.ID, nil); err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.Booking{}).
+			Where("id = ? AND status = ? AND ticket_version = ?", booking.ID, booking.Status, booking.TicketVersion).
+			Updates(map[string]interface{}{"event_id": co.event.ID, "ticket_type_id": ticketTypeID, "ticket_version": booking.TicketVersion + 1})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errBookingChanged
+		}
+		from := booking.Event.Name
+		booking.EventID, booking.TicketTypeID, booking.TicketVersion = co.event.ID, ticketTypeID, booking.TicketVersion+1
+
+		message := fmt.Sprintf("Your booking for %s has been moved to %s on %s.", from, co.event.Name, co.event.LocalDate.Format("Monday, 2 January 2006, 15:04 MST"))
+		if booking.Status == models.BookingStatusConfirmed {
+			message += " Your earlier tickets are no longer valid; use the new ones."
+			if len(booking.Attendees) > 0 {
+				if err := sendAttendeeTickets(tx, booking, &co.event); err != nil {
+					return err
+				}
+			}
+		}
+		return notifyHolder(tx, booking, models.NotificationBookingMoved, message)
+	})
+	switch err {
+	case nil:
+	case errSoldOut:
+		c.JSON(http.StatusConflict, models.CodedErrorResponse{Error: "Not enough tickets left", Code: ErrCodeSoldOut})
+		return
+	case errSeatTaken:
+		c.JSON(http.StatusConflict, seatTakenError)
+		return
+	case errBookingChanged:
+		c.JSON(http.StatusConflict, gin.H{"error": "Booking was changed by another request"})
+		return
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to move booking"})
+		return
+	}
+
+	respondAdminBooking(c, booking.ID)
+}
+
+// moveTarget checks the event, ticket type and seats a booking is moved to
+func moveTarget(req *models.MoveBookingRequest, booking *models.Booking, now time.Time) (*checkout, *checkoutError) {
+	var co checkout
+	if err := database.GetDB().First(&co.event, "id = ?", req.EventID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &checkoutError{http.StatusNotFound, gin.H{"error": "Event not found"}}
+		}
+		return nil, &checkoutError{http.StatusInternalServerError, gin.H{"error": "Failed to fetch event"}}
+	}
+	if co.event.Status != models.EventStatusPublished {
+		return nil, &checkoutError{http.StatusConflict, gin.H{"error": "Event is not open for booking"}}
+	}
+	if !co.event.Date.After(now) {
+		return nil, &checkoutError{http.StatusConflict, models.CodedErrorResponse{Error: "Event has already taken place", Code: ErrCodeEventPassed}}
+	}
+
+	if req.TicketTypeID != "" {
+		co.ticketType = &models.TicketType{}
+		if err := database.GetDB().First(co.ticketType, "id = ? AND event_id = ?", req.TicketTypeID, co.event.ID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, &checkoutError{http.StatusBadRequest, gin.H{"error": "Ticket type not found for this event"}}
+			}
+			return nil, &checkoutError{http.StatusInternalServerError, gin.H{"error": "Failed to fetch ticket type"}}
+		}
+	} else {
+		var typeCount int64
+		if err := database.GetDB().Model(&models.TicketType{}).Where("event_id = ?", co.event.ID).Count(&typeCount).Error; err != nil {
+			return nil, &checkoutError{http.StatusInternalServerError, gin.H{"error": "Failed to fetch ticket types"}}
+		}
+		if typeCount > 0 {
+			return nil, &checkoutError{http.StatusBadRequest, gin.H{"error": "ticketTypeId is required for this event"}}
+		}
+	}
+	if co.event.ID == booking.EventID && req.TicketTypeID == "" && booking.TicketTypeID == nil ||
+		booking.TicketTypeID != nil && *booking.TicketTypeID == req.TicketTypeID {
+		return nil, &checkoutError{http.StatusBadRequest, gin.H{"error": "Booking is already for this event and ticket type"}}
+	}
+
+	if err := co.chooseSeats(req.Seats); err != nil {
+		return nil, err
+	}
+	if len(co.seats) > 0 && len(co.seats) != booking.Quantity {
+		return nil, &checkoutError{http.StatusBadRequest, gin.H{"error": "Choose a seat for every ticket"}}
+	}
+	return &co, nil
+}
@@ -0,0 +1,258 @@
+package booking
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+	"online-task/pkg/money"
+)
+
+// setupAdminTest extends the payment test router with the admin routes and
+// gives the test event a booking of each kind: the user's pending booking of
+// two tickets, a guest's, a group booking and a cancelled one
+func setupAdminTest(t *testing.T) (*gin.Engine, models.BookingResponse) {
+	t.Helper()
+	r, _ := setupPaymentTest(t)
+	if err := database.GetDB().AutoMigrate(&models.User{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	database.GetDB().Create(&models.User{ID: "user-1", Username: "ada", Email: "ada@example.com", Password: "x"})
+
+	admin := r.Group("/api/admin")
+	admin.GET("/events/:id/bookings", ListEventBookingsHandler)
+	admin.GET("/events/:id/bookings/export", ExportEventBookingsHandler)
+	admin.POST("/bookings/:id/cancel", AdminCancelBookingHandler)
+	admin.POST("/bookings/:id/move", MoveBookingHandler)
+
+	pending := createPaidBooking(t, r)
+	standard := "standard"
+	earlier := time.Now().Add(-time.Hour)
+	for _, booking := range []models.Booking{
+		{ID: "guest", EventID: "concert", TicketTypeID: &standard, Quantity: 1, Status: models.BookingStatusConfirmed,
+			GuestName: "=HYPERLINK(\"http://evil\")", GuestEmail: "grace@example.com", CreatedAt: earlier},
+		{ID: "group", EventID: "concert", TicketTypeID: &standard, Quantity: 2, Status: models.BookingStatusConfirmed, UserID: "user-2", CreatedAt: earlier,
+			Attendees: []models.Attendee{{ID: "alan", Name: "Alan Turing", Email: "alan@example.com"}, {ID: "joan", Name: "Joan Clarke", Email: "joan@example.com"}}},
+		{ID: "cancelled", EventID: "concert", TicketTypeID: &standard, Quantity: 1, Status: models.BookingStatusCancelled, UserID: "user-1", CreatedAt: earlier},
+	} {
+		if err := database.GetDB().Create(&booking).Error; err != nil {
+			t.Fatalf("failed to create booking: %v", err)
+		}
+	}
+	database.GetDB().Model(&models.TicketType{}).Where("id = ?", standard).Update("sold", adminTestSold)
+	return r, pending
+}
+
+// adminTestSold counts the standard tickets of setupAdminTest's active bookings
+const adminTestSold = 5
+
+func TestListEventBookings(t *testing.T) {
+	r, pending := setupAdminTest(t)
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantTotal  int64
+		wantIDs    []string
+	}{
+		{name: "all", query: "", wantStatus: http.StatusOK, wantTotal: 4},
+		{name: "by status", query: "?status=confirmed", wantStatus: http.StatusOK, wantTotal: 2},
+		{name: "holder's name", query: "?q=ADA", wantStatus: http.StatusOK, wantTotal: 2},
+		{name: "guest's email", query: "?q=grace@", wantStatus: http.StatusOK, wantTotal: 1, wantIDs: []string{"guest"}},
+		{name: "attendee", query: "?q=turing", wantStatus: http.StatusOK, wantTotal: 1, wantIDs: []string{"group"}},
+		{name: "booking ID", query: "?q=" + pending.ID, wantStatus: http.StatusOK, wantTotal: 1, wantIDs: []string{pending.ID}},
+		{name: "page", query: "?page=2&pageSize=3", wantStatus: http.StatusOK, wantTotal: 4},
+		{name: "unknown status", query: "?status=lost", wantStatus: http.StatusBadRequest},
+		{name: "page too large", query: "?pageSize=500", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/admin/events/concert/bookings"+tt.query, nil))
+			var list models.AdminBookingList
+			json.Unmarshal(w.Body.Bytes(), &list)
+			if w.Code != tt.wantStatus || list.Total != tt.wantTotal {
+				t.Fatalf("got %d with %d bookings, want %d with %d, body %s", w.Code, list.Total, tt.wantStatus, tt.wantTotal, w.Body.String())
+			}
+			for i, id := range tt.wantIDs {
+				if list.Bookings[i].ID != id {
+					t.Errorf("booking %d = %s, want %s", i, list.Bookings[i].ID, id)
+				}
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/admin/events/concert/bookings?pageSize=1", nil))
+	var list models.AdminBookingList
+	json.Unmarshal(w.Body.Bytes(), &list)
+	if len(list.Bookings) != 1 || list.Bookings[0].ID != pending.ID || list.Bookings[0].HolderEmail != "ada@example.com" {
+		t.Errorf("first page = %+v, want the newest booking with its holder", list.Bookings)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/admin/events/missing/bookings", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown event: status = %d, want 404", w.Code)
+	}
+}
+
+func TestExportEventBookings(t *testing.T) {
+	r, _ := setupAdminTest(t)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/admin/events/concert/bookings/export", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("csv status = %d, body %s", w.Code, w.Body.String())
+	}
+	rows, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid csv: %v", err)
+	}
+	// The header, the pending and guest bookings and the group's two attendees
+	if len(rows) != 5 || rows[0][0] != "Booking ID" {
+		t.Fatalf("got %d rows, want 5: %v", len(rows), rows)
+	}
+	names := map[string]string{}
+	for _, row := range rows[1:] {
+		names[row[4]] = row[3]
+	}
+	for name, tickets := range map[string]string{"ada": "2", "Alan Turing": "1", "Joan Clarke": "1", "'=HYPERLINK(\"http://evil\")": "1"} {
+		if names[name] != tickets {
+			t.Errorf("row of %s has %q tickets, want %s", name, names[name], tickets)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/admin/events/concert/bookings/export?format=xlsx&status=cancelled", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("xlsx status = %d, body %s", w.Code, w.Body.String())
+	}
+	f, err := excelize.OpenReader(w.Body)
+	if err != nil {
+		t.Fatalf("invalid xlsx: %v", err)
+	}
+	sheetRows, err := f.GetRows("Attendees")
+	if err != nil || len(sheetRows) != 2 || sheetRows[1][0] != "cancelled" {
+		t.Errorf("got rows %v, %v, want the cancelled booking", sheetRows, err)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/admin/events/concert/bookings/export?format=pdf", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("unknown format: status = %d, want 400", w.Code)
+	}
+}
+
+func adminRequest(r *gin.Engine, path string, body interface{}) (*httptest.ResponseRecorder, models.AdminBooking) {
+	data, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, bytes.NewReader(data)))
+
+	var booking models.AdminBooking
+	json.Unmarshal(w.Body.Bytes(), &booking)
+	return w, booking
+}
+
+func TestAdminCancelBooking(t *testing.T) {
+	r, pending := setupAdminTest(t)
+	body, _ := json.Marshal(models.PayBookingRequest{PaymentMethod: "pm_card_visa"})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/bookings/"+pending.ID+"/pay", bytes.NewReader(body)))
+
+	half := 50
+	w, cancelled := adminRequest(r, "/api/admin/bookings/"+pending.ID+"/cancel", models.AdminCancelBookingRequest{Reason: "Duplicate booking", RefundPercent: &half})
+	if w.Code != http.StatusOK || cancelled.Status != models.BookingStatusCancelled {
+		t.Fatalf("got %d %+v, want the booking cancelled", w.Code, cancelled)
+	}
+	if len(cancelled.Refunds) != 1 || cancelled.Refunds[0].Amount != money.New(2000, "USD") || cancelled.Refunds[0].Reason != models.RefundReasonAdminCancelled {
+		t.Errorf("refunds = %+v, want half of 40.00 USD refunded", cancelled.Refunds)
+	}
+	if sold := soldTickets(t); sold != adminTestSold-2 {
+		t.Errorf("sold = %d, want the booking's tickets released", sold)
+	}
+	var sent int64
+	database.GetDB().Model(&models.Notification{}).Where("booking_id = ? AND type = ?", pending.ID, models.NotificationBookingCancelled).Count(&sent)
+	if sent != 1 {
+		t.Errorf("sent %d cancellation messages, want 1", sent)
+	}
+
+	if w, _ := adminRequest(r, "/api/admin/bookings/"+pending.ID+"/cancel", nil); w.Code != http.StatusConflict {
+		t.Errorf("cancelling twice: status = %d, want 409", w.Code)
+	}
+	if w, _ := adminRequest(r, "/api/admin/bookings/missing/cancel", nil); w.Code != http.StatusNotFound {
+		t.Errorf("unknown booking: status = %d, want 404", w.Code)
+	}
+}
+
+func TestMoveBooking(t *testing.T) {
+	r, _ := setupAdminTest(t)
+	opera := models.Event{ID: "opera", Name: "Opera", Date: time.Now().Add(72 * time.Hour), Status: models.EventStatusPublished}
+	database.GetDB().Create(&opera)
+	database.GetDB().Create(&models.TicketType{ID: "balcony", EventID: opera.ID, Name: "Balcony", Price: money.New(3000, "USD"), Quota: 3})
+	database.GetDB().Create(&models.TicketType{ID: "premium", EventID: "concert", Name: "Premium", Price: money.New(5000, "USD")})
+	database.GetDB().Create(&models.Booking{ID: "taken", EventID: opera.ID, Quantity: 2, Status: models.BookingStatusConfirmed, UserID: "user-3"})
+	database.GetDB().Model(&models.TicketType{}).Where("id = ?", "balcony").Update("sold", 2)
+
+	tests := []struct {
+		name       string
+		req        models.MoveBookingRequest
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "without a ticket type", req: models.MoveBookingRequest{EventID: "opera"}, wantStatus: http.StatusBadRequest},
+		{name: "same ticket type", req: models.MoveBookingRequest{EventID: "concert", TicketTypeID: "standard"}, wantStatus: http.StatusBadRequest},
+		{name: "unknown event", req: models.MoveBookingRequest{EventID: "missing"}, wantStatus: http.StatusNotFound},
+		{name: "past the quota", req: models.MoveBookingRequest{EventID: "opera", TicketTypeID: "balcony"}, wantStatus: http.StatusConflict, wantCode: ErrCodeSoldOut},
+		{name: "within the event", req: models.MoveBookingRequest{EventID: "concert", TicketTypeID: "premium"}, wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, _ := adminRequest(r, "/api/admin/bookings/group/move", tt.req)
+			var refused models.CodedErrorResponse
+			json.Unmarshal(w.Body.Bytes(), &refused)
+			if w.Code != tt.wantStatus || refused.Code != tt.wantCode {
+				t.Errorf("got %d %q, want %d %q, body %s", w.Code, refused.Code, tt.wantStatus, tt.wantCode, w.Body.String())
+			}
+		})
+	}
+
+	database.GetDB().Model(&models.TicketType{}).Where("id = ?", "balcony").Update("quota", 4)
+	w, moved := adminRequest(r, "/api/admin/bookings/group/move", models.MoveBookingRequest{EventID: "opera", TicketTypeID: "balcony"})
+	if w.Code != http.StatusOK || moved.EventID != "opera" || moved.TicketType == nil || moved.TicketType.ID != "balcony" {
+		t.Fatalf("got %d %+v, want the booking at the opera", w.Code, moved)
+	}
+	if !moved.Total.IsZero() || len(moved.Attendees) != 2 {
+		t.Errorf("got total %v and %d attendees, want them kept", moved.Total, len(moved.Attendees))
+	}
+
+	var types []models.TicketType
+	database.GetDB().Order("id").Find(&types)
+	sold := map[string]int{}
+	for _, tt := range types {
+		sold[tt.ID] = tt.Sold
+	}
+	if sold["standard"] != adminTestSold-2 || sold["premium"] != 0 || sold["balcony"] != 4 {
+		t.Errorf("sold = %v, want the tickets moved to the balcony", sold)
+	}
+
+	var tickets int64
+	database.GetDB().Model(&models.Notification{}).Where("booking_id = ? AND type = ?", "group", models.NotificationTicketIssued).Count(&tickets)
+	if tickets != 4 {
+		t.Errorf("sent %d tickets, want both attendees a new ticket after each move", tickets)
+	}
+	var booking models.Booking
+	database.GetDB().First(&booking, "id = ?", "group")
+	if booking.TicketVersion != 2 {
+		t.Errorf("ticket version = %d, want 2 so earlier tickets stop admitting", booking.TicketVersion)
+	}
+}
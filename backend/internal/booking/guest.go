@@ -0,0 +1,244 @@
+package booking
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"online-task/internal/models"
+	"online-task/internal/notification"
+	"online-task/pkg/database"
+)
+
+// ErrCodeAccountExists is returned when a guest books with the email of an
+// account, whose owner has to sign in instead
+const ErrCodeAccountExists = "account_exists"
+
+// GuestTokenHeader carries the token of a guest booking's magic link
+const GuestTokenHeader = "X-Booking-Token"
+
+// guestBookingKey is the context key of the booking opened with a guest link
+const guestBookingKey = "guestBookingID"
+
+// bookingOwner is who a booking is made for: a user, or a guest known only
+// by email
+type bookingOwner struct {
+	userID     string
+	guestEmail string
+}
+
+func ownerOf(booking *models.Booking) bookingOwner {
+	return bookingOwner{userID: booking.UserID, guestEmail: booking.GuestEmail}
+}
+
+// scope limits a query to the owner's bookings. A guest's bookings are
+// those not yet linked to an account.
+func (o bookingOwner) scope(db *gorm.DB) *gorm.DB {
+	if o.userID != "" {
+		return db.Where("user_id = ?", o.userID)
+	}
+	return db.Where("user_id = '' AND guest_email = ?", o.guestEmail)
+}
+
+// ownBooking limits a query to the booking in the path, which must belong to
+// the authenticated user or have been opened with its guest link
+func ownBooking(c *gin.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if id := c.GetString(guestBookingKey); id != "" && id == c.Param("id") {
+			return db.Where("id = ?", id)
+		}
+		return db.Where("id = ? AND user_id = ?", c.Param("id"), c.GetString("userID"))
+	}
+}
+
+// guestLinkURL is where magic links point, from GUEST_LINK_URL (default the
+// frontend's guest booking page)
+func guestLinkURL() string {
+	if base := os.Getenv("GUEST_LINK_URL"); base != "" {
+		return strings.TrimRight(base, "/")
+	}
+	return "http://localhost:5173/guest/bookings"
+}
+
+func guestLink(bookingID, token string) string {
+	return guestLinkURL() + "/" + url.PathEscape(bookingID) + "?token=" + url.QueryEscape(token)
+}
+
+// sendGuestLink emails the guest the magic link to their booking
+func sendGuestLink(tx *gorm.DB, booking *models.Booking, event *models.Event, token string) error {
+	// The token only leaves the server in the message to the guest and in
+	// the response to the request that created the booking
+	return notification.Emit(tx, models.Notification{
+		Type:      models.NotificationGuestBookingLink,
+		Email:     booking.GuestEmail,
+		EventID:   event.ID,
+		BookingID: booking.ID,
+		Message: fmt.Sprintf("Hi %s, your booking for %s is ready. View, pay for or cancel it at %s",
+			booking.GuestName, event.Name, guestLink(booking.ID, token)),
+	})
+}
+
+// guestOwner checks that a guest may book with the request's email, which
+// must not belong to an account
+func guestOwner(req *models.GuestBookingRequest) (bookingOwner, *checkoutError) {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+
+	var accounts int64
+	if err := database.GetDB().Model(&models.User{}).Where("LOWER(email) = ?", email).Count(&accounts).Error; err != nil {
+		return bookingOwner{}, &checkoutError{http.StatusInternalServerError, gin.H{"error": "Failed to check email"}}
+	}
+	if accounts > 0 {
+		return bookingOwner{}, &checkoutError{http.StatusConflict, models.CodedErrorResponse{
+			Error: "An account exists for this email; sign in to book",
+			Code:  ErrCodeAccountExists,
+		}}
+	}
+	return bookingOwner{guestEmail: email}, nil
+}
+
+// @Summary Book as a guest
+// @Description Book tickets without an account, with only a name and email. Booking rules and errors are those of POST /bookings; an email that belongs to an account is rejected with code account_exists. The guest is emailed a magic link to view, pay for or cancel the booking, and the response carries its guestToken so the booking can be paid straight away. Registering from the link with its token moves the guest's bookings to the new account.
+// @Tags guest
+// @Accept json
+// @Produce json
+// @Param request body models.GuestBookingRequest true "Guest and booking details"
+// @Success 201 {object} models.BookingResponse
+// @Failure 409 {object} models.CodedErrorResponse
+// @Failure 502 {object} models.ErrorResponse
+// @Router /guest/bookings [post]
+func CreateGuestBookingHandler(c *gin.Context) {
+	var req models.GuestBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	owner, checkoutErr := guestOwner(&req)
+	if checkoutErr != nil {
+		checkoutErr.respond(c)
+		return
+	}
+
+	token, hash, err := newToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create booking link"})
+		return
+	}
+
+	createBooking(c, &req.CreateBookingRequest, models.Booking{
+		GuestName:      strings.TrimSpace(req.Name),
+		GuestEmail:     owner.guestEmail,
+		GuestTokenHash: hash,
+	}, token)
+}
+
+// @Summary Quote a guest booking
+// @Description Preview the price of a guest booking, with the errors of POST /guest/bookings
+// @Tags guest
+// @Accept json
+// @Produce json
+// @Param request body models.GuestBookingRequest true "Guest and booking details"
+// @Success 200 {object} models.BookingQuote
+// @Failure 400 {object} models.CodedErrorResponse
+// @Failure 409 {object} models.CodedErrorResponse
+// @Router /guest/bookings/quote [post]
+func QuoteGuestBookingHandler(c *gin.Context) {
+	var req models.GuestBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	owner, checkoutErr := guestOwner(&req)
+	if checkoutErr != nil {
+		checkoutErr.respond(c)
+		return
+	}
+	co, checkoutErr := prepareCheckout(&req.CreateBookingRequest, owner, time.Now())
+	if checkoutErr != nil {
+		checkoutErr.respond(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, co.quote)
+}
+
+// @Summary Resend guest booking links
+// @Description Email fresh magic links to the active guest bookings made with an email. Links sent before stop working. The response is the same whether or not there are any bookings.
+// @Tags guest
+// @Accept json
+// @Produce json
+// @Param request body models.GuestLinkRequest true "Guest email"
+// @Success 202 {object} models.SuccessResponse
+// @Router /guest/bookings/link [post]
+func ResendGuestLinksHandler(c *gin.Context) {
+	var req models.GuestLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var bookings []models.Booking
+	if err := database.GetDB().Preload("Event").Scopes(bookingOwner{guestEmail: strings.ToLower(strings.TrimSpace(req.Email))}.scope).
+		Where("status <> ?", models.BookingStatusCancelled).Find(&bookings).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch bookings"})
+		return
+	}
+
+	for i := range bookings {
+		booking := &bookings[i]
+		err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+			token, hash, err := newToken()
+			if err != nil {
+				return err
+			}
+			if err := tx.Model(booking).Update("guest_token_hash", hash).Error; err != nil {
+				return err
+			}
+			return sendGuestLink(tx, booking, &booking.Event, token)
+		})
+		if err != nil {
+			log.Printf("Failed to resend the guest link of booking %s: %v", booking.ID, err)
+		}
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "If there are bookings for this email, links to them are on their way"})
+}
+
+// GuestAccessMiddleware lets the holder of a guest booking's magic link
+// manage that booking. The token is read from the X-Booking-Token header or
+// the token query parameter, as in the link.
+func GuestAccessMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.GetHeader(GuestTokenHeader)
+		if token == "" {
+			token = c.Query("token")
+		}
+
+		var matches int64
+		if token != "" {
+			if err := database.GetDB().Model(&models.Booking{}).
+				Where("id = ? AND guest_token_hash = ?", c.Param("id"), hashToken(token)).
+				Count(&matches).Error; err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check booking link"})
+				c.Abort()
+				return
+			}
+		}
+		if matches == 0 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or outdated booking link"})
+			c.Abort()
+			return
+		}
+
+		c.Set(guestBookingKey, c.Param("id"))
+		c.Next()
+	}
+}
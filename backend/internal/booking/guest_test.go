@@ -0,0 +1,209 @@
+package booking
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"online-task/internal/auth"
+	"online-task/internal/models"
+	"online-task/pkg/database"
+)
+
+// setupGuestTest extends the payment test router with the guest routes and
+// registration
+func setupGuestTest(t *testing.T) *gin.Engine {
+	t.Helper()
+	r, _ := setupPaymentTest(t)
+	if err := database.GetDB().AutoMigrate(&models.User{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	database.GetDB().Create(&models.User{ID: "ada", Username: "ada", Email: "ada@example.com", Password: "x"})
+
+	r.POST("/api/auth/register", auth.RegisterHandler)
+	guest := r.Group("/api/guest/bookings")
+	guest.POST("", CreateGuestBookingHandler)
+	guest.POST("/link", ResendGuestLinksHandler)
+	manage := guest.Group("/:id", GuestAccessMiddleware())
+	manage.GET("", GetBookingHandler)
+	manage.POST("/pay", PayBookingHandler)
+	manage.DELETE("", CancelBookingHandler)
+	return r
+}
+
+func guestRequest(r *gin.Engine, method, path string, body interface{}) *httptest.ResponseRecorder {
+	data, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(method, path, bytes.NewReader(data)))
+	return w
+}
+
+// sentGuestLink reads the magic link from the last message sent to email
+func sentGuestLink(t *testing.T, email string) *url.URL {
+	t.Helper()
+	var n models.Notification
+	if err := database.GetDB().Where("email = ? AND type = ?", email, models.NotificationGuestBookingLink).
+		Order("created_at DESC").First(&n).Error; err != nil {
+		t.Fatalf("no guest link sent to %s: %v", email, err)
+	}
+	link, err := url.Parse(n.Message[strings.LastIndex(n.Message, " ")+1:])
+	if err != nil {
+		t.Fatalf("message %q does not end with a link: %v", n.Message, err)
+	}
+	return link
+}
+
+func TestGuestBooking(t *testing.T) {
+	r := setupGuestTest(t)
+	req := models.CreateBookingRequest{EventID: "concert", TicketTypeID: "standard", Quantity: 1}
+
+	w := guestRequest(r, http.MethodPost, "/api/guest/bookings", models.GuestBookingRequest{CreateBookingRequest: req, Name: "Ada", Email: "ADA@example.com"})
+	var refused models.CodedErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &refused)
+	if w.Code != http.StatusConflict || refused.Code != ErrCodeAccountExists {
+		t.Fatalf("booking with an account's email: got %d %q, want 409 %q", w.Code, refused.Code, ErrCodeAccountExists)
+	}
+
+	w = guestRequest(r, http.MethodPost, "/api/guest/bookings", models.GuestBookingRequest{CreateBookingRequest: req, Name: "Grace", Email: "Grace@Example.com"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create status = %d, body %s", w.Code, w.Body.String())
+	}
+	var booking models.BookingResponse
+	json.Unmarshal(w.Body.Bytes(), &booking)
+	if booking.UserID != "" || booking.GuestEmail != "grace@example.com" || booking.GuestToken == "" {
+		t.Fatalf("booking = %+v, want a guest booking for grace@example.com with a token", booking)
+	}
+	link := sentGuestLink(t, "grace@example.com")
+	if link.Query().Get("token") != booking.GuestToken || !strings.HasSuffix(link.Path, "/"+booking.ID) {
+		t.Errorf("link %s does not open booking %s", link, booking.ID)
+	}
+
+	other := guestRequest(r, http.MethodPost, "/api/guest/bookings", models.GuestBookingRequest{CreateBookingRequest: req, Name: "Alan", Email: "alan@example.com"})
+	var otherBooking models.BookingResponse
+	json.Unmarshal(other.Body.Bytes(), &otherBooking)
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+	}{
+		{name: "magic link", path: "/api/guest/bookings/" + booking.ID + "?token=" + url.QueryEscape(booking.GuestToken), wantStatus: http.StatusOK},
+		{name: "no token", path: "/api/guest/bookings/" + booking.ID, wantStatus: http.StatusUnauthorized},
+		{name: "wrong token", path: "/api/guest/bookings/" + booking.ID + "?token=nope", wantStatus: http.StatusUnauthorized},
+		{name: "another guest's token", path: "/api/guest/bookings/" + booking.ID + "?token=" + url.QueryEscape(otherBooking.GuestToken), wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := guestRequest(r, http.MethodGet, tt.path, nil); w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+		})
+	}
+
+	// The token also works as a header, and pays for the booking
+	pay := httptest.NewRequest(http.MethodPost, "/api/guest/bookings/"+booking.ID+"/pay", nil)
+	pay.Header.Set(GuestTokenHeader, booking.GuestToken)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, pay)
+	if w.Code != http.StatusAccepted || bookingStatus(t, booking.ID) != models.BookingStatusConfirmed {
+		t.Errorf("pay status = %d, booking %s, want the guest booking paid", w.Code, bookingStatus(t, booking.ID))
+	}
+}
+
+func TestGuestLinksAndRegistration(t *testing.T) {
+	r := setupGuestTest(t)
+	req := models.GuestBookingRequest{
+		CreateBookingRequest: models.CreateBookingRequest{EventID: "concert", TicketTypeID: "standard", Quantity: 1},
+		Name:                 "Grace",
+		Email:                "grace@example.com",
+	}
+	w := guestRequest(r, http.MethodPost, "/api/guest/bookings", req)
+	var booking models.BookingResponse
+	json.Unmarshal(w.Body.Bytes(), &booking)
+
+	// A resent link replaces the first one
+	if w := guestRequest(r, http.MethodPost, "/api/guest/bookings/link", models.GuestLinkRequest{Email: "Grace@example.com"}); w.Code != http.StatusAccepted {
+		t.Fatalf("resend status = %d, body %s", w.Code, w.Body.String())
+	}
+	link := sentGuestLink(t, "grace@example.com")
+	if w := guestRequest(r, http.MethodGet, "/api/guest/bookings/"+booking.ID+"?token="+url.QueryEscape(booking.GuestToken), nil); w.Code != http.StatusUnauthorized {
+		t.Errorf("first link status = %d, want 401", w.Code)
+	}
+	if w := guestRequest(r, http.MethodGet, "/api/guest/bookings/"+booking.ID+"?"+link.RawQuery, nil); w.Code != http.StatusOK {
+		t.Errorf("resent link status = %d, want 200", w.Code)
+	}
+
+	// A second booking with the same email, and one by another guest
+	w = guestRequest(r, http.MethodPost, "/api/guest/bookings", req)
+	var second models.BookingResponse
+	json.Unmarshal(w.Body.Bytes(), &second)
+	other := req
+	other.Email = "mallory@example.com"
+	w = guestRequest(r, http.MethodPost, "/api/guest/bookings", other)
+	var others models.BookingResponse
+	json.Unmarshal(w.Body.Bytes(), &others)
+
+	tests := []struct {
+		name       string
+		email      string
+		token      string
+		wantLinked bool
+	}{
+		// Without proof of the email, registering with it takes nothing
+		{name: "without a link", email: "grace@example.com"},
+		{name: "with a wrong token", email: "grace@example.com", token: "nope"},
+		{name: "with another guest's link", email: "grace@example.com", token: others.GuestToken},
+		{name: "with the guest's link", email: "Grace@Example.com", token: link.Query().Get("token"), wantLinked: true},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Accounts that took nothing are removed so the email can be
+			// registered again
+			if !tt.wantLinked {
+				t.Cleanup(func() { database.GetDB().Unscoped().Where("username <> ?", "ada").Delete(&models.User{}) })
+			}
+
+			w := guestRequest(r, http.MethodPost, "/api/auth/register", models.RegisterRequest{
+				Username: fmt.Sprintf("grace%d", i), Email: tt.email, Password: "secret123", GuestToken: tt.token,
+			})
+			if w.Code != http.StatusCreated {
+				t.Fatalf("register status = %d, body %s", w.Code, w.Body.String())
+			}
+			var account models.AuthResponse
+			json.Unmarshal(w.Body.Bytes(), &account)
+
+			for _, id := range []string{booking.ID, second.ID} {
+				var linked models.Booking
+				database.GetDB().First(&linked, "id = ?", id)
+				if got := linked.UserID == account.User.ID; got != tt.wantLinked {
+					t.Errorf("booking %s user = %q, want linked %v", id, linked.UserID, tt.wantLinked)
+				}
+			}
+			var untouched models.Booking
+			database.GetDB().First(&untouched, "id = ?", others.ID)
+			if untouched.UserID != "" {
+				t.Errorf("another guest's booking joined the account")
+			}
+		})
+	}
+
+	// The bookings are the account's now, so the link stops working
+	if w := guestRequest(r, http.MethodGet, "/api/guest/bookings/"+booking.ID+"?"+link.RawQuery, nil); w.Code != http.StatusUnauthorized {
+		t.Errorf("link of a linked booking: status = %d, want 401", w.Code)
+	}
+
+	// The guest now books with their account
+	w = guestRequest(r, http.MethodPost, "/api/guest/bookings", req)
+	var refused models.CodedErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &refused)
+	if w.Code != http.StatusConflict || refused.Code != ErrCodeAccountExists {
+		t.Errorf("guest booking after registering: got %d %q, want 409 %q", w.Code, refused.Code, ErrCodeAccountExists)
+	}
+}
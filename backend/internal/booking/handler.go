@@ -1,7 +1,11 @@
 package booking
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -9,16 +13,177 @@ import (
 
 	"online-task/internal/models"
 	"online-task/pkg/database"
+	"online-task/pkg/payment"
 )
 
+// Error codes returned when an event cannot be booked right now
+const (
+	ErrCodeSalesNotStarted = "sales_not_started"
+	ErrCodeSalesEnded      = "sales_ended"
+	ErrCodeEventPassed     = "event_passed"
+	ErrCodeSoldOut         = "sold_out"
+	ErrCodeOrderLimit      = "order_limit"
+	ErrCodeUserLimit       = "user_limit"
+)
+
+var (
+	errSoldOut   = errors.New("sold out")
+	errUserLimit = errors.New("user ticket limit reached")
+)
+
+// salesWindowError reports why the event, or the ticket type if one is
+// given, is outside its booking window
+func salesWindowError(event *models.Event, ticketType *models.TicketType, now time.Time) *models.CodedErrorResponse {
+	if !event.Date.After(now) {
+		return &models.CodedErrorResponse{Error: "Event has already taken place", Code: ErrCodeEventPassed}
+	}
+
+	windows := [][2]*time.Time{{event.SalesStartAt, event.SalesEndAt}}
+	if ticketType != nil {
+		windows = append(windows, [2]*time.Time{ticketType.SalesStartAt, ticketType.SalesEndAt})
+	}
+	for _, window := range windows {
+		if window[0] != nil && now.Before(*window[0]) {
+			return &models.CodedErrorResponse{Error: "Ticket sales have not started yet", Code: ErrCodeSalesNotStarted}
+		}
+		if window[1] != nil && !now.Before(*window[1]) {
+			return &models.CodedErrorResponse{Error: "Ticket sales have ended", Code: ErrCodeSalesEnded}
+		}
+	}
+	return nil
+}
+
+// reserveTickets takes quantity tickets from the ticket type's quota and
+// the event's capacity, failing with errSoldOut if either is exhausted.
+// Tickets set aside by active seat holds are not available.
+func reserveTickets(tx *gorm.DB, event *models.Event, ticketType *models.TicketType, quantity int) error {
+	if ticketType != nil {
+		// The quota check and increment happen in one statement so concurrent
+		// bookings cannot oversell
+		result := tx.Model(&models.TicketType{}).
+			Where("id = ? AND (quota = 0 OR sold + ? <= quota)", ticketType.ID, quantity).
+			Update("sold", gorm.Expr("sold + ?", quantity))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errSoldOut
+		}
+	}
+
+	// The same goes for the event's capacity
+	result := tx.Model(&models.Event{}).
+		Where("id = ? AND (capacity = 0 OR reserved + ? <= capacity)", event.ID, quantity).
+		UpdateColumn("reserved", gorm.Expr("reserved + ?", quantity))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errSoldOut
+	}
+	return nil
+}
+
+// returnTickets gives quantity tickets back to the event's capacity and the
+// ticket type's quota
+func returnTickets(tx *gorm.DB, eventID string, ticketTypeID *string, quantity int) error {
+	if err := tx.Model(&models.Event{}).
+		Where("id = ? AND reserved >= ?", eventID, quantity).
+		UpdateColumn("reserved", gorm.Expr("reserved - ?", quantity)).Error; err != nil {
+		return err
+	}
+	if ticketTypeID == nil {
+		return nil
+	}
+	return tx.Model(&models.TicketType{}).
+		Where("id = ? AND sold >= ?", *ticketTypeID, quantity).
+		Update("sold", gorm.Expr("sold - ?", quantity)).Error
+}
+
+// userLimitReached reports whether booking quantity more tickets would take
+// the owner past the event's per-user limit, counting their active bookings
+// and seat holds
+func userLimitReached(db *gorm.DB, event *models.Event, owner bookingOwner, quantity int) (bool, error) {
+	if event.MaxTicketsPerUser == 0 {
+		return false, nil
+	}
+
+	var booked, held int64
+	if err := db.Model(&models.Booking{}).Scopes(owner.scope).
+		Where("event_id = ? AND status <> ?", event.ID, models.BookingStatusCancelled).
+		Select("COALESCE(SUM(quantity), 0)").
+		Scan(&booked).Error; err != nil {
+		return false, err
+	}
+	// Only users hold tickets; guests book straight away
+	if owner.userID != "" {
+		if err := db.Model(&models.SeatHold{}).
+			Where("event_id = ? AND user_id = ? AND status = ?", event.ID, owner.userID, models.HoldStatusActive).
+			Select("COALESCE(SUM(quantity), 0)").
+			Scan(&held).Error; err != nil {
+			return false, err
+		}
+	}
+	return booked+held+int64(quantity) > int64(event.MaxTicketsPerUser), nil
+}
+
+func userLimitError(event *models.Event) models.CodedErrorResponse {
+	return models.CodedErrorResponse{
+		Error: fmt.Sprintf("You can book at most %d tickets for this event", event.MaxTicketsPerUser),
+		Code:  ErrCodeUserLimit,
+	}
+}
+
+// toResponse converts a booking with its event and ticket type loaded
+func toResponse(booking models.Booking) models.BookingResponse {
+	token, _ := ticketToken(&booking)
+	response := models.BookingResponse{
+		ID:               booking.ID,
+		UserID:           booking.UserID,
+		GuestName:        booking.GuestName,
+		GuestEmail:       booking.GuestEmail,
+		EventID:          booking.EventID,
+		Status:           booking.Status,
+		TicketType:       booking.TicketType,
+		Quantity:         booking.Quantity,
+		UnitPrice:        booking.UnitPrice,
+		Discount:         booking.Discount,
+		Total:            booking.Total,
+		PaymentExpiresAt: booking.PaymentExpiresAt,
+		PaidAt:           booking.PaidAt,
+		Event:            booking.Event,
+		CreatedAt:        booking.CreatedAt,
+		CancelledAt:      booking.CancelledAt,
+		CheckedInAt:      booking.CheckedInAt,
+		Refunds:          booking.Refunds,
+		Ticket:           token,
+		Seats:            bookedSeats(&booking),
+	}
+	for i := range booking.Attendees {
+		attendee := &booking.Attendees[i]
+		attendeeToken, _ := attendeeTicketToken(&booking, attendee)
+		response.Attendees = append(response.Attendees, models.AttendeeResponse{
+			ID:          attendee.ID,
+			Name:        attendee.Name,
+			Email:       attendee.Email,
+			CheckedInAt: attendee.CheckedInAt,
+			Ticket:      attendeeToken,
+		})
+	}
+	return response
+}
+
 // @Summary Create a booking
-// @Description Book an event for the authenticated user
+// @Description Book one or more tickets of an event for the authenticated user. Events with ticket types need a ticketTypeId. Bookings outside the event's or ticket type's sales window or for past events are rejected with a code of sales_not_started, sales_ended or event_passed, and bookings beyond the ticket type's quota or the event's capacity with sold_out. A promoCode is redeemed atomically with the booking; see the quote endpoint for its errors. A holdId books the tickets of the user's seat hold, failing with code hold_expired once it has expired or been used. Events with a seat map need seats, one per ticket and in the ticket type's zone, and fail with code seat_taken if one is held or booked. Bookings with a price are created as pending_payment with a payment intent and are released if not paid in time.
 // @Tags bookings
 // @Accept json
 // @Produce json
 // @Param request body models.CreateBookingRequest true "Booking details"
+// @Param Idempotency-Key header string false "Unique key that makes a retry of the request return its first response"
 // @Security Bearer
 // @Success 201 {object} models.BookingResponse
+// @Failure 409 {object} models.CodedErrorResponse
+// @Failure 502 {object} models.ErrorResponse
 // @Router /bookings [post]
 func CreateBookingHandler(c *gin.Context) {
 	var req models.CreateBookingRequest
@@ -28,54 +193,131 @@ func CreateBookingHandler(c *gin.Context) {
 	}
 
 	userID, _ := c.Get("userID")
+	createBooking(c, &req, models.Booking{UserID: userID.(string)}, "")
+}
 
-	// Check if event exists
-	var event models.Event
-	if err := database.GetDB().First(&event, "id = ?", req.EventID).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch event"})
+// createBooking books the request for the owner set on booking, a user or a
+// guest, and responds with it. A guest booking is created with the token of
+// its magic link, which is emailed to the guest.
+func createBooking(c *gin.Context, req *models.CreateBookingRequest, booking models.Booking, guestToken string) {
+	owner := ownerOf(&booking)
+	co, checkoutErr := prepareCheckout(req, owner, time.Now())
+	if checkoutErr != nil {
+		checkoutErr.respond(c)
 		return
 	}
 
-	// Check if user already has a booking for this event
-	var existingBooking models.Booking
-	err := database.GetDB().Where("user_id = ? AND event_id = ?", userID, req.EventID).First(&existingBooking).Error
-	if err == nil {
-		c.JSON(http.StatusConflict, gin.H{"error": "You have already booked this event"})
-		return
-	} else if err != gorm.ErrRecordNotFound {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check existing booking"})
-		return
+	booking.ID = uuid.New().String()
+	booking.EventID = req.EventID
+	booking.Status = models.BookingStatusConfirmed
+	booking.Quantity = co.quote.Quantity
+	booking.UnitPrice = co.quote.UnitPrice
+	booking.Discount = co.quote.Discount
+	booking.Total = co.quote.Total
+	if co.ticketType != nil {
+		booking.TicketTypeID = &co.ticketType.ID
+	}
+	if co.promo != nil {
+		booking.PromoCodeID = &co.promo.ID
+	}
+	for _, attendee := range req.Attendees {
+		booking.Attendees = append(booking.Attendees, models.Attendee{
+			ID:        uuid.New().String(),
+			BookingID: booking.ID,
+			Name:      strings.TrimSpace(attendee.Name),
+			Email:     strings.ToLower(strings.TrimSpace(attendee.Email)),
+		})
 	}
 
-	booking := models.Booking{
-		ID:      uuid.New().String(),
-		UserID:  userID.(string),
-		EventID: req.EventID,
+	// Paid bookings hold their tickets until the payment is confirmed or times out
+	if !booking.Total.IsZero() {
+		expiresAt := time.Now().Add(paymentTimeout())
+		booking.Status = models.BookingStatusPendingPayment
+		booking.PaymentExpiresAt = &expiresAt
 	}
 
-	if err := database.GetDB().Create(&booking).Error; err != nil {
+	err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if co.promo != nil {
+			if err := redeemPromo(tx, co.promo, owner); err != nil {
+				return err
+			}
+		}
+		// A held booking's tickets were reserved when the hold was made
+		if co.hold != nil {
+			if err := consumeHold(tx, co.hold, booking.ID, time.Now()); err != nil {
+				return err
+			}
+		} else {
+			if err := reserveTickets(tx, &co.event, co.ticketType, booking.Quantity); err != nil {
+				return err
+			}
+			if err := reserveSeats(tx, co.event.ID, co.seats, &booking.ID, nil); err != nil {
+				return err
+			}
+		}
+		// Checked again here so that concurrent orders cannot both fit
+		reached, err := userLimitReached(tx, &co.event, owner, booking.Quantity)
+		if err != nil {
+			return err
+		}
+		if reached {
+			return errUserLimit
+		}
+		if err := tx.Create(&booking).Error; err != nil {
+			return err
+		}
+		if guestToken != "" {
+			if err := sendGuestLink(tx, &booking, &co.event, guestToken); err != nil {
+				return err
+			}
+		}
+		if booking.Status == models.BookingStatusConfirmed {
+			return sendAttendeeTickets(tx, &booking, &co.event)
+		}
+		return nil
+	})
+	if err == errSoldOut {
+		c.JSON(http.StatusConflict, models.CodedErrorResponse{Error: "Not enough tickets left", Code: ErrCodeSoldOut})
+		return
+	}
+	if err == errUserLimit {
+		c.JSON(http.StatusConflict, userLimitError(&co.event))
+		return
+	}
+	if err == errPromoExhausted {
+		c.JSON(http.StatusConflict, models.CodedErrorResponse{Error: "Promo code has been used up", Code: ErrCodePromoExhausted})
+		return
+	}
+	if err == errHoldExpired {
+		c.JSON(http.StatusConflict, holdExpiredError)
+		return
+	}
+	if err == errSeatTaken {
+		c.JSON(http.StatusConflict, seatTakenError)
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create booking"})
 		return
 	}
 
+	var intent *payment.Intent
+	if booking.Status == models.BookingStatusPendingPayment {
+		if intent, err = startPayment(c.Request.Context(), &booking); err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Payment provider error: " + err.Error()})
+			return
+		}
+	}
+
 	// Load the event details for the response
-	if err := database.GetDB().Preload("Event").First(&booking, "id = ?", booking.ID).Error; err != nil {
+	if err := database.GetDB().Preload("Event").Preload("TicketType").Preload("Attendees").Preload("Seats.Seat").First(&booking, "id = ?", booking.ID).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch booking details"})
 		return
 	}
 
-	response := models.BookingResponse{
-		ID:        booking.ID,
-		UserID:    booking.UserID,
-		EventID:   booking.EventID,
-		Event:     booking.Event,
-		CreatedAt: booking.CreatedAt,
-	}
-
+	response := toResponse(booking)
+	response.Payment = intent
+	response.GuestToken = guestToken
 	c.JSON(http.StatusCreated, response)
 }
 
@@ -91,21 +333,41 @@ func GetUserBookingsHandler(c *gin.Context) {
 	userID, _ := c.Get("userID")
 
 	var bookings []models.Booking
-	if err := database.GetDB().Preload("Event.Tags").Where("user_id = ?", userID).Find(&bookings).Error; err != nil {
+	if err := database.GetDB().Preload("Event.Tags").Preload("TicketType").Preload("Refunds").Preload("Attendees").Preload("Seats.Seat").Where("user_id = ?", userID).Find(&bookings).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch bookings"})
 		return
 	}
 
 	var response []models.BookingResponse
 	for _, booking := range bookings {
-		response = append(response, models.BookingResponse{
-			ID:        booking.ID,
-			UserID:    booking.UserID,
-			EventID:   booking.EventID,
-			Event:     booking.Event,
-			CreatedAt: booking.CreatedAt,
-		})
+		response = append(response, toResponse(booking))
 	}
 
 	c.JSON(http.StatusOK, response)
-} 
\ No newline at end of file
+}
+
+// @Summary Get a booking
+// @Description Get a booking of the authenticated user, or a guest booking with the token from its magic link
+// @Tags bookings
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Param token query string false "Guest booking token, also accepted in the X-Booking-Token header"
+// @Security Bearer
+// @Success 200 {object} models.BookingResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /bookings/{id} [get]
+// @Router /guest/bookings/{id} [get]
+func GetBookingHandler(c *gin.Context) {
+	var booking models.Booking
+	if err := database.GetDB().Preload("Event.Tags").Preload("TicketType").Preload("Refunds").Preload("Attendees").Preload("Seats.Seat").
+		Scopes(ownBooking(c)).First(&booking).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch booking"})
+		return
+	}
+
+	c.JSON(http.StatusOK, toResponse(booking))
+}
@@ -0,0 +1,246 @@
+package booking
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+)
+
+func TestSalesWindowError(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	before := now.Add(-time.Hour)
+	after := now.Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		event      models.Event
+		ticketType *models.TicketType
+		want       string
+	}{
+		{
+			name:  "no window",
+			event: models.Event{Date: now.Add(24 * time.Hour)},
+			want:  "",
+		},
+		{
+			name:  "inside window",
+			event: models.Event{Date: now.Add(24 * time.Hour), SalesStartAt: &before, SalesEndAt: &after},
+			want:  "",
+		},
+		{
+			name:  "sales not started",
+			event: models.Event{Date: now.Add(24 * time.Hour), SalesStartAt: &after},
+			want:  ErrCodeSalesNotStarted,
+		},
+		{
+			name:  "sales ended",
+			event: models.Event{Date: now.Add(24 * time.Hour), SalesEndAt: &before},
+			want:  ErrCodeSalesEnded,
+		},
+		{
+			name:       "ticket type sales ended",
+			event:      models.Event{Date: now.Add(24 * time.Hour)},
+			ticketType: &models.TicketType{SalesEndAt: &before},
+			want:       ErrCodeSalesEnded,
+		},
+		{
+			name:  "event passed",
+			event: models.Event{Date: before, SalesEndAt: &after},
+			want:  ErrCodeEventPassed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if err := salesWindowError(&tt.event, tt.ticketType, now); err != nil {
+				got = err.Code
+			}
+			if got != tt.want {
+				t.Errorf("salesWindowError() code = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReserveTicketsEnforcesQuota(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&models.Event{}, &models.Booking{}, &models.TicketType{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	event := models.Event{ID: "event"}
+	if err := db.Create(&event).Error; err != nil {
+		t.Fatalf("failed to create event: %v", err)
+	}
+	ticketType := models.TicketType{ID: "vip", EventID: event.ID, Name: "VIP", Quota: 5}
+	if err := db.Create(&ticketType).Error; err != nil {
+		t.Fatalf("failed to create ticket type: %v", err)
+	}
+
+	steps := []struct {
+		quantity int
+		want     error
+	}{
+		{quantity: 3, want: nil},
+		{quantity: 3, want: errSoldOut},
+		{quantity: 2, want: nil},
+		{quantity: 1, want: errSoldOut},
+	}
+	for i, step := range steps {
+		if err := reserveTickets(db, &event, &ticketType, step.quantity); err != step.want {
+			t.Fatalf("step %d: reserveTickets(%d) error = %v, want %v", i, step.quantity, err, step.want)
+		}
+	}
+
+	db.First(&ticketType, "id = ?", ticketType.ID)
+	if ticketType.Sold != 5 {
+		t.Errorf("sold = %d, want 5", ticketType.Sold)
+	}
+}
+
+func TestReserveTicketsEnforcesCapacity(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&models.Event{}, &models.TicketType{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	event := models.Event{ID: "event", Capacity: 4}
+	if err := db.Create(&event).Error; err != nil {
+		t.Fatalf("failed to create event: %v", err)
+	}
+
+	steps := []struct {
+		quantity int
+		want     error
+	}{
+		{quantity: 3, want: nil},
+		{quantity: 2, want: errSoldOut},
+		{quantity: -1},
+		{quantity: 2, want: nil},
+		{quantity: 1, want: errSoldOut},
+	}
+	for i, step := range steps {
+		// A negative quantity gives tickets back
+		if step.quantity < 0 {
+			if err := returnTickets(db, event.ID, nil, -step.quantity); err != nil {
+				t.Fatalf("step %d: returnTickets(%d) error = %v", i, -step.quantity, err)
+			}
+			continue
+		}
+		if err := reserveTickets(db, &event, nil, step.quantity); err != step.want {
+			t.Fatalf("step %d: reserveTickets(%d) error = %v, want %v", i, step.quantity, err, step.want)
+		}
+	}
+
+	db.First(&event, "id = ?", event.ID)
+	if event.Reserved != 4 {
+		t.Errorf("reserved = %d, want 4", event.Reserved)
+	}
+}
+
+func book(r *gin.Engine, req models.CreateBookingRequest) (*httptest.ResponseRecorder, models.BookingResponse) {
+	body, _ := json.Marshal(req)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/bookings", bytes.NewReader(body)))
+
+	var booking models.BookingResponse
+	json.Unmarshal(w.Body.Bytes(), &booking)
+	return w, booking
+}
+
+func TestBookingLimits(t *testing.T) {
+	r, _ := setupPaymentTest(t)
+	database.GetDB().Model(&models.Event{}).Where("id = ?", "concert").
+		Updates(map[string]interface{}{"max_tickets_per_order": 3, "max_tickets_per_user": 4})
+	attendees := []models.AttendeeRequest{{Name: "Grace", Email: "grace@example.com"}, {Name: "Alan", Email: "alan@example.com"}}
+
+	tests := []struct {
+		name       string
+		quantity   int
+		attendees  []models.AttendeeRequest
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "over the order limit", quantity: 4, wantStatus: http.StatusBadRequest, wantCode: ErrCodeOrderLimit},
+		{name: "attendees do not match quantity", quantity: 3, attendees: attendees, wantStatus: http.StatusBadRequest},
+		{name: "first order", quantity: 3, wantStatus: http.StatusCreated},
+		{name: "over the user limit", attendees: attendees, wantStatus: http.StatusConflict, wantCode: ErrCodeUserLimit},
+		{name: "second order within the user limit", quantity: 1, wantStatus: http.StatusCreated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, _ := book(r, models.CreateBookingRequest{EventID: "concert", TicketTypeID: "standard", Quantity: tt.quantity, Attendees: tt.attendees})
+			var refused models.CodedErrorResponse
+			json.Unmarshal(w.Body.Bytes(), &refused)
+			if w.Code != tt.wantStatus || refused.Code != tt.wantCode {
+				t.Errorf("got %d %q, want %d %q, body %s", w.Code, refused.Code, tt.wantStatus, tt.wantCode, w.Body.String())
+			}
+		})
+	}
+
+	if sold := soldTickets(t); sold != 4 {
+		t.Errorf("sold = %d, want 4", sold)
+	}
+}
+
+func TestGroupBookingTickets(t *testing.T) {
+	r, _ := setupPaymentTest(t)
+
+	w, booking := book(r, models.CreateBookingRequest{EventID: "concert", TicketTypeID: "standard", Attendees: []models.AttendeeRequest{
+		{Name: "Grace Hopper", Email: "Grace@Example.com"},
+		{Name: "Alan Turing", Email: "alan@example.com"},
+	}})
+	if w.Code != http.StatusCreated || booking.Quantity != 2 || len(booking.Attendees) != 2 || booking.Attendees[0].Ticket != "" {
+		t.Fatalf("got %d %+v, want a pending booking of 2 named tickets without tickets yet", w.Code, booking)
+	}
+
+	body, _ := json.Marshal(models.PayBookingRequest{PaymentMethod: "pm_card_visa"})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/bookings/"+booking.ID+"/pay", bytes.NewReader(body)))
+
+	var confirmed models.Booking
+	database.GetDB().Preload("Attendees").First(&confirmed, "id = ?", booking.ID)
+	response := toResponse(confirmed)
+	if response.Status != models.BookingStatusConfirmed || response.Ticket != "" {
+		t.Fatalf("booking = %+v, want confirmed with no booking-wide ticket", response)
+	}
+
+	var sent []models.Notification
+	database.GetDB().Where("type = ?", models.NotificationTicketIssued).Order("email").Find(&sent)
+	if len(sent) != 2 || sent[0].Email != "alan@example.com" || sent[1].Email != "grace@example.com" {
+		t.Fatalf("sent %+v, want a ticket to each attendee", sent)
+	}
+	for _, attendee := range response.Attendees {
+		claims, err := VerifyTicket(attendee.Ticket)
+		if err != nil || claims.AttendeeID != attendee.ID || claims.Quantity != 1 {
+			t.Errorf("ticket of %s has claims %+v, %v", attendee.Name, claims, err)
+		}
+		found := false
+		for _, n := range sent {
+			found = found || strings.HasSuffix(n.Message, attendee.Ticket)
+		}
+		if !found {
+			t.Errorf("no message carries %s's ticket", attendee.Name)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package booking
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+)
+
+// ErrCodeHoldExpired is returned when a seat hold can no longer be booked
+const ErrCodeHoldExpired = "hold_expired"
+
+var errHoldExpired = errors.New("seat hold expired")
+
+var holdExpiredError = models.CodedErrorResponse{Error: "Seat hold has expired or was already used", Code: ErrCodeHoldExpired}
+
+// holdTTL is how long a seat hold keeps its tickets, from HOLD_TTL_MINUTES
+// (default 10)
+func holdTTL() time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv("HOLD_TTL_MINUTES"))
+	if err != nil || minutes <= 0 {
+		minutes = 10
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// useHold checks that the checkout can book the owner's seat hold, which
+// sets the quantity if none was asked for
+func (co *checkout) useHold(holdID string, owner bookingOwner, now time.Time) *checkoutError {
+	var hold models.SeatHold
+	if err := database.GetDB().First(&hold, "id = ? AND user_id = ? AND event_id = ?", holdID, owner.userID, co.event.ID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return &checkoutError{http.StatusNotFound, gin.H{"error": "Seat hold not found"}}
+		}
+		return &checkoutError{http.StatusInternalServerError, gin.H{"error": "Failed to fetch seat hold"}}
+	}
+
+	if hold.Status != models.HoldStatusActive || !hold.ExpiresAt.After(now) {
+		return &checkoutError{http.StatusConflict, holdExpiredError}
+	}
+	if (hold.TicketTypeID == nil) != (co.ticketType == nil) || (co.ticketType != nil && *hold.TicketTypeID != co.ticketType.ID) {
+		return &checkoutError{http.StatusBadRequest, gin.H{"error": "Seat hold is for another ticket type"}}
+	}
+	if co.quote.Quantity == 0 {
+		co.quote.Quantity = hold.Quantity
+	}
+	if co.quote.Quantity != hold.Quantity {
+		return &checkoutError{http.StatusBadRequest, gin.H{"error": "Quantity must match the seat hold"}}
+	}
+
+	co.hold = &hold
+	return nil
+}
+
+// consumeHold hands a hold's tickets to the booking made with it, failing
+// with errHoldExpired if it has expired or was used in the meantime
+func consumeHold(tx *gorm.DB, hold *models.SeatHold, bookingID string, now time.Time) error {
+	result := tx.Model(&models.SeatHold{}).
+		Where("id = ? AND status = ? AND expires_at > ?", hold.ID, models.HoldStatusActive, now).
+		Updates(map[string]interface{}{"status": models.HoldStatusConsumed, "booking_id": bookingID})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errHoldExpired
+	}
+	return tx.Model(&models.SeatReservation{}).
+		Where("hold_id = ?", hold.ID).
+		Updates(map[string]interface{}{"booking_id": bookingID, "hold_id": nil}).Error
+}
+
+// releaseHold ends an active hold with the given status and returns its
+// tickets to the event and quota. It reports false if the hold was no longer
+// active.
+func releaseHold(tx *gorm.DB, hold *models.SeatHold, status string) (bool, error) {
+	result := tx.Model(&models.SeatHold{}).
+		Where("id = ? AND status = ?", hold.ID, models.HoldStatusActive).
+		Update("status", status)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+	if err := tx.Where("hold_id = ?", hold.ID).Delete(&models.SeatReservation{}).Error; err != nil {
+		return false, err
+	}
+	return true, returnTickets(tx, hold.EventID, hold.TicketTypeID, hold.Quantity)
+}
+
+// expireHolds releases holds whose time has run out and returns how many
+// were released
+func expireHolds(now time.Time) (int, error) {
+	var holds []models.SeatHold
+	if err := database.GetDB().
+		Where("status = ? AND expires_at <= ?", models.HoldStatusActive, now).
+		Find(&holds).Error; err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for i := range holds {
+		err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+			ok, err := releaseHold(tx, &holds[i], models.HoldStatusExpired)
+			if ok {
+				released++
+			}
+			return err
+		})
+		if err != nil {
+			return released, err
+		}
+	}
+	return released, nil
+}
+
+// StartHoldReaper periodically releases the tickets of expired seat holds
+func StartHoldReaper(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			released, err := expireHolds(time.Now())
+			if err != nil {
+				log.Printf("Failed to release expired seat holds: %v", err)
+			} else if released > 0 {
+				log.Printf("Released %d expired seat holds", released)
+			}
+		}
+	}()
+}
+
+// @Summary Hold tickets
+// @Description Set tickets aside for the authenticated user while they check out. The hold keeps its tickets for HOLD_TTL_MINUTES (default 10); book them by passing its id as holdId when creating the booking. Holds count against the event's capacity, the ticket type's quota and the user's ticket limit, and are refused with the booking errors of POST /bookings. At events with a seat map the hold keeps the chosen seats.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param request body models.CreateHoldRequest true "Tickets to hold"
+// @Param Idempotency-Key header string false "Unique key that makes a retry of the request return its first response"
+// @Security Bearer
+// @Success 201 {object} models.SeatHold
+// @Failure 400 {object} models.CodedErrorResponse
+// @Failure 409 {object} models.CodedErrorResponse
+// @Router /bookings/holds [post]
+func CreateHoldHandler(c *gin.Context) {
+	var req models.CreateHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	owner := bookingOwner{userID: userID.(string)}
+	now := time.Now()
+	co, checkoutErr := prepareCheckout(&models.CreateBookingRequest{
+		EventID:      req.EventID,
+		TicketTypeID: req.TicketTypeID,
+		Quantity:     req.Quantity,
+		Seats:        req.Seats,
+	}, owner, now)
+	if checkoutErr != nil {
+		checkoutErr.respond(c)
+		return
+	}
+
+	hold := models.SeatHold{
+		ID:        uuid.New().String(),
+		EventID:   co.event.ID,
+		UserID:    owner.userID,
+		Quantity:  co.quote.Quantity,
+		Status:    models.HoldStatusActive,
+		ExpiresAt: now.Add(holdTTL()),
+	}
+	if co.ticketType != nil {
+		hold.TicketTypeID = &co.ticketType.ID
+	}
+
+	err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := reserveTickets(tx, &co.event, co.ticketType, hold.Quantity); err != nil {
+			return err
+		}
+		if err := reserveSeats(tx, co.event.ID, co.seats, nil, &hold.ID); err != nil {
+			return err
+		}
+		reached, err := userLimitReached(tx, &co.event, owner, hold.Quantity)
+		if err != nil {
+			return err
+		}
+		if reached {
+			return errUserLimit
+		}
+		return tx.Create(&hold).Error
+	})
+	if err == errSoldOut {
+		c.JSON(http.StatusConflict, models.CodedErrorResponse{Error: "Not enough tickets left", Code: ErrCodeSoldOut})
+		return
+	}
+	if err == errUserLimit {
+		c.JSON(http.StatusConflict, userLimitError(&co.event))
+		return
+	}
+	if err == errSeatTaken {
+		c.JSON(http.StatusConflict, seatTakenError)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hold tickets"})
+		return
+	}
+
+	for _, seat := range co.seats {
+		seat := seat
+		hold.Seats = append(hold.Seats, models.SeatReservation{EventID: hold.EventID, SeatID: seat.ID, HoldID: &hold.ID, Seat: &seat})
+	}
+	c.JSON(http.StatusCreated, hold)
+}
+
+// @Summary Release a seat hold
+// @Description Give the tickets of one of the authenticated user's active seat holds back before it expires
+// @Tags bookings
+// @Produce json
+// @Param id path string true "Hold ID"
+// @Security Bearer
+// @Success 200 {object} models.SeatHold
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.CodedErrorResponse
+// @Router /bookings/holds/{id} [delete]
+func ReleaseHoldHandler(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var hold models.SeatHold
+	if err := database.GetDB().First(&hold, "id = ? AND user_id = ?", c.Param("id"), userID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Seat hold not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch seat hold"})
+		return
+	}
+
+	var released bool
+	err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		var err error
+		released, err = releaseHold(tx, &hold, models.HoldStatusReleased)
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to release seat hold"})
+		return
+	}
+	if !released {
+		c.JSON(http.StatusConflict, holdExpiredError)
+		return
+	}
+
+	hold.Status = models.HoldStatusReleased
+	c.JSON(http.StatusOK, hold)
+}
@@ -0,0 +1,120 @@
+package booking
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+)
+
+func hold(r *gin.Engine, req models.CreateHoldRequest) (*httptest.ResponseRecorder, models.SeatHold) {
+	body, _ := json.Marshal(req)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/bookings/holds", bytes.NewReader(body)))
+
+	var held models.SeatHold
+	json.Unmarshal(w.Body.Bytes(), &held)
+	return w, held
+}
+
+func holdStatus(t *testing.T, id string) string {
+	t.Helper()
+	var held models.SeatHold
+	if err := database.GetDB().First(&held, "id = ?", id).Error; err != nil {
+		t.Fatalf("failed to fetch hold: %v", err)
+	}
+	return held.Status
+}
+
+func TestSeatHolds(t *testing.T) {
+	r, _ := setupPaymentTest(t)
+	database.GetDB().Model(&models.Event{}).Where("id = ?", "concert").
+		Updates(map[string]interface{}{"capacity": 6, "max_tickets_per_user": 5})
+
+	w, held := hold(r, models.CreateHoldRequest{EventID: "concert", TicketTypeID: "standard", Quantity: 4})
+	if w.Code != http.StatusCreated || held.Status != models.HoldStatusActive || !held.ExpiresAt.After(time.Now()) {
+		t.Fatalf("got %d %+v, want an active hold", w.Code, held)
+	}
+	if sold := soldTickets(t); sold != 4 {
+		t.Fatalf("sold = %d, want the 4 held tickets taken", sold)
+	}
+
+	tests := []struct {
+		name       string
+		req        models.CreateBookingRequest
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "past the user limit with the hold", req: models.CreateBookingRequest{Quantity: 2}, wantStatus: http.StatusConflict, wantCode: ErrCodeUserLimit},
+		{name: "other quantity", req: models.CreateBookingRequest{HoldID: held.ID, Quantity: 2}, wantStatus: http.StatusBadRequest},
+		{name: "unknown hold", req: models.CreateBookingRequest{HoldID: "missing"}, wantStatus: http.StatusNotFound},
+		{name: "held tickets", req: models.CreateBookingRequest{HoldID: held.ID}, wantStatus: http.StatusCreated},
+		{name: "hold used twice", req: models.CreateBookingRequest{HoldID: held.ID}, wantStatus: http.StatusConflict, wantCode: ErrCodeHoldExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.EventID, tt.req.TicketTypeID = "concert", "standard"
+			w, _ := book(r, tt.req)
+			var refused models.CodedErrorResponse
+			json.Unmarshal(w.Body.Bytes(), &refused)
+			if w.Code != tt.wantStatus || refused.Code != tt.wantCode {
+				t.Errorf("got %d %q, want %d %q, body %s", w.Code, refused.Code, tt.wantStatus, tt.wantCode, w.Body.String())
+			}
+		})
+	}
+
+	if got := holdStatus(t, held.ID); got != models.HoldStatusConsumed {
+		t.Errorf("hold status = %s, want consumed", got)
+	}
+	if sold := soldTickets(t); sold != 4 {
+		t.Errorf("sold = %d, want the booking to take over the held tickets", sold)
+	}
+
+	// Another user's hold takes the last of the capacity
+	database.GetDB().Create(&models.SeatHold{ID: "other", EventID: "concert", UserID: "user-2", Quantity: 2,
+		Status: models.HoldStatusActive, ExpiresAt: time.Now().Add(time.Minute)})
+	database.GetDB().Model(&models.Event{}).Where("id = ?", "concert").Update("reserved", 6)
+	if w, _ := book(r, models.CreateBookingRequest{EventID: "concert", TicketTypeID: "standard", Quantity: 1}); w.Code != http.StatusConflict {
+		t.Errorf("booking past held capacity: status = %d, want 409", w.Code)
+	}
+}
+
+func TestSeatHoldsAreReleased(t *testing.T) {
+	r, _ := setupPaymentTest(t)
+	_, expiring := hold(r, models.CreateHoldRequest{EventID: "concert", TicketTypeID: "standard", Quantity: 3})
+	_, released := hold(r, models.CreateHoldRequest{EventID: "concert", TicketTypeID: "standard", Quantity: 2})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/bookings/holds/"+released.ID, nil))
+	if w.Code != http.StatusOK || holdStatus(t, released.ID) != models.HoldStatusReleased {
+		t.Fatalf("release status = %d, body %s", w.Code, w.Body.String())
+	}
+	if sold := soldTickets(t); sold != 3 {
+		t.Errorf("sold = %d, want the released tickets back", sold)
+	}
+
+	n, err := expireHolds(time.Now().Add(holdTTL() + time.Second))
+	if err != nil || n != 1 {
+		t.Fatalf("expireHolds() = %d, %v, want 1", n, err)
+	}
+	if got := holdStatus(t, expiring.ID); got != models.HoldStatusExpired {
+		t.Errorf("hold status = %s, want expired", got)
+	}
+	if sold := soldTickets(t); sold != 0 {
+		t.Errorf("sold = %d, want every held ticket released", sold)
+	}
+
+	w, _ = book(r, models.CreateBookingRequest{EventID: "concert", TicketTypeID: "standard", HoldID: expiring.ID})
+	var refused models.CodedErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &refused)
+	if w.Code != http.StatusConflict || refused.Code != ErrCodeHoldExpired {
+		t.Errorf("booking an expired hold: got %d %q, want 409 %q", w.Code, refused.Code, ErrCodeHoldExpired)
+	}
+}
@@ -0,0 +1,279 @@
+package booking
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+	"online-task/pkg/payment"
+)
+
+// maxWebhookBytes bounds the size of a payment webhook body
+const maxWebhookBytes = 1 << 20
+
+// paymentProvider takes payment for bookings of paid events
+var paymentProvider payment.Provider = payment.NewFake()
+
+// SetPaymentProvider configures the provider used for paid bookings
+func SetPaymentProvider(p payment.Provider) {
+	paymentProvider = p
+}
+
+// paymentTimeout is how long a booking may await payment before its tickets
+// are released, from PAYMENT_TIMEOUT_MINUTES (default 15)
+func paymentTimeout() time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv("PAYMENT_TIMEOUT_MINUTES"))
+	if err != nil || minutes <= 0 {
+		minutes = 15
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// releaseTickets returns the tickets of a booking to its ticket type's quota,
+// frees its seats and gives back the use of its promo code
+func releaseTickets(tx *gorm.DB, booking *models.Booking) error {
+	if booking.PromoCodeID != nil {
+		if err := tx.Model(&models.PromoCode{}).
+			Where("id = ? AND uses > 0", *booking.PromoCodeID).
+			Update("uses", gorm.Expr("uses - 1")).Error; err != nil {
+			return err
+		}
+	}
+	return releaseSeats(tx, booking)
+}
+
+// releaseSeats returns the tickets of a booking to its event's capacity and
+// ticket type's quota and frees its seats
+func releaseSeats(tx *gorm.DB, booking *models.Booking) error {
+	if err := tx.Where("booking_id = ?", booking.ID).Delete(&models.SeatReservation{}).Error; err != nil {
+		return err
+	}
+	return returnTickets(tx, booking.EventID, booking.TicketTypeID, booking.Quantity)
+}
+
+// cancelPendingBooking cancels a booking that is still awaiting payment and
+// releases its tickets. It reports false if the booking was no longer pending.
+func cancelPendingBooking(tx *gorm.DB, booking *models.Booking, now time.Time) (bool, error) {
+	result := tx.Model(&models.Booking{}).
+		Where("id = ? AND status = ?", booking.ID, models.BookingStatusPendingPayment).
+		Updates(map[string]interface{}{"status": models.BookingStatusCancelled, "cancelled_at": now})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+	return true, releaseTickets(tx, booking)
+}
+
+// startPayment creates the payment intent of a new pending booking. If the
+// provider fails the booking is cancelled so its tickets are not held.
+func startPayment(ctx context.Context, booking *models.Booking) (*payment.Intent, error) {
+	intent, err := paymentProvider.CreateIntent(ctx, booking.Total, booking.ID)
+	if err == nil {
+		booking.PaymentProvider = paymentProvider.Name()
+		booking.PaymentIntentID = &intent.ID
+		err = database.GetDB().Model(booking).Updates(map[string]interface{}{
+			"payment_provider":  booking.PaymentProvider,
+			"payment_intent_id": intent.ID,
+		}).Error
+		if err == nil {
+			return &intent, nil
+		}
+	}
+
+	database.GetDB().Transaction(func(tx *gorm.DB) error {
+		_, err := cancelPendingBooking(tx, booking, time.Now())
+		return err
+	})
+	return nil, err
+}
+
+// expirePendingBookings cancels bookings whose payment window has passed and
+// returns how many were released
+func expirePendingBookings(now time.Time) (int, error) {
+	var bookings []models.Booking
+	if err := database.GetDB().
+		Where("status = ? AND payment_expires_at <= ?", models.BookingStatusPendingPayment, now).
+		Find(&bookings).Error; err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for i := range bookings {
+		err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+			cancelled, err := cancelPendingBooking(tx, &bookings[i], now)
+			if cancelled {
+				released++
+			}
+			return err
+		})
+		if err != nil {
+			return released, err
+		}
+	}
+	return released, nil
+}
+
+// StartPaymentReaper periodically releases bookings that were never paid
+// and retries refunds the provider has not completed
+func StartPaymentReaper(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			released, err := expirePendingBookings(time.Now())
+			if err != nil {
+				log.Printf("Failed to release unpaid bookings: %v", err)
+			} else if released > 0 {
+				log.Printf("Released %d unpaid bookings", released)
+			}
+
+			if err := retryRefunds(context.Background()); err != nil {
+				log.Printf("Failed to retry refunds: %v", err)
+			}
+		}
+	}()
+}
+
+// confirmPayment confirms the booking paid with an intent. Payments that
+// arrive after the booking was released or cancelled are refunded.
+func confirmPayment(ctx context.Context, intentID string) error {
+	var booking models.Booking
+	if err := database.GetDB().Preload("Event").Preload("Attendees").First(&booking, "payment_intent_id = ?", intentID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			// Not a booking payment; nothing to do
+			return nil
+		}
+		return err
+	}
+
+	now := time.Now()
+	confirmed := false
+	err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Booking{}).
+			Where("id = ? AND status = ?", booking.ID, models.BookingStatusPendingPayment).
+			Updates(map[string]interface{}{"status": models.BookingStatusConfirmed, "paid_at": now})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		confirmed = true
+		booking.Status = models.BookingStatusConfirmed
+		return sendAttendeeTickets(tx, &booking, &booking.Event)
+	})
+	if err != nil || confirmed {
+		return err
+	}
+
+	// Recording the late payment and its refund together keeps redelivered
+	// webhooks from refunding it twice
+	var refund *models.Refund
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		claim := tx.Model(&models.Booking{}).
+			Where("id = ? AND status = ? AND paid_at IS NULL", booking.ID, models.BookingStatusCancelled).
+			Update("paid_at", now)
+		if claim.Error != nil || claim.RowsAffected == 0 {
+			return claim.Error
+		}
+		var err error
+		refund, err = RecordRefund(tx, &booking, 100, models.RefundReasonLatePayment)
+		return err
+	})
+	if err != nil || refund == nil {
+		return err
+	}
+	issueRefund(ctx, refund, intentID)
+	return nil
+}
+
+// @Summary Payment webhook
+// @Description Receives signed payment notifications from the payment provider. A succeeded payment confirms its pending booking; payments for bookings that were already released are refunded.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Router /payments/webhook [post]
+func PaymentWebhookHandler(c *gin.Context) {
+	payload, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBytes))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read webhook"})
+		return
+	}
+
+	event, err := paymentProvider.VerifyWebhook(payload, c.Request.Header)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook signature"})
+		return
+	}
+
+	if event.Type == payment.EventPaymentSucceeded {
+		if err := confirmPayment(c.Request.Context(), event.IntentID); err != nil {
+			// A non-2xx status makes the provider retry the webhook
+			log.Printf("Failed to process payment %s: %v", event.IntentID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process payment"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: "Webhook processed"})
+}
+
+// @Summary Pay for a booking
+// @Description Charge a payment method for a booking awaiting payment. The booking is confirmed once the provider reports the payment by webhook.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Param request body models.PayBookingRequest false "Payment method"
+// @Param token query string false "Guest booking token, also accepted in the X-Booking-Token header"
+// @Param Idempotency-Key header string false "Unique key that makes a retry of the request return its first response"
+// @Security Bearer
+// @Success 202 {object} models.BookingResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 502 {object} models.ErrorResponse
+// @Router /bookings/{id}/pay [post]
+// @Router /guest/bookings/{id}/pay [post]
+func PayBookingHandler(c *gin.Context) {
+	var req models.PayBookingRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	var booking models.Booking
+	if err := database.GetDB().Preload("Event").Preload("TicketType").Preload("Attendees").
+		Scopes(ownBooking(c)).First(&booking).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch booking"})
+		return
+	}
+
+	if booking.Status != models.BookingStatusPendingPayment || booking.PaymentIntentID == nil ||
+		(booking.PaymentExpiresAt != nil && !time.Now().Before(*booking.PaymentExpiresAt)) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Booking is not awaiting payment"})
+		return
+	}
+
+	intent, err := paymentProvider.Confirm(c.Request.Context(), *booking.PaymentIntentID, req.PaymentMethod)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Payment provider error: " + err.Error()})
+		return
+	}
+
+	response := toResponse(booking)
+	response.Payment = &intent
+	c.JSON(http.StatusAccepted, response)
+}
@@ -0,0 +1,183 @@
+package booking
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+	"online-task/pkg/money"
+	"online-task/pkg/payment"
+)
+
+// setupPaymentTest returns a router for a signed-in user and a fake provider
+// whose webhooks are delivered to it
+func setupPaymentTest(t *testing.T) (*gin.Engine, *payment.FakeProvider) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&models.Event{}, &models.TicketType{}, &models.Booking{}, &models.Attendee{}, &models.SeatHold{}, &models.SeatMap{}, &models.Seat{}, &models.SeatReservation{}, &models.Refund{}, &models.PromoCode{}, &models.Tag{}, &models.Notification{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	database.DB = db
+
+	event := models.Event{ID: "concert", Name: "Concert", Date: time.Now().Add(48 * time.Hour), Status: models.EventStatusPublished}
+	if err := db.Create(&event).Error; err != nil {
+		t.Fatalf("failed to create event: %v", err)
+	}
+	ticketType := models.TicketType{ID: "standard", EventID: event.ID, Name: "Standard", Price: money.New(2000, "USD"), Quota: 10}
+	if err := db.Create(&ticketType).Error; err != nil {
+		t.Fatalf("failed to create ticket type: %v", err)
+	}
+
+	r := gin.New()
+	r.POST("/api/payments/webhook", PaymentWebhookHandler)
+	user := r.Group("/api/bookings", func(c *gin.Context) { c.Set("userID", "user-1") })
+	user.POST("", CreateBookingHandler)
+	user.POST("/quote", QuoteBookingHandler)
+	user.POST("/holds", CreateHoldHandler)
+	user.DELETE("/holds/:id", ReleaseHoldHandler)
+	user.POST("/:id/pay", PayBookingHandler)
+	user.DELETE("/:id", CancelBookingHandler)
+
+	fake := payment.NewFake()
+	fake.Deliver = func(payload []byte, header http.Header) {
+		req := httptest.NewRequest(http.MethodPost, "/api/payments/webhook", bytes.NewReader(payload))
+		req.Header = header
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	SetPaymentProvider(fake)
+	return r, fake
+}
+
+func createPaidBooking(t *testing.T, r *gin.Engine) models.BookingResponse {
+	t.Helper()
+	body, _ := json.Marshal(models.CreateBookingRequest{EventID: "concert", TicketTypeID: "standard", Quantity: 2})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/bookings", bytes.NewReader(body)))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create booking status = %d, body %s", w.Code, w.Body.String())
+	}
+
+	var booking models.BookingResponse
+	json.Unmarshal(w.Body.Bytes(), &booking)
+	if booking.Status != models.BookingStatusPendingPayment || booking.Payment == nil || booking.Total != money.New(4000, "USD") {
+		t.Fatalf("got booking %+v, want a pending booking with a 40.00 USD payment", booking)
+	}
+	return booking
+}
+
+func bookingStatus(t *testing.T, id string) string {
+	t.Helper()
+	var booking models.Booking
+	if err := database.GetDB().First(&booking, "id = ?", id).Error; err != nil {
+		t.Fatalf("failed to fetch booking: %v", err)
+	}
+	return booking.Status
+}
+
+func soldTickets(t *testing.T) int {
+	t.Helper()
+	var ticketType models.TicketType
+	database.GetDB().First(&ticketType, "id = ?", "standard")
+	return ticketType.Sold
+}
+
+func TestPaidBookingConfirmedByWebhook(t *testing.T) {
+	tests := []struct {
+		name          string
+		paymentMethod string
+		want          string
+	}{
+		{name: "payment succeeds", paymentMethod: "pm_card_visa", want: models.BookingStatusConfirmed},
+		{name: "card declined", paymentMethod: payment.FakeDeclinedPaymentMethod, want: models.BookingStatusPendingPayment},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := setupPaymentTest(t)
+			booking := createPaidBooking(t, r)
+
+			body, _ := json.Marshal(models.PayBookingRequest{PaymentMethod: tt.paymentMethod})
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/bookings/"+booking.ID+"/pay", bytes.NewReader(body)))
+			if w.Code != http.StatusAccepted {
+				t.Fatalf("pay status = %d, body %s", w.Code, w.Body.String())
+			}
+			if got := bookingStatus(t, booking.ID); got != tt.want {
+				t.Errorf("status = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaymentWebhookRejectsBadSignature(t *testing.T) {
+	r, fake := setupPaymentTest(t)
+	booking := createPaidBooking(t, r)
+
+	payload, header := fake.Webhook(payment.EventPaymentSucceeded, booking.Payment.ID)
+	header.Set(payment.SignatureHeader, payment.Sign("not-the-secret", payload, time.Now()))
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/payments/webhook", bytes.NewReader(payload))
+	req.Header = header
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+	if got := bookingStatus(t, booking.ID); got != models.BookingStatusPendingPayment {
+		t.Errorf("status = %s, want the booking to stay pending", got)
+	}
+}
+
+func TestUnpaidBookingsAreReleased(t *testing.T) {
+	r, fake := setupPaymentTest(t)
+	booking := createPaidBooking(t, r)
+	if sold := soldTickets(t); sold != 2 {
+		t.Fatalf("sold = %d, want 2 held by the pending booking", sold)
+	}
+
+	released, err := expirePendingBookings(time.Now().Add(paymentTimeout() + time.Second))
+	if err != nil || released != 1 {
+		t.Fatalf("expirePendingBookings() = %d, %v, want 1", released, err)
+	}
+	if got := bookingStatus(t, booking.ID); got != models.BookingStatusCancelled {
+		t.Errorf("status = %s, want cancelled", got)
+	}
+	if sold := soldTickets(t); sold != 0 {
+		t.Errorf("sold = %d, want the tickets released", sold)
+	}
+
+	// A payment that arrives after the booking was released is refunded once,
+	// however often the webhook is delivered
+	if _, err := fake.Confirm(context.Background(), booking.Payment.ID, ""); err != nil {
+		t.Fatal(err)
+	}
+	fake.Deliver(fake.Webhook(payment.EventPaymentSucceeded, booking.Payment.ID))
+	if _, err := fake.Refund(context.Background(), booking.Payment.ID, money.New(1, "USD"), "extra"); err == nil {
+		t.Error("late payment was not refunded in full")
+	}
+	var refunds []models.Refund
+	database.GetDB().Find(&refunds, "booking_id = ?", booking.ID)
+	if len(refunds) != 1 || refunds[0].Status != models.RefundStatusSucceeded || refunds[0].Reason != models.RefundReasonLatePayment {
+		t.Errorf("refunds = %+v, want one succeeded late payment refund", refunds)
+	}
+	if got := bookingStatus(t, booking.ID); got != models.BookingStatusCancelled {
+		t.Errorf("status = %s, want the released booking to stay cancelled", got)
+	}
+}
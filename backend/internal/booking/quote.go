@@ -0,0 +1,252 @@
+package booking
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+)
+
+// Error codes returned when a promo code cannot be used
+const (
+	ErrCodePromoInvalid       = "promo_invalid"
+	ErrCodePromoNotApplicable = "promo_not_applicable"
+	ErrCodePromoExhausted     = "promo_exhausted"
+)
+
+var errPromoExhausted = errors.New("promo code exhausted")
+
+// checkout is a booking request resolved to its event, ticket type, promo
+// code and price
+type checkout struct {
+	event      models.Event
+	ticketType *models.TicketType
+	promo      *models.PromoCode
+	hold       *models.SeatHold
+	seats      []models.Seat
+	quote      models.BookingQuote
+}
+
+// checkoutError is a rejected booking request with the status to respond with
+type checkoutError struct {
+	status int
+	body   interface{}
+}
+
+func (e *checkoutError) respond(c *gin.Context) {
+	c.JSON(e.status, e.body)
+}
+
+// prepareCheckout checks that the event can be booked with the requested
+// ticket type and promo code and prices the booking
+func prepareCheckout(req *models.CreateBookingRequest, owner bookingOwner, now time.Time) (*checkout, *checkoutError) {
+	var co checkout
+	if err := database.GetDB().Preload("Tags").First(&co.event, "id = ?", req.EventID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &checkoutError{http.StatusNotFound, gin.H{"error": "Event not found"}}
+		}
+		return nil, &checkoutError{http.StatusInternalServerError, gin.H{"error": "Failed to fetch event"}}
+	}
+
+	// Only published events are open for booking
+	if co.event.Status != models.EventStatusPublished {
+		return nil, &checkoutError{http.StatusConflict, gin.H{"error": "Event is not open for booking"}}
+	}
+
+	if req.TicketTypeID != "" {
+		co.ticketType = &models.TicketType{}
+		if err := database.GetDB().First(co.ticketType, "id = ? AND event_id = ?", req.TicketTypeID, co.event.ID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, &checkoutError{http.StatusBadRequest, gin.H{"error": "Ticket type not found for this event"}}
+			}
+			return nil, &checkoutError{http.StatusInternalServerError, gin.H{"error": "Failed to fetch ticket type"}}
+		}
+	} else {
+		var typeCount int64
+		if err := database.GetDB().Model(&models.TicketType{}).Where("event_id = ?", co.event.ID).Count(&typeCount).Error; err != nil {
+			return nil, &checkoutError{http.StatusInternalServerError, gin.H{"error": "Failed to fetch ticket types"}}
+		}
+		if typeCount > 0 {
+			return nil, &checkoutError{http.StatusBadRequest, gin.H{"error": "ticketTypeId is required for this event"}}
+		}
+	}
+
+	if windowErr := salesWindowError(&co.event, co.ticketType, now); windowErr != nil {
+		return nil, &checkoutError{http.StatusConflict, windowErr}
+	}
+
+	co.quote = models.BookingQuote{
+		EventID:   co.event.ID,
+		Quantity:  req.Quantity,
+		UnitPrice: co.event.Price,
+	}
+	if req.HoldID != "" {
+		if err := co.useHold(req.HoldID, owner, now); err != nil {
+			return nil, err
+		}
+	}
+	if err := co.chooseSeats(req.Seats); err != nil {
+		return nil, err
+	}
+	if co.quote.Quantity == 0 {
+		co.quote.Quantity = len(co.seats)
+	}
+	if co.quote.Quantity == 0 {
+		co.quote.Quantity = len(req.Attendees)
+	}
+	if co.quote.Quantity == 0 {
+		co.quote.Quantity = 1
+	}
+	if len(co.seats) > 0 && len(co.seats) != co.quote.Quantity {
+		return nil, &checkoutError{http.StatusBadRequest, gin.H{"error": "Choose a seat for every ticket"}}
+	}
+	if len(req.Attendees) > 0 && len(req.Attendees) != co.quote.Quantity {
+		return nil, &checkoutError{http.StatusBadRequest, gin.H{"error": "Name one attendee for every ticket"}}
+	}
+	if limit := co.event.OrderLimit(); co.quote.Quantity > limit {
+		return nil, &checkoutError{http.StatusBadRequest, models.CodedErrorResponse{
+			Error: fmt.Sprintf("At most %d tickets can be booked at once", limit),
+			Code:  ErrCodeOrderLimit,
+		}}
+	}
+	// Held tickets already count towards the user's limit
+	if co.hold == nil {
+		reached, err := userLimitReached(database.GetDB(), &co.event, owner, co.quote.Quantity)
+		if err != nil {
+			return nil, &checkoutError{http.StatusInternalServerError, gin.H{"error": "Failed to count your bookings"}}
+		}
+		if reached {
+			return nil, &checkoutError{http.StatusConflict, userLimitError(&co.event)}
+		}
+	}
+	if co.ticketType != nil {
+		co.quote.TicketTypeID = co.ticketType.ID
+		co.quote.UnitPrice = co.ticketType.Price
+	}
+	co.quote.Subtotal = co.quote.UnitPrice.Mul(co.quote.Quantity)
+	co.quote.Discount = co.quote.Subtotal.Mul(0)
+	co.quote.Total = co.quote.Subtotal
+
+	if req.PromoCode != "" {
+		if err := co.applyPromo(req.PromoCode, owner, now); err != nil {
+			return nil, err
+		}
+	}
+	return &co, nil
+}
+
+// applyPromo checks a promo code against the booking and takes its discount
+// off the total. Limits are checked again when the code is redeemed.
+func (co *checkout) applyPromo(code string, owner bookingOwner, now time.Time) *checkoutError {
+	var promo models.PromoCode
+	if err := database.GetDB().First(&promo, "code = ?", strings.ToUpper(strings.TrimSpace(code))).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return &checkoutError{http.StatusBadRequest, models.CodedErrorResponse{Error: "Promo code not found", Code: ErrCodePromoInvalid}}
+		}
+		return &checkoutError{http.StatusInternalServerError, gin.H{"error": "Failed to fetch promo code"}}
+	}
+
+	if !promo.ValidAt(now) {
+		return &checkoutError{http.StatusBadRequest, models.CodedErrorResponse{Error: "Promo code is not valid at this time", Code: ErrCodePromoInvalid}}
+	}
+	if !promo.AppliesTo(&co.event) {
+		return &checkoutError{http.StatusBadRequest, models.CodedErrorResponse{Error: "Promo code does not apply to this event", Code: ErrCodePromoNotApplicable}}
+	}
+	discount, err := promo.Discount(co.quote.Subtotal)
+	if err != nil {
+		return &checkoutError{http.StatusBadRequest, models.CodedErrorResponse{Error: "Promo code does not apply to prices in " + co.quote.Subtotal.Currency, Code: ErrCodePromoNotApplicable}}
+	}
+
+	exhausted, err := promoExhausted(database.GetDB(), &promo, owner)
+	if err != nil {
+		return &checkoutError{http.StatusInternalServerError, gin.H{"error": "Failed to check promo code usage"}}
+	}
+	if exhausted {
+		return &checkoutError{http.StatusConflict, models.CodedErrorResponse{Error: "Promo code has been used up", Code: ErrCodePromoExhausted}}
+	}
+
+	co.promo = &promo
+	co.quote.PromoCode = promo.Code
+	co.quote.Discount = discount
+	co.quote.Total, _ = co.quote.Subtotal.Sub(discount)
+	return nil
+}
+
+// promoExhausted reports whether the code has reached its overall limit or
+// the owner's limit, counting the owner's active bookings with the code
+func promoExhausted(db *gorm.DB, promo *models.PromoCode, owner bookingOwner) (bool, error) {
+	if promo.MaxUses > 0 && promo.Uses >= promo.MaxUses {
+		return true, nil
+	}
+	if promo.MaxUsesPerUser == 0 {
+		return false, nil
+	}
+
+	var used int64
+	if err := db.Model(&models.Booking{}).Scopes(owner.scope).
+		Where("promo_code_id = ? AND status <> ?", promo.ID, models.BookingStatusCancelled).
+		Count(&used).Error; err != nil {
+		return false, err
+	}
+	return used >= int64(promo.MaxUsesPerUser), nil
+}
+
+// redeemPromo counts a use of the code inside the booking transaction,
+// failing with errPromoExhausted once a limit is reached
+func redeemPromo(tx *gorm.DB, promo *models.PromoCode, owner bookingOwner) error {
+	// The limit check and increment happen in one statement, which also locks
+	// the code so the per-user count below cannot race another booking
+	result := tx.Model(&models.PromoCode{}).
+		Where("id = ? AND (max_uses = 0 OR uses < max_uses)", promo.ID).
+		Update("uses", gorm.Expr("uses + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errPromoExhausted
+	}
+
+	exhausted, err := promoExhausted(tx, &models.PromoCode{ID: promo.ID, MaxUsesPerUser: promo.MaxUsesPerUser}, owner)
+	if err != nil {
+		return err
+	}
+	if exhausted {
+		return errPromoExhausted
+	}
+	return nil
+}
+
+// @Summary Quote a booking
+// @Description Preview the price of a booking, with the discount of an optional promo code, without booking. Promo codes that are unknown or outside their validity window are rejected with code promo_invalid, codes for other events or currencies with promo_not_applicable and used-up codes with promo_exhausted.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param request body models.CreateBookingRequest true "Booking details"
+// @Security Bearer
+// @Success 200 {object} models.BookingQuote
+// @Failure 400 {object} models.CodedErrorResponse
+// @Failure 409 {object} models.CodedErrorResponse
+// @Router /bookings/quote [post]
+func QuoteBookingHandler(c *gin.Context) {
+	var req models.CreateBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	co, checkoutErr := prepareCheckout(&req, bookingOwner{userID: userID.(string)}, time.Now())
+	if checkoutErr != nil {
+		checkoutErr.respond(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, co.quote)
+}
@@ -0,0 +1,139 @@
+package booking
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+	"online-task/pkg/money"
+)
+
+func createPromo(t *testing.T, promo models.PromoCode) {
+	t.Helper()
+	if promo.ID == "" {
+		promo.ID = promo.Code
+	}
+	promo.Active = true
+	if err := database.GetDB().Create(&promo).Error; err != nil {
+		t.Fatalf("failed to create promo code: %v", err)
+	}
+}
+
+func TestQuoteBooking(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name       string
+		promo      *models.PromoCode
+		usedBefore bool
+		code       string
+		wantStatus int
+		wantCode   string
+		wantTotal  int64
+	}{
+		{name: "no code", wantStatus: http.StatusOK, wantTotal: 4000},
+		{name: "percent", promo: &models.PromoCode{Code: "SPRING25", Type: models.PromoTypePercent, PercentOff: 25}, code: "spring25", wantStatus: http.StatusOK, wantTotal: 3000},
+		{name: "fixed", promo: &models.PromoCode{Code: "TENOFF", Type: models.PromoTypeFixed, AmountOff: money.New(1000, "USD")}, code: "TENOFF", wantStatus: http.StatusOK, wantTotal: 3000},
+		{name: "fixed above the total", promo: &models.PromoCode{Code: "FREE", Type: models.PromoTypeFixed, AmountOff: money.New(9000, "USD")}, code: "FREE", wantStatus: http.StatusOK, wantTotal: 0},
+		{name: "restricted to the event", promo: &models.PromoCode{Code: "FANS", Type: models.PromoTypePercent, PercentOff: 50, EventIDs: []string{"concert"}}, code: "FANS", wantStatus: http.StatusOK, wantTotal: 2000},
+		{name: "unknown", code: "NOPE", wantStatus: http.StatusBadRequest, wantCode: ErrCodePromoInvalid},
+		{name: "expired", promo: &models.PromoCode{Code: "OLD", Type: models.PromoTypePercent, PercentOff: 10, ValidUntil: &past}, code: "OLD", wantStatus: http.StatusBadRequest, wantCode: ErrCodePromoInvalid},
+		{name: "other event", promo: &models.PromoCode{Code: "OTHER", Type: models.PromoTypePercent, PercentOff: 10, EventIDs: []string{"theatre"}}, code: "OTHER", wantStatus: http.StatusBadRequest, wantCode: ErrCodePromoNotApplicable},
+		{name: "other tag", promo: &models.PromoCode{Code: "JAZZ", Type: models.PromoTypePercent, PercentOff: 10, TagIDs: []string{"jazz"}}, code: "JAZZ", wantStatus: http.StatusBadRequest, wantCode: ErrCodePromoNotApplicable},
+		{name: "other currency", promo: &models.PromoCode{Code: "EURO", Type: models.PromoTypeFixed, AmountOff: money.New(500, "EUR")}, code: "EURO", wantStatus: http.StatusBadRequest, wantCode: ErrCodePromoNotApplicable},
+		{name: "used up", promo: &models.PromoCode{Code: "GONE", Type: models.PromoTypePercent, PercentOff: 10, MaxUses: 5, Uses: 5}, code: "GONE", wantStatus: http.StatusConflict, wantCode: ErrCodePromoExhausted},
+		{name: "used up by the user", promo: &models.PromoCode{Code: "ONCE", Type: models.PromoTypePercent, PercentOff: 10, MaxUsesPerUser: 1}, usedBefore: true, code: "ONCE", wantStatus: http.StatusConflict, wantCode: ErrCodePromoExhausted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := setupPaymentTest(t)
+			if tt.promo != nil {
+				createPromo(t, *tt.promo)
+			}
+			if tt.usedBefore {
+				promoID := tt.promo.Code
+				database.GetDB().Create(&models.Booking{ID: "earlier", UserID: "user-1", EventID: "theatre", PromoCodeID: &promoID, Status: models.BookingStatusConfirmed})
+			}
+
+			body, _ := json.Marshal(models.CreateBookingRequest{EventID: "concert", TicketTypeID: "standard", Quantity: 2, PromoCode: tt.code})
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/bookings/quote", bytes.NewReader(body)))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+
+			if tt.wantCode != "" {
+				var resp models.CodedErrorResponse
+				json.Unmarshal(w.Body.Bytes(), &resp)
+				if resp.Code != tt.wantCode {
+					t.Errorf("code = %q, want %q", resp.Code, tt.wantCode)
+				}
+				return
+			}
+			var quote models.BookingQuote
+			json.Unmarshal(w.Body.Bytes(), &quote)
+			if quote.Subtotal.Amount != 4000 || quote.Total != money.New(tt.wantTotal, "USD") {
+				t.Errorf("quote = %+v, want a total of %d", quote, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestPromoRedemptionLimits(t *testing.T) {
+	setupPaymentTest(t)
+	database.GetDB().Model(&models.TicketType{}).Where("id = ?", "standard").Update("quota", 0)
+	createPromo(t, models.PromoCode{Code: "FIRST3", Type: models.PromoTypePercent, PercentOff: 100, MaxUses: 3})
+
+	// Users book concurrently; only three may redeem the code
+	users := []string{"user-1", "user-2", "user-3", "user-4", "user-5", "user-6"}
+	statuses := make([]int, len(users))
+	var wg sync.WaitGroup
+	for i, user := range users {
+		wg.Add(1)
+		go func(i int, user string) {
+			defer wg.Done()
+			c := gin.New()
+			c.POST("/api/bookings", func(ctx *gin.Context) { ctx.Set("userID", user) }, CreateBookingHandler)
+			body, _ := json.Marshal(models.CreateBookingRequest{EventID: "concert", TicketTypeID: "standard", PromoCode: "FIRST3"})
+			w := httptest.NewRecorder()
+			c.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/bookings", bytes.NewReader(body)))
+			statuses[i] = w.Code
+		}(i, user)
+	}
+	wg.Wait()
+
+	created := 0
+	for _, status := range statuses {
+		if status == http.StatusCreated {
+			created++
+		} else if status != http.StatusConflict {
+			t.Errorf("status = %d, want 201 or 409", status)
+		}
+	}
+	var promo models.PromoCode
+	database.GetDB().First(&promo, "code = ?", "FIRST3")
+	if created != 3 || promo.Uses != 3 {
+		t.Errorf("created %d bookings with %d uses, want 3", created, promo.Uses)
+	}
+
+	// Cancelling a booking gives its use back
+	var booking models.Booking
+	database.GetDB().First(&booking, "promo_code_id = ?", promo.ID)
+	w := httptest.NewRecorder()
+	c := gin.New()
+	c.DELETE("/api/bookings/:id", func(ctx *gin.Context) { ctx.Set("userID", booking.UserID) }, CancelBookingHandler)
+	c.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/bookings/"+booking.ID, nil))
+	database.GetDB().First(&promo, "code = ?", "FIRST3")
+	if w.Code != http.StatusOK || promo.Uses != 2 {
+		t.Errorf("cancel status = %d with %d uses, want 200 and 2", w.Code, promo.Uses)
+	}
+}
@@ -0,0 +1,190 @@
+package booking
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+)
+
+// maxRefundAttempts bounds how often a failed refund is retried before it is
+// left for staff to resolve
+const maxRefundAttempts = 5
+
+// refundRetryDelay keeps the reaper away from refunds that are being issued
+const refundRetryDelay = time.Minute
+
+var errAlreadyCancelled = errors.New("booking already cancelled")
+
+// RecordRefund records a pending refund of percent of a paid booking's total.
+// It returns nil if there is nothing to refund. The refund is issued by
+// issueRefund, or in the background by the payment reaper.
+func RecordRefund(tx *gorm.DB, booking *models.Booking, percent int, reason string) (*models.Refund, error) {
+	amount := booking.Total.Percent(percent)
+	if amount.IsZero() || booking.PaymentIntentID == nil {
+		return nil, nil
+	}
+
+	refund := models.Refund{
+		ID:        uuid.New().String(),
+		BookingID: booking.ID,
+		UserID:    booking.UserID,
+		Amount:    amount,
+		Percent:   percent,
+		Reason:    reason,
+		Status:    models.RefundStatusPending,
+	}
+	if err := tx.Create(&refund).Error; err != nil {
+		return nil, err
+	}
+	return &refund, nil
+}
+
+// issueRefund asks the payment provider to pay out a recorded refund and
+// stores the outcome. The refund ID makes retries idempotent.
+func issueRefund(ctx context.Context, refund *models.Refund, intentID string) {
+	updates := map[string]interface{}{"attempts": gorm.Expr("attempts + 1")}
+	result, err := paymentProvider.Refund(ctx, intentID, refund.Amount, refund.ID)
+	if err != nil {
+		log.Printf("Failed to refund %s for booking %s: %v", refund.ID, refund.BookingID, err)
+		refund.Status = models.RefundStatusFailed
+		updates["last_error"] = err.Error()
+	} else {
+		refund.Status = models.RefundStatusSucceeded
+		refund.ProviderRefundID = result.ID
+		updates["provider_refund_id"] = result.ID
+	}
+	updates["status"] = refund.Status
+
+	if err := database.GetDB().Model(&models.Refund{}).Where("id = ?", refund.ID).Updates(updates).Error; err != nil {
+		log.Printf("Failed to save refund %s: %v", refund.ID, err)
+	}
+}
+
+// retryRefunds issues refunds that are still pending or have failed fewer
+// than maxRefundAttempts times
+func retryRefunds(ctx context.Context) error {
+	var refunds []models.Refund
+	if err := database.GetDB().
+		Where("status IN ? AND attempts < ? AND updated_at <= ?",
+			[]string{models.RefundStatusPending, models.RefundStatusFailed}, maxRefundAttempts, time.Now().Add(-refundRetryDelay)).
+		Find(&refunds).Error; err != nil {
+		return err
+	}
+
+	for i := range refunds {
+		var booking models.Booking
+		if err := database.GetDB().First(&booking, "id = ?", refunds[i].BookingID).Error; err != nil {
+			return err
+		}
+		if booking.PaymentIntentID != nil {
+			issueRefund(ctx, &refunds[i], *booking.PaymentIntentID)
+		}
+	}
+	return nil
+}
+
+// cancelBooking cancels a pending or confirmed booking and releases its
+// tickets, failing with errAlreadyCancelled if it was no longer active. Paid
+// bookings get a refund of percent of their total, returned for issueRefund.
+func cancelBooking(tx *gorm.DB, booking *models.Booking, now time.Time, percent int, reason string) (*models.Refund, error) {
+	if booking.Status == models.BookingStatusPendingPayment {
+		cancelled, err := cancelPendingBooking(tx, booking, now)
+		if err == nil && !cancelled {
+			return nil, errAlreadyCancelled
+		}
+		return nil, err
+	}
+
+	result := tx.Model(&models.Booking{}).
+		Where("id = ? AND status = ?", booking.ID, models.BookingStatusConfirmed).
+		Updates(map[string]interface{}{"status": models.BookingStatusCancelled, "cancelled_at": now})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errAlreadyCancelled
+	}
+	if err := releaseTickets(tx, booking); err != nil {
+		return nil, err
+	}
+
+	if booking.PaidAt == nil {
+		return nil, nil
+	}
+	return RecordRefund(tx, booking, percent, reason)
+}
+
+// @Summary Cancel a booking
+// @Description Cancel a booking of the authenticated user, or a guest booking with the token from its magic link, and release its tickets. Paid bookings are refunded according to the event's cancellation policy, by default in full up to 7 days before the event, half up to 48 hours before and nothing after. The refund is listed on the booking. Bookings cannot be cancelled once the event has started.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Param token query string false "Guest booking token, also accepted in the X-Booking-Token header"
+// @Param Idempotency-Key header string false "Unique key that makes a retry of the request return its first response"
+// @Security Bearer
+// @Success 200 {object} models.BookingResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.CodedErrorResponse
+// @Router /bookings/{id} [delete]
+// @Router /guest/bookings/{id} [delete]
+func CancelBookingHandler(c *gin.Context) {
+	var booking models.Booking
+	if err := database.GetDB().Preload("Event").Scopes(ownBooking(c)).First(&booking).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch booking"})
+		return
+	}
+
+	now := time.Now()
+	if booking.Status == models.BookingStatusCancelled {
+		c.JSON(http.StatusConflict, gin.H{"error": "Booking is already cancelled"})
+		return
+	}
+	if booking.Status == models.BookingStatusAttended {
+		c.JSON(http.StatusConflict, gin.H{"error": "Booking has already been used"})
+		return
+	}
+	if !booking.Event.Date.After(now) {
+		c.JSON(http.StatusConflict, models.CodedErrorResponse{Error: "Event has already taken place", Code: ErrCodeEventPassed})
+		return
+	}
+
+	var refund *models.Refund
+	err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		var err error
+		refund, err = cancelBooking(tx, &booking, now, booking.Event.RefundPercent(now), models.RefundReasonCustomerCancelled)
+		return err
+	})
+	if err == errAlreadyCancelled {
+		c.JSON(http.StatusConflict, gin.H{"error": "Booking is already cancelled"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel booking"})
+		return
+	}
+
+	if refund != nil {
+		issueRefund(c.Request.Context(), refund, *booking.PaymentIntentID)
+	}
+
+	if err := database.GetDB().Preload("Event").Preload("TicketType").Preload("Refunds").Preload("Attendees").First(&booking, "id = ?", booking.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch booking details"})
+		return
+	}
+
+	c.JSON(http.StatusOK, toResponse(booking))
+}
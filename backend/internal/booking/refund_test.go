@@ -0,0 +1,103 @@
+package booking
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+	"online-task/pkg/money"
+)
+
+func TestRefundPercent(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	custom := []models.CancellationRule{{HoursBefore: 24, RefundPercent: 80}}
+
+	tests := []struct {
+		name   string
+		notice time.Duration
+		policy []models.CancellationRule
+		want   int
+	}{
+		{name: "default, a week ahead", notice: 8 * 24 * time.Hour, want: 100},
+		{name: "default, three days ahead", notice: 72 * time.Hour, want: 50},
+		{name: "default, exactly 48 hours ahead", notice: 48 * time.Hour, want: 50},
+		{name: "default, a day ahead", notice: 24 * time.Hour, want: 0},
+		{name: "custom, two days ahead", notice: 48 * time.Hour, policy: custom, want: 80},
+		{name: "custom, an hour ahead", notice: time.Hour, policy: custom, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := models.Event{Date: now.Add(tt.notice), CancellationPolicy: tt.policy}
+			if got := event.RefundPercent(now); got != tt.want {
+				t.Errorf("RefundPercent() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCancelBooking(t *testing.T) {
+	tests := []struct {
+		name       string
+		eventIn    time.Duration
+		pay        bool
+		wantStatus int
+		wantRefund int64
+	}{
+		{name: "unpaid booking", eventIn: 10 * 24 * time.Hour, wantStatus: http.StatusOK},
+		{name: "paid, a week ahead", eventIn: 10 * 24 * time.Hour, pay: true, wantStatus: http.StatusOK, wantRefund: 4000},
+		{name: "paid, three days ahead", eventIn: 72 * time.Hour, pay: true, wantStatus: http.StatusOK, wantRefund: 2000},
+		{name: "paid, a day ahead", eventIn: 24 * time.Hour, pay: true, wantStatus: http.StatusOK},
+		{name: "event started", eventIn: -time.Hour, pay: true, wantStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := setupPaymentTest(t)
+			booking := createPaidBooking(t, r)
+			if tt.pay {
+				body, _ := json.Marshal(models.PayBookingRequest{PaymentMethod: "pm_card_visa"})
+				r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/bookings/"+booking.ID+"/pay", bytes.NewReader(body)))
+			}
+			database.GetDB().Model(&models.Event{}).Where("id = ?", "concert").Update("date", time.Now().Add(tt.eventIn))
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/bookings/"+booking.ID, nil))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var response models.BookingResponse
+			json.Unmarshal(w.Body.Bytes(), &response)
+			if response.Status != models.BookingStatusCancelled {
+				t.Errorf("status = %s, want cancelled", response.Status)
+			}
+			if sold := soldTickets(t); sold != 0 {
+				t.Errorf("sold = %d, want the tickets released", sold)
+			}
+
+			if tt.wantRefund == 0 {
+				if len(response.Refunds) != 0 {
+					t.Errorf("refunds = %+v, want none", response.Refunds)
+				}
+			} else if len(response.Refunds) != 1 || response.Refunds[0].Amount != money.New(tt.wantRefund, "USD") ||
+				response.Refunds[0].Status != models.RefundStatusSucceeded {
+				t.Errorf("refunds = %+v, want one succeeded refund of %d", response.Refunds, tt.wantRefund)
+			}
+
+			w = httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/bookings/"+booking.ID, nil))
+			if w.Code != http.StatusConflict {
+				t.Errorf("second cancel status = %d, want 409", w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package booking
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"online-task/internal/models"
+	"online-task/internal/seating"
+	"online-task/pkg/database"
+)
+
+// ErrCodeSeatTaken is returned when a chosen seat is already held or booked
+const ErrCodeSeatTaken = "seat_taken"
+
+var errSeatTaken = errors.New("seat taken")
+
+var seatTakenError = models.CodedErrorResponse{Error: "A chosen seat has just been taken", Code: ErrCodeSeatTaken}
+
+// chooseSeats checks the seats asked for against the event's seat map. A
+// held checkout gets the hold's seats. Events without a seat map take no
+// seats.
+func (co *checkout) chooseSeats(seatIDs []string) *checkoutError {
+	seatMap, err := seating.ForEvent(database.GetDB(), &co.event)
+	if err != nil {
+		return &checkoutError{http.StatusInternalServerError, gin.H{"error": "Failed to fetch seat map"}}
+	}
+	if seatMap == nil {
+		if len(seatIDs) > 0 {
+			return &checkoutError{http.StatusBadRequest, gin.H{"error": "Event does not have reserved seating"}}
+		}
+		return nil
+	}
+
+	if co.hold != nil {
+		var held []models.SeatReservation
+		if err := database.GetDB().Preload("Seat").Find(&held, "hold_id = ?", co.hold.ID).Error; err != nil {
+			return &checkoutError{http.StatusInternalServerError, gin.H{"error": "Failed to fetch held seats"}}
+		}
+		heldIDs := map[string]bool{}
+		for _, r := range held {
+			heldIDs[r.SeatID] = true
+			co.seats = append(co.seats, *r.Seat)
+		}
+		if len(seatIDs) > 0 && (len(seatIDs) != len(held) || !containsAll(heldIDs, seatIDs)) {
+			return &checkoutError{http.StatusBadRequest, gin.H{"error": "Seats must match the seat hold"}}
+		}
+		return nil
+	}
+
+	if len(seatIDs) == 0 {
+		return &checkoutError{http.StatusBadRequest, gin.H{"error": "Choose a seat for every ticket"}}
+	}
+	unique := map[string]bool{}
+	for _, id := range seatIDs {
+		unique[id] = true
+	}
+	if len(unique) != len(seatIDs) {
+		return &checkoutError{http.StatusBadRequest, gin.H{"error": "A seat is chosen more than once"}}
+	}
+
+	if err := database.GetDB().Where("id IN ? AND seat_map_id = ?", seatIDs, seatMap.ID).Order("position").Find(&co.seats).Error; err != nil {
+		return &checkoutError{http.StatusInternalServerError, gin.H{"error": "Failed to fetch seats"}}
+	}
+	if len(co.seats) != len(seatIDs) {
+		return &checkoutError{http.StatusBadRequest, gin.H{"error": "Seat not found on the event's seat map"}}
+	}
+	if co.ticketType != nil && co.ticketType.Zone != "" {
+		for _, seat := range co.seats {
+			if seat.Zone != co.ticketType.Zone {
+				return &checkoutError{http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Seat %s%s of %s is not sold with this ticket type", seat.Row, seat.Number, seat.Section)}}
+			}
+		}
+	}
+
+	var taken int64
+	if err := database.GetDB().Model(&models.SeatReservation{}).
+		Where("event_id = ? AND seat_id IN ?", co.event.ID, seatIDs).
+		Count(&taken).Error; err != nil {
+		return &checkoutError{http.StatusInternalServerError, gin.H{"error": "Failed to check seats"}}
+	}
+	if taken > 0 {
+		return &checkoutError{http.StatusConflict, models.CodedErrorResponse{Error: "A chosen seat is already taken", Code: ErrCodeSeatTaken}}
+	}
+	return nil
+}
+
+func containsAll(set map[string]bool, ids []string) bool {
+	for _, id := range ids {
+		if !set[id] {
+			return false
+		}
+	}
+	return true
+}
+
+// reserveSeats gives the seats at the event to a booking or a hold, failing
+// with errSeatTaken if any was taken since the checkout was checked. The
+// unique index on the event and seat settles concurrent reservations.
+func reserveSeats(tx *gorm.DB, eventID string, seats []models.Seat, bookingID, holdID *string) error {
+	if len(seats) == 0 {
+		return nil
+	}
+
+	reservations := make([]models.SeatReservation, 0, len(seats))
+	for _, seat := range seats {
+		reservations = append(reservations, models.SeatReservation{
+			ID:        uuid.New().String(),
+			EventID:   eventID,
+			SeatID:    seat.ID,
+			BookingID: bookingID,
+			HoldID:    holdID,
+		})
+	}
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&reservations)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected != int64(len(reservations)) {
+		return errSeatTaken
+	}
+	return nil
+}
+
+// bookedSeats lists the seats of a booking with its reservations loaded
+func bookedSeats(booking *models.Booking) []models.Seat {
+	var seats []models.Seat
+	for _, r := range booking.Seats {
+		if r.Seat != nil {
+			seats = append(seats, *r.Seat)
+		}
+	}
+	return seats
+}
@@ -0,0 +1,122 @@
+package booking
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+)
+
+// setupSeats gives the test event a row of four standard seats and a row of
+// two premium seats, and returns their IDs
+func setupSeats(t *testing.T) (standard, premium []string) {
+	t.Helper()
+	eventID := "concert"
+	seatMap := models.SeatMap{ID: "map", EventID: &eventID, Name: "Main"}
+	if err := database.GetDB().Create(&seatMap).Error; err != nil {
+		t.Fatalf("failed to create seat map: %v", err)
+	}
+	for i, label := range []string{"1", "2", "3", "4", "5", "6"} {
+		seat := models.Seat{ID: "A" + label, SeatMapID: seatMap.ID, Section: "Stalls", Row: "A", Number: label, Zone: "standard", Position: i}
+		if i >= 4 {
+			seat.Zone = "premium"
+		}
+		if err := database.GetDB().Create(&seat).Error; err != nil {
+			t.Fatalf("failed to create seat: %v", err)
+		}
+		if seat.Zone == "standard" {
+			standard = append(standard, seat.ID)
+		} else {
+			premium = append(premium, seat.ID)
+		}
+	}
+	database.GetDB().Model(&models.TicketType{}).Where("id = ?", "standard").Update("zone", "standard")
+	return standard, premium
+}
+
+func reservedSeats(t *testing.T) int64 {
+	t.Helper()
+	var n int64
+	database.GetDB().Model(&models.SeatReservation{}).Where("event_id = ?", "concert").Count(&n)
+	return n
+}
+
+func TestReservedSeating(t *testing.T) {
+	r, _ := setupPaymentTest(t)
+	standard, premium := setupSeats(t)
+
+	tests := []struct {
+		name       string
+		req        models.CreateBookingRequest
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "no seats", req: models.CreateBookingRequest{Quantity: 1}, wantStatus: http.StatusBadRequest},
+		{name: "seat of another zone", req: models.CreateBookingRequest{Seats: []string{premium[0]}}, wantStatus: http.StatusBadRequest},
+		{name: "unknown seat", req: models.CreateBookingRequest{Seats: []string{"Z9"}}, wantStatus: http.StatusBadRequest},
+		{name: "seat chosen twice", req: models.CreateBookingRequest{Seats: []string{standard[0], standard[0]}}, wantStatus: http.StatusBadRequest},
+		{name: "fewer seats than tickets", req: models.CreateBookingRequest{Quantity: 3, Seats: standard[:2]}, wantStatus: http.StatusBadRequest},
+		{name: "seats", req: models.CreateBookingRequest{Seats: standard[:2]}, wantStatus: http.StatusCreated},
+		{name: "seat already booked", req: models.CreateBookingRequest{Seats: standard[1:3]}, wantStatus: http.StatusConflict, wantCode: ErrCodeSeatTaken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.EventID, tt.req.TicketTypeID = "concert", "standard"
+			w, _ := book(r, tt.req)
+			var refused models.CodedErrorResponse
+			json.Unmarshal(w.Body.Bytes(), &refused)
+			if w.Code != tt.wantStatus || refused.Code != tt.wantCode {
+				t.Errorf("got %d %q, want %d %q, body %s", w.Code, refused.Code, tt.wantStatus, tt.wantCode, w.Body.String())
+			}
+		})
+	}
+	if n := reservedSeats(t); n != 2 {
+		t.Errorf("reserved seats = %d, want 2", n)
+	}
+
+	// A hold keeps its seats until they are booked with it
+	w, held := hold(r, models.CreateHoldRequest{EventID: "concert", TicketTypeID: "standard", Seats: standard[2:4]})
+	if w.Code != http.StatusCreated || held.Quantity != 2 || len(held.Seats) != 2 {
+		t.Fatalf("hold: got %d %+v, want two held seats", w.Code, held)
+	}
+	if w, _ := hold(r, models.CreateHoldRequest{EventID: "concert", TicketTypeID: "standard", Seats: standard[3:]}); w.Code != http.StatusConflict {
+		t.Errorf("holding a held seat: status = %d, want 409", w.Code)
+	}
+	w, booking := book(r, models.CreateBookingRequest{EventID: "concert", TicketTypeID: "standard", HoldID: held.ID})
+	if w.Code != http.StatusCreated || len(booking.Seats) != 2 || booking.Seats[0].ID != standard[2] {
+		t.Fatalf("booking the hold: got %d with seats %+v, want its seats", w.Code, booking.Seats)
+	}
+
+	// Cancelling gives the seats back
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/bookings/"+booking.ID, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("cancel status = %d, body %s", w.Code, w.Body.String())
+	}
+	if n := reservedSeats(t); n != 2 {
+		t.Errorf("reserved seats = %d, want the cancelled booking's seats freed", n)
+	}
+	if w, _ := book(r, models.CreateBookingRequest{EventID: "concert", TicketTypeID: "standard", Seats: standard[2:4]}); w.Code != http.StatusCreated {
+		t.Errorf("rebooking freed seats: status = %d, want 201", w.Code)
+	}
+}
+
+func TestReserveSeatsOnce(t *testing.T) {
+	setupPaymentTest(t)
+	standard, _ := setupSeats(t)
+	seats := []models.Seat{{ID: standard[0]}, {ID: standard[1]}}
+
+	first, second := "first", "second"
+	if err := reserveSeats(database.GetDB(), "concert", seats, &first, nil); err != nil {
+		t.Fatalf("reserveSeats() error = %v", err)
+	}
+	if err := reserveSeats(database.GetDB(), "concert", seats[1:], &second, nil); err != errSeatTaken {
+		t.Errorf("reserving a taken seat: error = %v, want errSeatTaken", err)
+	}
+	if n := reservedSeats(t); n != 2 {
+		t.Errorf("reserved seats = %d, want 2", n)
+	}
+}
@@ -0,0 +1,284 @@
+package booking
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jung-kurt/gofpdf"
+	"github.com/skip2/go-qrcode"
+	"gorm.io/gorm"
+
+	"online-task/internal/models"
+	"online-task/internal/notification"
+	"online-task/pkg/database"
+	"online-task/pkg/ticket"
+)
+
+// ticketQRSize is the width and height of ticket QR images in pixels
+const ticketQRSize = 512
+
+// ticketSigner signs e-tickets and check-in manifests. It is set at startup.
+var ticketSigner *ticket.Signer
+
+// SetTicketSigner configures the key e-tickets are signed with. It must be
+// called before any ticket is issued or verified.
+func SetTicketSigner(s *ticket.Signer) {
+	ticketSigner = s
+}
+
+// ticketToken returns the signed e-ticket of a confirmed or attended
+// booking, or an empty string for bookings that do not admit anyone.
+// Group bookings, loaded with their attendees, have no ticket of their own.
+func ticketToken(booking *models.Booking) (string, error) {
+	if booking.Status != models.BookingStatusConfirmed && booking.Status != models.BookingStatusAttended {
+		return "", nil
+	}
+	if len(booking.Attendees) > 0 {
+		return "", nil
+	}
+	// Signing the booking's creation time keeps the token, and so the QR
+	// code, the same however often it is downloaded
+	return ticketSigner.Sign(ticket.Claims{
+		BookingID: booking.ID,
+		EventID:   booking.EventID,
+		Quantity:  booking.Quantity,
+		IssuedAt:  booking.CreatedAt.Unix(),
+		Version:   booking.TicketVersion,
+	})
+}
+
+// attendeeTicketToken returns the e-ticket of one attendee of a confirmed or
+// attended group booking
+func attendeeTicketToken(booking *models.Booking, attendee *models.Attendee) (string, error) {
+	if booking.Status != models.BookingStatusConfirmed && booking.Status != models.BookingStatusAttended {
+		return "", nil
+	}
+	return ticketSigner.Sign(ticket.Claims{
+		BookingID:  booking.ID,
+		AttendeeID: attendee.ID,
+		EventID:    booking.EventID,
+		Quantity:   1,
+		IssuedAt:   booking.CreatedAt.Unix(),
+		Version:    booking.TicketVersion,
+	})
+}
+
+// sendAttendeeTickets queues every attendee of a group booking their ticket
+// as part of the transaction that confirms the booking
+func sendAttendeeTickets(tx *gorm.DB, booking *models.Booking, event *models.Event) error {
+	notifications := make([]models.Notification, 0, len(booking.Attendees))
+	for i := range booking.Attendees {
+		attendee := &booking.Attendees[i]
+		token, err := attendeeTicketToken(booking, attendee)
+		if err != nil {
+			return err
+		}
+		notifications = append(notifications, models.Notification{
+			Type:      models.NotificationTicketIssued,
+			Email:     attendee.Email,
+			EventID:   booking.EventID,
+			BookingID: booking.ID,
+			Message:   fmt.Sprintf("%s, your ticket for %s: %s", attendee.Name, event.Name, token),
+		})
+	}
+	return notification.Emit(tx, notifications...)
+}
+
+// VerifyTicket checks the signature of a scanned ticket token and returns
+// its claims
+func VerifyTicket(token string) (ticket.Claims, error) {
+	return ticketSigner.Verify(token)
+}
+
+// SignManifest signs a check-in manifest with the e-ticket key, so scanners
+// can verify it with the same public key
+func SignManifest(data []byte) (payload, signature string) {
+	return ticketSigner.SignPayload(ticket.ContextManifest, data)
+}
+
+// loadTicket fetches a booking of the authenticated user, or the guest
+// booking opened with its link, with its token, responding with an error if
+// it has no ticket
+func loadTicket(c *gin.Context) (*models.Booking, string, bool) {
+	var booking models.Booking
+	if err := database.GetDB().Preload("Event").Preload("TicketType").Preload("User").Preload("Attendees").Preload("Seats.Seat").
+		Scopes(ownBooking(c)).First(&booking).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
+			return nil, "", false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch booking"})
+		return nil, "", false
+	}
+
+	var token string
+	var err error
+	if attendeeID := c.Query("attendee"); attendeeID != "" {
+		attendee := findAttendee(&booking, attendeeID)
+		if attendee == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Attendee not found"})
+			return nil, "", false
+		}
+		token, err = attendeeTicketToken(&booking, attendee)
+		booking.Attendees = []models.Attendee{*attendee}
+	} else {
+		if len(booking.Attendees) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Group bookings have a ticket per attendee; pass attendee"})
+			return nil, "", false
+		}
+		token, err = ticketToken(&booking)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to sign ticket"})
+		return nil, "", false
+	}
+	if token == "" {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only confirmed bookings have a ticket"})
+		return nil, "", false
+	}
+	return &booking, token, true
+}
+
+func findAttendee(booking *models.Booking, id string) *models.Attendee {
+	for i := range booking.Attendees {
+		if booking.Attendees[i].ID == id {
+			return &booking.Attendees[i]
+		}
+	}
+	return nil
+}
+
+// @Summary Get a booking's QR code
+// @Description Get the e-ticket of a confirmed booking of the authenticated user as a QR code. The code holds the signed ticket token also returned as the booking's ticket field. Group bookings need the attendee whose ticket to get.
+// @Tags bookings
+// @Produce png
+// @Param id path string true "Booking ID"
+// @Param attendee query string false "Attendee ID, for group bookings"
+// @Param token query string false "Guest booking token, also accepted in the X-Booking-Token header"
+// @Security Bearer
+// @Success 200 {file} binary
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /bookings/{id}/ticket.png [get]
+// @Router /guest/bookings/{id}/ticket.png [get]
+func TicketQRHandler(c *gin.Context) {
+	_, token, ok := loadTicket(c)
+	if !ok {
+		return
+	}
+
+	png, err := qrcode.Encode(token, qrcode.Medium, ticketQRSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render QR code"})
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	c.Data(http.StatusOK, "image/png", png)
+}
+
+// @Summary Get a printable ticket
+// @Description Get the e-ticket of a confirmed booking of the authenticated user as a PDF with the event details and QR code. Group bookings need the attendee whose ticket to get.
+// @Tags bookings
+// @Produce application/pdf
+// @Param id path string true "Booking ID"
+// @Param attendee query string false "Attendee ID, for group bookings"
+// @Param token query string false "Guest booking token, also accepted in the X-Booking-Token header"
+// @Security Bearer
+// @Success 200 {file} binary
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /bookings/{id}/ticket.pdf [get]
+// @Router /guest/bookings/{id}/ticket.pdf [get]
+func TicketPDFHandler(c *gin.Context) {
+	booking, token, ok := loadTicket(c)
+	if !ok {
+		return
+	}
+
+	png, err := qrcode.Encode(token, qrcode.Medium, ticketQRSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render QR code"})
+		return
+	}
+	pdf, err := renderTicketPDF(booking, png)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render ticket"})
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="ticket-%s.pdf"`, booking.ID))
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+// renderTicketPDF lays out an A6 ticket with the event details and QR code
+func renderTicketPDF(booking *models.Booking, qr []byte) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A6", "")
+	pdf.SetMargins(10, 10, 10)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.AddPage()
+	// The core fonts are not Unicode; translate what cp1252 can show
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.MultiCell(0, 7, tr(booking.Event.Name), "", "L", false)
+	pdf.Ln(2)
+
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.MultiCell(0, 5, tr(booking.Event.LocalDate.Format("Monday, 2 January 2006, 15:04 MST")), "", "L", false)
+	pdf.MultiCell(0, 5, tr(booking.Event.Location), "", "L", false)
+	pdf.Ln(2)
+
+	// loadTicket leaves only the ticket's attendee on group bookings
+	holder := booking.HolderName()
+	admits := fmt.Sprintf("Admits %d", booking.Quantity)
+	if len(booking.Attendees) > 0 {
+		holder = booking.Attendees[0].Name
+		admits = "Admits 1"
+	}
+	if booking.TicketType != nil {
+		admits += " - " + booking.TicketType.Name
+	}
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.MultiCell(0, 5, tr(admits), "", "L", false)
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.MultiCell(0, 5, tr(holder), "", "L", false)
+	if len(booking.Attendees) == 0 {
+		for _, seat := range bookedSeats(booking) {
+			pdf.MultiCell(0, 5, tr(fmt.Sprintf("%s, row %s, seat %s", seat.Section, seat.Row, seat.Number)), "", "L", false)
+		}
+	}
+
+	pdf.RegisterImageOptionsReader("qr", gofpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(qr))
+	pageWidth, _ := pdf.GetPageSize()
+	size := 60.0
+	pdf.ImageOptions("qr", (pageWidth-size)/2, pdf.GetY()+4, size, size, false, gofpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+
+	pdf.SetY(pdf.GetY() + size + 6)
+	pdf.SetFont("Helvetica", "", 7)
+	pdf.CellFormat(0, 4, "Booking "+booking.ID, "", 1, "C", false, 0, "")
+	pdf.CellFormat(0, 4, "Show this code at the entrance", "", 1, "C", false, 0, "")
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// @Summary Get the ticket verification key
+// @Description Get the Ed25519 public key that verifies ticket tokens, so scanners can check tickets offline. A token is the base64url JSON claims and the base64url signature of those claims joined by a dot. Signatures cover a context prefix followed by the base64url payload: "ticket:" for ticket tokens and "manifest:" for check-in manifests, so one is never accepted as the other.
+// @Tags bookings
+// @Produce json
+// @Success 200 {object} models.TicketKeyResponse
+// @Router /tickets/public-key [get]
+func TicketPublicKeyHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, models.TicketKeyResponse{
+		Algorithm: ticket.Algorithm,
+		PublicKey: base64.StdEncoding.EncodeToString(ticketSigner.PublicKey()),
+	})
+}
@@ -0,0 +1,104 @@
+package booking
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"image/png"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+	"online-task/pkg/ticket"
+)
+
+// TestMain configures a ticket key, as the server does at startup
+func TestMain(m *testing.M) {
+	signer, err := ticket.GenerateSigner()
+	if err != nil {
+		log.Fatalf("failed to generate ticket key: %v", err)
+	}
+	SetTicketSigner(signer)
+	os.Exit(m.Run())
+}
+
+func TestTicketDownloads(t *testing.T) {
+	r, _ := setupPaymentTest(t)
+	r.GET("/api/bookings/:id/ticket.png", func(c *gin.Context) { c.Set("userID", "user-1") }, TicketQRHandler)
+	r.GET("/api/bookings/:id/ticket.pdf", func(c *gin.Context) { c.Set("userID", "user-1") }, TicketPDFHandler)
+	r.GET("/api/tickets/public-key", TicketPublicKeyHandler)
+	database.GetDB().Create(&models.User{ID: "user-1", Username: "Ada", Email: "ada@example.com", Password: "x"})
+
+	booking := createPaidBooking(t, r)
+	pending := httptest.NewRecorder()
+	r.ServeHTTP(pending, httptest.NewRequest(http.MethodGet, "/api/bookings/"+booking.ID+"/ticket.png", nil))
+	if pending.Code != http.StatusConflict || booking.Ticket != "" {
+		t.Errorf("pending booking got status %d and ticket %q, want 409 and none", pending.Code, booking.Ticket)
+	}
+
+	body, _ := json.Marshal(models.PayBookingRequest{PaymentMethod: "pm_card_visa"})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/bookings/"+booking.ID+"/pay", bytes.NewReader(body)))
+
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		check       func(t *testing.T, body []byte)
+	}{
+		{
+			name:        "qr code",
+			path:        "/api/bookings/" + booking.ID + "/ticket.png",
+			contentType: "image/png",
+			check: func(t *testing.T, body []byte) {
+				if _, err := png.Decode(bytes.NewReader(body)); err != nil {
+					t.Errorf("not a PNG: %v", err)
+				}
+			},
+		},
+		{
+			name:        "pdf",
+			path:        "/api/bookings/" + booking.ID + "/ticket.pdf",
+			contentType: "application/pdf",
+			check: func(t *testing.T, body []byte) {
+				if !bytes.HasPrefix(body, []byte("%PDF-")) {
+					t.Error("not a PDF")
+				}
+			},
+		},
+		{
+			name:        "public key verifies the ticket",
+			path:        "/api/tickets/public-key",
+			contentType: "application/json; charset=utf-8",
+			check: func(t *testing.T, body []byte) {
+				var key models.TicketKeyResponse
+				json.Unmarshal(body, &key)
+				raw, _ := base64.StdEncoding.DecodeString(key.PublicKey)
+
+				var confirmed models.Booking
+				database.GetDB().First(&confirmed, "id = ?", booking.ID)
+				token, _ := ticketToken(&confirmed)
+				claims, err := ticket.Verify(raw, token)
+				if err != nil || claims.BookingID != booking.ID || claims.Quantity != 2 {
+					t.Errorf("Verify() = %+v, %v", claims, err)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != http.StatusOK || w.Header().Get("Content-Type") != tt.contentType {
+				t.Fatalf("status = %d, content type %q, body %s", w.Code, w.Header().Get("Content-Type"), w.Body.String())
+			}
+			tt.check(t, w.Body.Bytes())
+		})
+	}
+}
@@ -0,0 +1,424 @@
+package booking
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"online-task/internal/auth"
+	"online-task/internal/models"
+	"online-task/internal/notification"
+	"online-task/pkg/database"
+)
+
+// Error codes returned when a ticket cannot be transferred
+const (
+	ErrCodeTransfersDisabled = "transfers_disabled"
+	ErrCodeNotTransferable   = "not_transferable"
+	ErrCodeTransferExpired   = "transfer_expired"
+	ErrCodeTransferClosed    = "transfer_closed"
+)
+
+var errTransferClosed = errors.New("transfer no longer pending")
+
+// transferTTL is how long a recipient has to accept a transfer, from
+// TRANSFER_TTL_HOURS (default 72). Transfers also expire when the event
+// starts.
+func transferTTL() time.Duration {
+	hours, err := strconv.Atoi(os.Getenv("TRANSFER_TTL_HOURS"))
+	if err != nil || hours <= 0 {
+		hours = 72
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// newToken returns a random token to hand out, for a transfer recipient or
+// a guest, and the hash stored to look it up
+func newToken() (token, hash string, err error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(raw)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// transferStatus reports a pending transfer past its expiry as expired
+func transferStatus(transfer *models.TicketTransfer, now time.Time) string {
+	if transfer.Status == models.TransferStatusPending && !transfer.ExpiresAt.After(now) {
+		return models.TransferStatusExpired
+	}
+	return transfer.Status
+}
+
+// CancelPendingTransfers withdraws the pending transfers of an event's
+// bookings, once the event stops allowing transfers
+func CancelPendingTransfers(tx *gorm.DB, eventID string, now time.Time) error {
+	return tx.Model(&models.TicketTransfer{}).
+		Where("event_id = ? AND status = ?", eventID, models.TransferStatusPending).
+		Updates(map[string]interface{}{"status": models.TransferStatusCancelled, "cancelled_at": now}).Error
+}
+
+// @Summary Transfer a ticket
+// @Description Offer a confirmed booking of the authenticated user to the holder of an email address. The recipient is sent a token to accept the transfer with, valid for 72 hours or until the event starts. Starting a new transfer cancels a pending one. Refused with transfers_disabled for events that do not allow transfers, not_transferable for bookings that are not confirmed or have named attendees, and event_passed once the event has started.
+// @Tags transfers
+// @Accept json
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Param request body models.CreateTransferRequest true "Recipient"
+// @Security Bearer
+// @Success 201 {object} models.TicketTransfer
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.CodedErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.CodedErrorResponse
+// @Router /bookings/{id}/transfer [post]
+func CreateTransferHandler(c *gin.Context) {
+	var req models.CreateTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	userID := c.GetString("userID")
+
+	var booking models.Booking
+	if err := database.GetDB().Preload("Event").Preload("User").Preload("Attendees").
+		First(&booking, "id = ? AND user_id = ?", c.Param("id"), userID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch booking"})
+		return
+	}
+
+	now := time.Now()
+	if booking.Event.TransfersDisabled {
+		c.JSON(http.StatusForbidden, models.CodedErrorResponse{Error: "Tickets for this event cannot be transferred", Code: ErrCodeTransfersDisabled})
+		return
+	}
+	if booking.Status != models.BookingStatusConfirmed {
+		c.JSON(http.StatusConflict, models.CodedErrorResponse{Error: "Only confirmed bookings can be transferred", Code: ErrCodeNotTransferable})
+		return
+	}
+	// A group booking's tickets are issued to its named attendees
+	if len(booking.Attendees) > 0 {
+		c.JSON(http.StatusConflict, models.CodedErrorResponse{Error: "Bookings with named attendees cannot be transferred", Code: ErrCodeNotTransferable})
+		return
+	}
+	if !booking.Event.Date.After(now) {
+		c.JSON(http.StatusConflict, models.CodedErrorResponse{Error: "Event has already started", Code: ErrCodeEventPassed})
+		return
+	}
+	if strings.EqualFold(booking.User.Email, email) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot transfer a ticket to yourself"})
+		return
+	}
+
+	token, hash, err := newToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create transfer token"})
+		return
+	}
+	expiresAt := now.Add(transferTTL())
+	if booking.Event.Date.Before(expiresAt) {
+		expiresAt = booking.Event.Date
+	}
+	transfer := models.TicketTransfer{
+		ID:         uuid.New().String(),
+		BookingID:  booking.ID,
+		EventID:    booking.EventID,
+		FromUserID: userID,
+		ToEmail:    email,
+		TokenHash:  hash,
+		Status:     models.TransferStatusPending,
+		ExpiresAt:  expiresAt,
+	}
+
+	var recipient models.User
+	if err := database.GetDB().First(&recipient, "LOWER(email) = ?", email).Error; err == nil {
+		transfer.ToUserID = &recipient.ID
+	} else if err != gorm.ErrRecordNotFound {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch recipient"})
+		return
+	}
+
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.TicketTransfer{}).
+			Where("booking_id = ? AND status = ?", booking.ID, models.TransferStatusPending).
+			Updates(map[string]interface{}{"status": models.TransferStatusCancelled, "cancelled_at": now}).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&transfer).Error; err != nil {
+			return err
+		}
+		// The token only leaves the server in the message to the recipient
+		return notification.Emit(tx, models.Notification{
+			Type:      models.NotificationTicketOffered,
+			UserID:    recipient.ID,
+			Email:     email,
+			EventID:   booking.EventID,
+			BookingID: booking.ID,
+			Message: fmt.Sprintf("%s sent you a ticket for %s. Accept it by %s with the code %s",
+				booking.User.Username, booking.Event.Name, expiresAt.UTC().Format(time.RFC1123), token),
+		})
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create transfer"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, transfer)
+}
+
+// @Summary Accept a ticket transfer
+// @Description Take over the booking of a transfer sent to the authenticated user's email address. Ownership moves in one step and the booking gets a new ticket, so the previous owner's ticket no longer admits anyone. Refused with transfers_disabled if the event no longer allows transfers, transfer_expired after the deadline or once the event has started, and transfer_closed if the transfer was cancelled or already accepted, the event is no longer published or the booking can no longer be transferred.
+// @Tags transfers
+// @Accept json
+// @Produce json
+// @Param request body models.AcceptTransferRequest true "Transfer token"
+// @Security Bearer
+// @Success 200 {object} models.BookingResponse
+// @Failure 403 {object} models.CodedErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.CodedErrorResponse
+// @Router /transfers/accept [post]
+func AcceptTransferHandler(c *gin.Context) {
+	var req models.AcceptTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID := c.GetString("userID")
+
+	var transfer models.TicketTransfer
+	if err := database.GetDB().First(&transfer, "token_hash = ?", hashToken(strings.TrimSpace(req.Token))).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Transfer not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch transfer"})
+		return
+	}
+
+	var user models.User
+	if err := database.GetDB().First(&user, "id = ?", userID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+		return
+	}
+	if !strings.EqualFold(user.Email, transfer.ToEmail) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "This transfer was sent to another email address"})
+		return
+	}
+
+	now := time.Now()
+	switch transferStatus(&transfer, now) {
+	case models.TransferStatusPending:
+	case models.TransferStatusExpired:
+		database.GetDB().Model(&models.TicketTransfer{}).
+			Where("id = ? AND status = ?", transfer.ID, models.TransferStatusPending).
+			Update("status", models.TransferStatusExpired)
+		c.JSON(http.StatusConflict, models.CodedErrorResponse{Error: "Transfer has expired", Code: ErrCodeTransferExpired})
+		return
+	default:
+		c.JSON(http.StatusConflict, models.CodedErrorResponse{Error: "Transfer is no longer pending", Code: ErrCodeTransferClosed})
+		return
+	}
+
+	// The event may have changed since the transfer was offered
+	var event models.Event
+	if err := database.GetDB().First(&event, "id = ?", transfer.EventID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch event"})
+		return
+	}
+	if event.TransfersDisabled {
+		c.JSON(http.StatusForbidden, models.CodedErrorResponse{Error: "Tickets for this event cannot be transferred", Code: ErrCodeTransfersDisabled})
+		return
+	}
+	if !event.Date.After(now) {
+		c.JSON(http.StatusConflict, models.CodedErrorResponse{Error: "Transfer has expired", Code: ErrCodeTransferExpired})
+		return
+	}
+	if event.Status != models.EventStatusPublished {
+		c.JSON(http.StatusConflict, models.CodedErrorResponse{Error: "Event is not open for transfers", Code: ErrCodeTransferClosed})
+		return
+	}
+
+	err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		// Both updates are conditional, so a concurrent accept, cancellation,
+		// check-in or change to the event makes the transfer fail rather than
+		// half apply
+		result := tx.Model(&models.TicketTransfer{}).
+			Where("id = ? AND status = ? AND expires_at > ?", transfer.ID, models.TransferStatusPending, now).
+			Updates(map[string]interface{}{"status": models.TransferStatusAccepted, "accepted_at": now, "to_user_id": userID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errTransferClosed
+		}
+
+		openEvents := tx.Model(&models.Event{}).Select("id").
+			Where("transfers_disabled = ? AND status = ? AND date > ?", false, models.EventStatusPublished, now)
+		result = tx.Model(&models.Booking{}).
+			Where("id = ? AND user_id = ? AND status = ?", transfer.BookingID, transfer.FromUserID, models.BookingStatusConfirmed).
+			Where("event_id IN (?)", openEvents).
+			Where("id NOT IN (?)", tx.Model(&models.Attendee{}).Select("booking_id")).
+			Updates(map[string]interface{}{"user_id": userID, "ticket_version": gorm.Expr("ticket_version + 1")})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errTransferClosed
+		}
+
+		return notification.Emit(tx, models.Notification{
+			Type:      models.NotificationTicketAccepted,
+			UserID:    transfer.FromUserID,
+			EventID:   transfer.EventID,
+			BookingID: transfer.BookingID,
+			Message:   fmt.Sprintf("%s accepted your ticket; your copy of it is no longer valid", transfer.ToEmail),
+		})
+	})
+	if err == errTransferClosed {
+		c.JSON(http.StatusConflict, models.CodedErrorResponse{Error: "Transfer is no longer pending", Code: ErrCodeTransferClosed})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to accept transfer"})
+		return
+	}
+
+	var booking models.Booking
+	if err := database.GetDB().Preload("Event").Preload("TicketType").Preload("Attendees").First(&booking, "id = ?", transfer.BookingID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch booking details"})
+		return
+	}
+	c.JSON(http.StatusOK, toResponse(booking))
+}
+
+// @Summary Cancel a ticket transfer
+// @Description Withdraw a pending transfer started by the authenticated user
+// @Tags transfers
+// @Produce json
+// @Param id path string true "Transfer ID"
+// @Security Bearer
+// @Success 200 {object} models.TicketTransfer
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.CodedErrorResponse
+// @Router /transfers/{id} [delete]
+func CancelTransferHandler(c *gin.Context) {
+	var transfer models.TicketTransfer
+	if err := database.GetDB().First(&transfer, "id = ? AND from_user_id = ?", c.Param("id"), c.GetString("userID")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Transfer not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch transfer"})
+		return
+	}
+
+	now := time.Now()
+	result := database.GetDB().Model(&transfer).
+		Where("status = ?", models.TransferStatusPending).
+		Updates(map[string]interface{}{"status": models.TransferStatusCancelled, "cancelled_at": now})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel transfer"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusConflict, models.CodedErrorResponse{Error: "Transfer is no longer pending", Code: ErrCodeTransferClosed})
+		return
+	}
+
+	transfer.Status = models.TransferStatusCancelled
+	transfer.CancelledAt = &now
+	c.JSON(http.StatusOK, transfer)
+}
+
+// @Summary List my ticket transfers
+// @Description List the transfers the authenticated user sent or was sent, newest first
+// @Tags transfers
+// @Produce json
+// @Security Bearer
+// @Success 200 {array} models.TicketTransfer
+// @Router /transfers [get]
+func GetUserTransfersHandler(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	var user models.User
+	if err := database.GetDB().First(&user, "id = ?", userID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+		return
+	}
+
+	var transfers []models.TicketTransfer
+	if err := database.GetDB().Preload("Event").
+		Where("from_user_id = ? OR to_user_id = ? OR to_email = ?", userID, userID, strings.ToLower(user.Email)).
+		Order("created_at DESC").
+		Find(&transfers).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch transfers"})
+		return
+	}
+
+	now := time.Now()
+	for i := range transfers {
+		transfers[i].Status = transferStatus(&transfers[i], now)
+	}
+	c.JSON(http.StatusOK, transfers)
+}
+
+// @Summary Get a booking's transfer history
+// @Description List every transfer of a booking, oldest first, for its current owner or an admin
+// @Tags transfers
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Security Bearer
+// @Success 200 {array} models.TicketTransfer
+// @Failure 404 {object} models.ErrorResponse
+// @Router /bookings/{id}/transfers [get]
+func GetBookingTransfersHandler(c *gin.Context) {
+	query := database.GetDB().Where("id = ?", c.Param("id"))
+	if !auth.IsAdmin(c) {
+		query = query.Where("user_id = ?", c.GetString("userID"))
+	}
+	var booking models.Booking
+	if err := query.First(&booking).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch booking"})
+		return
+	}
+
+	var transfers []models.TicketTransfer
+	if err := database.GetDB().Where("booking_id = ?", booking.ID).Order("created_at ASC").Find(&transfers).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch transfers"})
+		return
+	}
+
+	now := time.Now()
+	for i := range transfers {
+		transfers[i].Status = transferStatus(&transfers[i], now)
+	}
+	c.JSON(http.StatusOK, transfers)
+}
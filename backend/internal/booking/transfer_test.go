@@ -0,0 +1,266 @@
+package booking
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+)
+
+func setupTransferTest(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&models.User{}, &models.Event{}, &models.TicketType{}, &models.Booking{}, &models.Attendee{}, &models.TicketTransfer{}, &models.Notification{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	database.DB = db
+
+	db.Create(&[]models.User{
+		{ID: "ada", Username: "ada", Email: "ada@example.com", Password: "x"},
+		{ID: "bob", Username: "bob", Email: "bob@example.com", Password: "x"},
+		{ID: "eve", Username: "eve", Email: "eve@example.com", Password: "x"},
+	})
+	db.Create(&[]models.Event{
+		{ID: "concert", Name: "Concert", Date: time.Now().Add(48 * time.Hour), Status: models.EventStatusPublished},
+		{ID: "gala", Name: "Gala", Date: time.Now().Add(48 * time.Hour), Status: models.EventStatusPublished, TransfersDisabled: true},
+	})
+	db.Create(&[]models.Booking{
+		{ID: "ticket", UserID: "ada", EventID: "concert", Quantity: 1, Status: models.BookingStatusConfirmed},
+		{ID: "gala-ticket", UserID: "ada", EventID: "gala", Quantity: 1, Status: models.BookingStatusConfirmed},
+		{ID: "unpaid", UserID: "ada", EventID: "concert", Quantity: 1, Status: models.BookingStatusPendingPayment},
+		{ID: "group", UserID: "ada", EventID: "concert", Quantity: 2, Status: models.BookingStatusConfirmed, Attendees: []models.Attendee{
+			{ID: "alan", Name: "Alan Turing", Email: "alan@example.com"}, {ID: "joan", Name: "Joan Clarke", Email: "joan@example.com"},
+		}},
+	})
+
+	r := gin.New()
+	api := r.Group("/api", func(c *gin.Context) { c.Set("userID", c.GetHeader("X-User")) })
+	api.POST("/bookings/:id/transfer", CreateTransferHandler)
+	api.GET("/bookings/:id/transfers", GetBookingTransfersHandler)
+	api.GET("/transfers", GetUserTransfersHandler)
+	api.POST("/transfers/accept", AcceptTransferHandler)
+	api.DELETE("/transfers/:id", CancelTransferHandler)
+	return r
+}
+
+func transferRequest(r *gin.Engine, method, path, user string, body interface{}) *httptest.ResponseRecorder {
+	data, _ := json.Marshal(body)
+	req := httptest.NewRequest(method, path, bytes.NewReader(data))
+	req.Header.Set("X-User", user)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// sentToken reads the transfer token from the last message sent to email
+func sentToken(t *testing.T, email string) string {
+	t.Helper()
+	var n models.Notification
+	if err := database.GetDB().Where("email = ? AND type = ?", email, models.NotificationTicketOffered).
+		Order("created_at DESC").First(&n).Error; err != nil {
+		t.Fatalf("no transfer offer sent to %s: %v", email, err)
+	}
+	return n.Message[strings.LastIndex(n.Message, " ")+1:]
+}
+
+func TestCreateTransfer(t *testing.T) {
+	r := setupTransferTest(t)
+
+	tests := []struct {
+		name       string
+		booking    string
+		email      string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "transfers disabled", booking: "gala-ticket", email: "bob@example.com", wantStatus: http.StatusForbidden, wantCode: ErrCodeTransfersDisabled},
+		{name: "not paid", booking: "unpaid", email: "bob@example.com", wantStatus: http.StatusConflict, wantCode: ErrCodeNotTransferable},
+		{name: "named attendees", booking: "group", email: "bob@example.com", wantStatus: http.StatusConflict, wantCode: ErrCodeNotTransferable},
+		{name: "to yourself", booking: "ticket", email: "ADA@example.com", wantStatus: http.StatusBadRequest},
+		{name: "someone else's booking", booking: "missing", email: "bob@example.com", wantStatus: http.StatusNotFound},
+		{name: "to a new address", booking: "ticket", email: "New@Example.com", wantStatus: http.StatusCreated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := transferRequest(r, http.MethodPost, "/api/bookings/"+tt.booking+"/transfer", "ada", models.CreateTransferRequest{Email: tt.email})
+			var refused models.CodedErrorResponse
+			json.Unmarshal(w.Body.Bytes(), &refused)
+			if w.Code != tt.wantStatus || refused.Code != tt.wantCode {
+				t.Errorf("got %d %q, want %d %q, body %s", w.Code, refused.Code, tt.wantStatus, tt.wantCode, w.Body.String())
+			}
+		})
+	}
+
+	var transfer models.TicketTransfer
+	database.GetDB().First(&transfer, "booking_id = ?", "ticket")
+	if transfer.ToEmail != "new@example.com" || transfer.ToUserID != nil || transfer.Status != models.TransferStatusPending {
+		t.Errorf("transfer = %+v, want a pending transfer to new@example.com", transfer)
+	}
+}
+
+func TestAcceptTransfer(t *testing.T) {
+	r := setupTransferTest(t)
+	var before models.Booking
+	database.GetDB().First(&before, "id = ?", "ticket")
+	oldTicket, _ := ticketToken(&before)
+
+	// The first offer is replaced by the second
+	transferRequest(r, http.MethodPost, "/api/bookings/ticket/transfer", "ada", models.CreateTransferRequest{Email: "eve@example.com"})
+	replaced := sentToken(t, "eve@example.com")
+	transferRequest(r, http.MethodPost, "/api/bookings/ticket/transfer", "ada", models.CreateTransferRequest{Email: "bob@example.com"})
+	token := sentToken(t, "bob@example.com")
+
+	tests := []struct {
+		name       string
+		user       string
+		token      string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "replaced offer", user: "eve", token: replaced, wantStatus: http.StatusConflict, wantCode: ErrCodeTransferClosed},
+		{name: "unknown token", user: "bob", token: "nope", wantStatus: http.StatusNotFound},
+		{name: "someone else", user: "eve", token: token, wantStatus: http.StatusForbidden},
+		{name: "recipient", user: "bob", token: token, wantStatus: http.StatusOK},
+		{name: "accepted twice", user: "bob", token: token, wantStatus: http.StatusConflict, wantCode: ErrCodeTransferClosed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := transferRequest(r, http.MethodPost, "/api/transfers/accept", tt.user, models.AcceptTransferRequest{Token: tt.token})
+			var refused models.CodedErrorResponse
+			json.Unmarshal(w.Body.Bytes(), &refused)
+			if w.Code != tt.wantStatus || refused.Code != tt.wantCode {
+				t.Errorf("got %d %q, want %d %q, body %s", w.Code, refused.Code, tt.wantStatus, tt.wantCode, w.Body.String())
+			}
+		})
+	}
+
+	var after models.Booking
+	database.GetDB().First(&after, "id = ?", "ticket")
+	newTicket, _ := ticketToken(&after)
+	if after.UserID != "bob" || after.TicketVersion != 1 {
+		t.Errorf("booking = %+v, want owned by bob with ticket version 1", after)
+	}
+	if claims, err := VerifyTicket(oldTicket); err != nil || claims.Version == after.TicketVersion || newTicket == oldTicket {
+		t.Errorf("old ticket %+v still matches the booking's ticket version %d", claims, after.TicketVersion)
+	}
+
+	// The new owner sees both offers, the previous owner no longer sees the booking
+	w := transferRequest(r, http.MethodGet, "/api/bookings/ticket/transfers", "bob", nil)
+	var history []models.TicketTransfer
+	json.Unmarshal(w.Body.Bytes(), &history)
+	if len(history) != 2 || history[0].Status != models.TransferStatusCancelled || history[1].Status != models.TransferStatusAccepted {
+		t.Errorf("history = %+v, want a cancelled and an accepted transfer", history)
+	}
+	if w := transferRequest(r, http.MethodGet, "/api/bookings/ticket/transfers", "ada", nil); w.Code != http.StatusNotFound {
+		t.Errorf("previous owner history status = %d, want 404", w.Code)
+	}
+	var sent []models.TicketTransfer
+	json.Unmarshal(transferRequest(r, http.MethodGet, "/api/transfers", "ada", nil).Body.Bytes(), &sent)
+	if len(sent) != 2 {
+		t.Errorf("ada's transfers = %d, want 2", len(sent))
+	}
+}
+
+func TestTransferExpiryAndCancel(t *testing.T) {
+	r := setupTransferTest(t)
+
+	transferRequest(r, http.MethodPost, "/api/bookings/ticket/transfer", "ada", models.CreateTransferRequest{Email: "bob@example.com"})
+	token := sentToken(t, "bob@example.com")
+	database.GetDB().Model(&models.TicketTransfer{}).Where("booking_id = ?", "ticket").Update("expires_at", time.Now().Add(-time.Minute))
+
+	w := transferRequest(r, http.MethodPost, "/api/transfers/accept", "bob", models.AcceptTransferRequest{Token: token})
+	var refused models.CodedErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &refused)
+	if w.Code != http.StatusConflict || refused.Code != ErrCodeTransferExpired {
+		t.Errorf("expired accept = %d %q, want 409 %q", w.Code, refused.Code, ErrCodeTransferExpired)
+	}
+
+	w = transferRequest(r, http.MethodPost, "/api/bookings/ticket/transfer", "ada", models.CreateTransferRequest{Email: "bob@example.com"})
+	var transfer models.TicketTransfer
+	json.Unmarshal(w.Body.Bytes(), &transfer)
+	if w := transferRequest(r, http.MethodDelete, "/api/transfers/"+transfer.ID, "bob", nil); w.Code != http.StatusNotFound {
+		t.Errorf("recipient cancel status = %d, want 404", w.Code)
+	}
+	if w := transferRequest(r, http.MethodDelete, "/api/transfers/"+transfer.ID, "ada", nil); w.Code != http.StatusOK {
+		t.Errorf("cancel status = %d, want 200", w.Code)
+	}
+	w = transferRequest(r, http.MethodPost, "/api/transfers/accept", "bob", models.AcceptTransferRequest{Token: sentToken(t, "bob@example.com")})
+	if w.Code != http.StatusConflict {
+		t.Errorf("accepting a cancelled transfer status = %d, want 409", w.Code)
+	}
+
+	var booking models.Booking
+	database.GetDB().First(&booking, "id = ?", "ticket")
+	if booking.UserID != "ada" || booking.TicketVersion != 0 {
+		t.Errorf("booking = %+v, want unchanged", booking)
+	}
+}
+
+func TestAcceptTransferAfterEventChanges(t *testing.T) {
+	tests := []struct {
+		name       string
+		change     map[string]interface{}
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "transfers disabled", change: map[string]interface{}{"transfers_disabled": true}, wantStatus: http.StatusForbidden, wantCode: ErrCodeTransfersDisabled},
+		{name: "postponed", change: map[string]interface{}{"status": models.EventStatusPostponed}, wantStatus: http.StatusConflict, wantCode: ErrCodeTransferClosed},
+		{name: "moved forward", change: map[string]interface{}{"date": time.Now().Add(-time.Minute)}, wantStatus: http.StatusConflict, wantCode: ErrCodeTransferExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := setupTransferTest(t)
+			transferRequest(r, http.MethodPost, "/api/bookings/ticket/transfer", "ada", models.CreateTransferRequest{Email: "bob@example.com"})
+			database.GetDB().Model(&models.Event{}).Where("id = ?", "concert").Updates(tt.change)
+
+			w := transferRequest(r, http.MethodPost, "/api/transfers/accept", "bob", models.AcceptTransferRequest{Token: sentToken(t, "bob@example.com")})
+			var refused models.CodedErrorResponse
+			json.Unmarshal(w.Body.Bytes(), &refused)
+			if w.Code != tt.wantStatus || refused.Code != tt.wantCode {
+				t.Errorf("got %d %q, want %d %q, body %s", w.Code, refused.Code, tt.wantStatus, tt.wantCode, w.Body.String())
+			}
+			var booking models.Booking
+			database.GetDB().First(&booking, "id = ?", "ticket")
+			if booking.UserID != "ada" {
+				t.Errorf("booking owner = %q, want ada", booking.UserID)
+			}
+		})
+	}
+}
+
+// A group booking offered before such transfers were refused cannot be
+// accepted, which would invalidate its attendees' tickets
+func TestAcceptGroupBookingTransfer(t *testing.T) {
+	r := setupTransferTest(t)
+	database.GetDB().Create(&models.TicketTransfer{ID: "group-offer", BookingID: "group", EventID: "concert", FromUserID: "ada",
+		ToEmail: "bob@example.com", TokenHash: hashToken("group-token"), Status: models.TransferStatusPending, ExpiresAt: time.Now().Add(time.Hour)})
+
+	w := transferRequest(r, http.MethodPost, "/api/transfers/accept", "bob", models.AcceptTransferRequest{Token: "group-token"})
+	var refused models.CodedErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &refused)
+	if w.Code != http.StatusConflict || refused.Code != ErrCodeTransferClosed {
+		t.Errorf("got %d %q, want 409 %q, body %s", w.Code, refused.Code, ErrCodeTransferClosed, w.Body.String())
+	}
+	var booking models.Booking
+	database.GetDB().First(&booking, "id = ?", "group")
+	if booking.UserID != "ada" || booking.TicketVersion != 0 {
+		t.Errorf("booking = %+v, want unchanged", booking)
+	}
+}
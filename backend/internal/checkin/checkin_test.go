@@ -0,0 +1,191 @@
+package checkin
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"online-task/internal/booking"
+	"online-task/internal/models"
+	"online-task/pkg/database"
+	"online-task/pkg/ticket"
+)
+
+// setupCheckInTest returns a router for a signed-in staff member and the
+// signer tickets are issued with
+func setupCheckInTest(t *testing.T) (*gin.Engine, *ticket.Signer) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&models.User{}, &models.Event{}, &models.TicketType{}, &models.Booking{}, &models.Attendee{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	database.DB = db
+
+	db.Create(&models.User{ID: "user-1", Username: "ada", Email: "ada@example.com", Password: "x"})
+	db.Create(&models.Event{ID: "concert", Name: "Concert", Date: time.Now().Add(time.Hour), Status: models.EventStatusPublished})
+	bookings := []models.Booking{
+		{ID: "confirmed", UserID: "user-1", EventID: "concert", Quantity: 2, Status: models.BookingStatusConfirmed},
+		{ID: "other", UserID: "user-1", EventID: "concert", Quantity: 3, Status: models.BookingStatusConfirmed},
+		{ID: "cancelled", UserID: "user-1", EventID: "concert", Quantity: 1, Status: models.BookingStatusCancelled},
+		{ID: "pending", UserID: "user-1", EventID: "concert", Quantity: 1, Status: models.BookingStatusPendingPayment},
+	}
+	if err := db.Create(&bookings).Error; err != nil {
+		t.Fatalf("failed to create bookings: %v", err)
+	}
+
+	signer, _ := ticket.GenerateSigner()
+	booking.SetTicketSigner(signer)
+
+	r := gin.New()
+	staff := r.Group("/api", func(c *gin.Context) { c.Set("userID", "staff-1") })
+	staff.POST("/checkin", CheckInHandler)
+	staff.GET("/events/:id/checkin/stats", CheckInStatsHandler)
+	staff.GET("/events/:id/checkin/manifest", ManifestHandler)
+	staff.POST("/events/:id/checkin/sync", SyncHandler)
+	return r, signer
+}
+
+func scan(r *gin.Engine, req models.CheckInRequest) (*httptest.ResponseRecorder, models.CheckInErrorResponse) {
+	body, _ := json.Marshal(req)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/checkin", bytes.NewReader(body)))
+
+	var refused models.CheckInErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &refused)
+	return w, refused
+}
+
+func TestCheckIn(t *testing.T) {
+	r, signer := setupCheckInTest(t)
+	other, _ := ticket.GenerateSigner()
+	sign := func(s *ticket.Signer, bookingID string) string {
+		token, _ := s.Sign(ticket.Claims{BookingID: bookingID, EventID: "concert"})
+		return token
+	}
+	// The other booking was transferred, so its first ticket was replaced
+	database.GetDB().Model(&models.Booking{}).Where("id = ?", "other").Update("ticket_version", 1)
+
+	tests := []struct {
+		name       string
+		req        models.CheckInRequest
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "valid ticket", req: models.CheckInRequest{Token: sign(signer, "confirmed"), EventID: "concert"}, wantStatus: http.StatusOK},
+		{name: "scanned again", req: models.CheckInRequest{Token: sign(signer, "confirmed")}, wantStatus: http.StatusConflict, wantCode: ErrCodeAlreadyCheckedIn},
+		{name: "forged signature", req: models.CheckInRequest{Token: sign(other, "other")}, wantStatus: http.StatusBadRequest, wantCode: ErrCodeTicketInvalid},
+		{name: "unknown booking", req: models.CheckInRequest{Token: sign(signer, "missing")}, wantStatus: http.StatusBadRequest, wantCode: ErrCodeTicketInvalid},
+		{name: "other event", req: models.CheckInRequest{Token: sign(signer, "other"), EventID: "theatre"}, wantStatus: http.StatusConflict, wantCode: ErrCodeWrongEvent},
+		{name: "transferred away", req: models.CheckInRequest{Token: sign(signer, "other")}, wantStatus: http.StatusConflict, wantCode: ErrCodeTicketSuperseded},
+		{name: "cancelled", req: models.CheckInRequest{Token: sign(signer, "cancelled")}, wantStatus: http.StatusConflict, wantCode: ErrCodeTicketCancelled},
+		{name: "unpaid", req: models.CheckInRequest{Token: sign(signer, "pending")}, wantStatus: http.StatusConflict, wantCode: ErrCodeTicketNotPaid},
+	}
+
+	// The cases run in order: the second scan sees the first check-in
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, refused := scan(r, tt.req)
+			if w.Code != tt.wantStatus || refused.Code != tt.wantCode {
+				t.Fatalf("got %d %q, want %d %q, body %s", w.Code, refused.Code, tt.wantStatus, tt.wantCode, w.Body.String())
+			}
+			if tt.wantCode == ErrCodeAlreadyCheckedIn && (refused.CheckedInBy == nil || *refused.CheckedInBy != "staff-1" || refused.CheckedInAt == nil) {
+				t.Errorf("refusal %+v does not say who checked the ticket in and when", refused)
+			}
+		})
+	}
+
+	var attended models.Booking
+	database.GetDB().First(&attended, "id = ?", "confirmed")
+	if attended.Status != models.BookingStatusAttended || attended.CheckedInBy == nil || *attended.CheckedInBy != "staff-1" {
+		t.Errorf("booking = %+v, want attended and checked in by staff-1", attended)
+	}
+}
+
+func TestCheckInStats(t *testing.T) {
+	r, signer := setupCheckInTest(t)
+	token, _ := signer.Sign(ticket.Claims{BookingID: "confirmed", EventID: "concert"})
+	scan(r, models.CheckInRequest{Token: token})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/events/concert/checkin/stats", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+	}
+
+	var stats models.CheckInStats
+	json.Unmarshal(w.Body.Bytes(), &stats)
+	if stats.Bookings != 2 || stats.Tickets != 5 || stats.CheckedInBookings != 1 || stats.CheckedInTickets != 2 ||
+		stats.RemainingTickets != 3 || stats.LastCheckInAt == nil {
+		t.Errorf("stats = %+v, want 1 of 2 bookings and 2 of 5 tickets checked in", stats)
+	}
+}
+
+func TestCheckInGroupBooking(t *testing.T) {
+	r, signer := setupCheckInTest(t)
+	group := models.Booking{ID: "group", UserID: "user-1", EventID: "concert", Quantity: 2, Status: models.BookingStatusConfirmed,
+		Attendees: []models.Attendee{
+			{ID: "grace", BookingID: "group", Name: "Grace", Email: "grace@example.com"},
+			{ID: "alan", BookingID: "group", Name: "Alan", Email: "alan@example.com"},
+		}}
+	database.GetDB().Create(&group)
+	sign := func(attendeeID string) string {
+		token, _ := signer.Sign(ticket.Claims{BookingID: "group", AttendeeID: attendeeID, EventID: "concert"})
+		return token
+	}
+
+	tests := []struct {
+		name       string
+		token      string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "attendee", token: sign("grace"), wantStatus: http.StatusOK},
+		{name: "same attendee again", token: sign("grace"), wantStatus: http.StatusConflict, wantCode: ErrCodeAlreadyCheckedIn},
+		{name: "whole booking", token: sign(""), wantStatus: http.StatusBadRequest, wantCode: ErrCodeTicketInvalid},
+		{name: "unknown attendee", token: sign("ada"), wantStatus: http.StatusBadRequest, wantCode: ErrCodeTicketInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, refused := scan(r, models.CheckInRequest{Token: tt.token})
+			if w.Code != tt.wantStatus || refused.Code != tt.wantCode {
+				t.Fatalf("got %d %q, want %d %q, body %s", w.Code, refused.Code, tt.wantStatus, tt.wantCode, w.Body.String())
+			}
+		})
+	}
+
+	// The other attendee arrives through an offline scanner
+	sync := syncScans(t, r, models.SyncRequest{DeviceID: "gate-1", Scans: []models.OfflineScan{{Token: sign("alan"), ScannedAt: time.Now().Add(-time.Minute)}}})
+	if sync.Accepted != 1 {
+		t.Fatalf("sync = %+v, want the attendee accepted", sync)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/events/concert/checkin/stats", nil))
+	var stats models.CheckInStats
+	json.Unmarshal(w.Body.Bytes(), &stats)
+	if stats.Bookings != 3 || stats.Tickets != 7 || stats.CheckedInBookings != 1 || stats.CheckedInTickets != 2 {
+		t.Errorf("stats = %+v, want the group booking's 2 tickets checked in", stats)
+	}
+
+	var attended models.Booking
+	var alan models.Attendee
+	database.GetDB().First(&attended, "id = ?", "group")
+	database.GetDB().First(&alan, "id = ?", "alan")
+	if attended.Status != models.BookingStatusAttended || !attended.CheckedInAt.Equal(*alan.CheckedInAt) {
+		t.Errorf("booking = %+v, want attended from the earliest arrival", attended)
+	}
+}
@@ -0,0 +1,235 @@
+package checkin
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"online-task/internal/booking"
+	"online-task/internal/models"
+	"online-task/pkg/database"
+	"online-task/pkg/ticket"
+)
+
+// Error codes returned when a scanned ticket is refused
+const (
+	ErrCodeTicketInvalid    = "ticket_invalid"
+	ErrCodeWrongEvent       = "wrong_event"
+	ErrCodeTicketCancelled  = "ticket_cancelled"
+	ErrCodeTicketNotPaid    = "ticket_not_paid"
+	ErrCodeAlreadyCheckedIn = "already_checked_in"
+	ErrCodeTicketSuperseded = "ticket_superseded"
+)
+
+// refusal explains why a booking cannot be checked in, or returns nil if it can
+func refusal(b *models.Booking) *models.CheckInErrorResponse {
+	switch b.Status {
+	case models.BookingStatusConfirmed:
+		return nil
+	case models.BookingStatusAttended:
+		return &models.CheckInErrorResponse{
+			Error:       "Ticket has already been used",
+			Code:        ErrCodeAlreadyCheckedIn,
+			CheckedInAt: b.CheckedInAt,
+			CheckedInBy: b.CheckedInBy,
+		}
+	case models.BookingStatusPendingPayment:
+		return &models.CheckInErrorResponse{Error: "Ticket has not been paid for", Code: ErrCodeTicketNotPaid}
+	default:
+		return &models.CheckInErrorResponse{Error: "Ticket has been cancelled", Code: ErrCodeTicketCancelled}
+	}
+}
+
+// superseded reports a ticket issued before its booking was transferred, or
+// returns nil if the ticket is current
+func superseded(claims ticket.Claims, b *models.Booking) *models.CheckInErrorResponse {
+	if claims.Version == b.TicketVersion {
+		return nil
+	}
+	return &models.CheckInErrorResponse{Error: "Ticket was transferred and has been reissued", Code: ErrCodeTicketSuperseded}
+}
+
+// @Summary Check in a ticket
+// @Description Verify a scanned ticket and mark its booking as attended (staff only). Refused tickets return a code: ticket_invalid for bad signatures or unknown bookings, wrong_event for tickets of another event, ticket_superseded for tickets replaced by a transfer, ticket_cancelled, ticket_not_paid, and already_checked_in with when and by whom the ticket was used.
+// @Tags checkin
+// @Accept json
+// @Produce json
+// @Param request body models.CheckInRequest true "Scanned ticket"
+// @Security Bearer
+// @Success 200 {object} models.CheckInResponse
+// @Failure 400 {object} models.CheckInErrorResponse
+// @Failure 409 {object} models.CheckInErrorResponse
+// @Router /checkin [post]
+func CheckInHandler(c *gin.Context) {
+	var req models.CheckInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims, err := booking.VerifyTicket(req.Token)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.CheckInErrorResponse{Error: "Ticket signature is not valid", Code: ErrCodeTicketInvalid})
+		return
+	}
+
+	var b models.Booking
+	if err := database.GetDB().Preload("User").Preload("TicketType").Preload("Attendees").First(&b, "id = ?", claims.BookingID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusBadRequest, models.CheckInErrorResponse{Error: "Ticket does not belong to a booking", Code: ErrCodeTicketInvalid})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch booking"})
+		return
+	}
+	if req.EventID != "" && b.EventID != req.EventID {
+		c.JSON(http.StatusConflict, models.CheckInErrorResponse{Error: "Ticket is for another event", Code: ErrCodeWrongEvent})
+		return
+	}
+	if refused := superseded(claims, &b); refused != nil {
+		c.JSON(http.StatusConflict, refused)
+		return
+	}
+	h, refused := findHolder(claims, &b)
+	if refused != nil {
+		c.JSON(http.StatusBadRequest, refused)
+		return
+	}
+	if refused := h.refusal(); refused != nil {
+		c.JSON(http.StatusConflict, refused)
+		return
+	}
+
+	staffID := c.GetString("userID")
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	// Only one of two simultaneous scans of the same ticket can check it in
+	checkedIn, err := h.markCheckedIn(now, staffID, nil, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check in"})
+		return
+	}
+	if !checkedIn {
+		if err := database.GetDB().Preload("Attendees").First(&b, "id = ?", b.ID).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch booking"})
+			return
+		}
+		if refused := superseded(claims, &b); refused != nil {
+			c.JSON(http.StatusConflict, refused)
+			return
+		}
+		h, _ = findHolder(claims, &b)
+		c.JSON(http.StatusConflict, h.refusal())
+		return
+	}
+
+	response := models.CheckInResponse{
+		BookingID:   b.ID,
+		EventID:     b.EventID,
+		Attendee:    b.HolderName(),
+		Quantity:    b.Quantity,
+		CheckedInAt: now,
+		CheckedInBy: staffID,
+	}
+	if h.attendee != nil {
+		response.AttendeeID = h.attendee.ID
+		response.Attendee = h.attendee.Name
+		response.Quantity = 1
+	}
+	if b.TicketType != nil {
+		response.TicketType = b.TicketType.Name
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Get check-in statistics
+// @Description Get live counts of an event's bookings and tickets and how many have been checked in (staff only)
+// @Tags checkin
+// @Produce json
+// @Param id path string true "Event ID"
+// @Security Bearer
+// @Success 200 {object} models.CheckInStats
+// @Failure 404 {object} models.ErrorResponse
+// @Router /events/{id}/checkin/stats [get]
+func CheckInStatsHandler(c *gin.Context) {
+	var event models.Event
+	if err := database.GetDB().First(&event, "id = ?", c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch event"})
+		return
+	}
+
+	var counts []struct {
+		Status   string
+		Bookings int64
+		Tickets  int64
+	}
+	if err := database.GetDB().Model(&models.Booking{}).
+		Select("status, COUNT(*) AS bookings, COALESCE(SUM(quantity), 0) AS tickets").
+		Where("event_id = ? AND status IN ?", event.ID, []string{models.BookingStatusConfirmed, models.BookingStatusAttended}).
+		Group("status").
+		Scan(&counts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count bookings"})
+		return
+	}
+
+	stats := models.CheckInStats{EventID: event.ID}
+	for _, count := range counts {
+		stats.Bookings += count.Bookings
+		stats.Tickets += count.Tickets
+		if count.Status == models.BookingStatusAttended {
+			stats.CheckedInBookings = count.Bookings
+			stats.CheckedInTickets = count.Tickets
+		}
+	}
+
+	// Group bookings count as attended from their first arrival, so only
+	// the attendees who have arrived count as checked-in tickets
+	attendees := database.GetDB().Model(&models.Attendee{}).
+		Joins("JOIN bookings ON bookings.id = attendees.booking_id").
+		Where("bookings.event_id = ? AND bookings.status = ? AND bookings.deleted_at IS NULL", event.ID, models.BookingStatusAttended)
+	var named struct {
+		Tickets int64
+		Arrived int64
+	}
+	if err := attendees.Session(&gorm.Session{}).
+		Select("COUNT(*) AS tickets, COUNT(attendees.checked_in_at) AS arrived").
+		Scan(&named).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count attendees"})
+		return
+	}
+	stats.CheckedInTickets += named.Arrived - named.Tickets
+	stats.RemainingTickets = stats.Tickets - stats.CheckedInTickets
+
+	if stats.CheckedInBookings > 0 {
+		var last models.Booking
+		if err := database.GetDB().Select("checked_in_at").
+			Where("event_id = ? AND status = ?", event.ID, models.BookingStatusAttended).
+			Order("checked_in_at DESC").
+			First(&last).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch last check-in"})
+			return
+		}
+		stats.LastCheckInAt = last.CheckedInAt
+
+		var lastAttendee models.Attendee
+		err := attendees.Session(&gorm.Session{}).
+			Select("attendees.checked_in_at").
+			Where("attendees.checked_in_at IS NOT NULL").
+			Order("attendees.checked_in_at DESC").
+			First(&lastAttendee).Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch last check-in"})
+			return
+		}
+		if lastAttendee.CheckedInAt != nil && lastAttendee.CheckedInAt.After(*stats.LastCheckInAt) {
+			stats.LastCheckInAt = lastAttendee.CheckedInAt
+		}
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
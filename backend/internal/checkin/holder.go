@@ -0,0 +1,128 @@
+package checkin
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+	"online-task/pkg/ticket"
+)
+
+var errTicketChanged = errors.New("ticket changed while checking in")
+
+// holder is who a ticket admits: a whole booking, or one named attendee of
+// a group booking
+type holder struct {
+	booking  *models.Booking
+	attendee *models.Attendee
+}
+
+// findHolder picks the holder of a ticket from its booking, loaded with its
+// attendees, or explains why the ticket admits no one
+func findHolder(claims ticket.Claims, b *models.Booking) (holder, *models.CheckInErrorResponse) {
+	if claims.AttendeeID == "" {
+		if len(b.Attendees) > 0 {
+			return holder{}, &models.CheckInErrorResponse{Error: "Group bookings are admitted by their attendees' tickets", Code: ErrCodeTicketInvalid}
+		}
+		return holder{booking: b}, nil
+	}
+	for i := range b.Attendees {
+		if b.Attendees[i].ID == claims.AttendeeID {
+			return holder{booking: b, attendee: &b.Attendees[i]}, nil
+		}
+	}
+	return holder{}, &models.CheckInErrorResponse{Error: "Ticket does not belong to an attendee of the booking", Code: ErrCodeTicketInvalid}
+}
+
+// checkedIn returns when and on which device the holder was checked in
+func (h holder) checkedIn() (*time.Time, *string) {
+	if h.attendee != nil {
+		return h.attendee.CheckedInAt, h.attendee.CheckedInDevice
+	}
+	return h.booking.CheckedInAt, h.booking.CheckedInDevice
+}
+
+// refusal explains why the holder cannot be checked in, or returns nil if
+// they can. Attendees of a group booking are let in one by one.
+func (h holder) refusal() *models.CheckInErrorResponse {
+	if h.attendee == nil {
+		return refusal(h.booking)
+	}
+	switch h.booking.Status {
+	case models.BookingStatusConfirmed, models.BookingStatusAttended:
+		if h.attendee.CheckedInAt == nil {
+			return nil
+		}
+		return &models.CheckInErrorResponse{
+			Error:       "Ticket has already been used",
+			Code:        ErrCodeAlreadyCheckedIn,
+			CheckedInAt: h.attendee.CheckedInAt,
+			CheckedInBy: h.attendee.CheckedInBy,
+		}
+	}
+	return refusal(h.booking)
+}
+
+// markCheckedIn records the holder's check-in at the given time. replaces
+// is the time of the stored check-in this one supersedes, or nil if the
+// ticket must not have been used. It reports false, changing nothing, if
+// the ticket was checked in, cancelled or reissued since it was loaded.
+func (h holder) markCheckedIn(at time.Time, staffID string, device *string, replaces *time.Time) (bool, error) {
+	updates := map[string]interface{}{
+		"checked_in_at":     at,
+		"checked_in_by":     staffID,
+		"checked_in_device": device,
+	}
+
+	if h.attendee == nil {
+		query := database.GetDB().Model(&models.Booking{}).Where("id = ? AND ticket_version = ?", h.booking.ID, h.booking.TicketVersion)
+		if replaces == nil {
+			query = query.Where("status = ?", models.BookingStatusConfirmed)
+		} else {
+			query = query.Where("status = ? AND checked_in_at = ?", models.BookingStatusAttended, *replaces)
+		}
+		updates["status"] = models.BookingStatusAttended
+		result := query.Updates(updates)
+		return result.RowsAffected > 0, result.Error
+	}
+
+	err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.Attendee{}).Where("id = ?", h.attendee.ID)
+		if replaces == nil {
+			query = query.Where("checked_in_at IS NULL")
+		} else {
+			query = query.Where("checked_in_at = ?", *replaces)
+		}
+		result := query.Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errTicketChanged
+		}
+
+		// A group booking counts as attended from its first arrival
+		result = tx.Model(&models.Booking{}).
+			Where("id = ? AND ticket_version = ? AND status IN ?", h.booking.ID, h.booking.TicketVersion,
+				[]string{models.BookingStatusConfirmed, models.BookingStatusAttended}).
+			Updates(map[string]interface{}{
+				"status":        models.BookingStatusAttended,
+				"checked_in_at": gorm.Expr("CASE WHEN checked_in_at IS NULL OR checked_in_at > ? THEN ? ELSE checked_in_at END", at, at),
+				"checked_in_by": gorm.Expr("COALESCE(checked_in_by, ?)", staffID),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errTicketChanged
+		}
+		return nil
+	})
+	if err == errTicketChanged {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,250 @@
+package checkin
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"online-task/internal/booking"
+	"online-task/internal/models"
+	"online-task/pkg/database"
+)
+
+// ErrCodeInvalidScanTime rejects offline scans dated in the future
+const ErrCodeInvalidScanTime = "invalid_scan_time"
+
+// maxClockSkew is how far ahead of the server a scanner's clock may be
+const maxClockSkew = 5 * time.Minute
+
+// maxSyncAttempts bounds retries when another scan of the same ticket is
+// stored while one is being resolved
+const maxSyncAttempts = 3
+
+// findEvent fetches the event of the request path, responding with an error
+// if it does not exist
+func findEvent(c *gin.Context) (*models.Event, bool) {
+	var event models.Event
+	if err := database.GetDB().First(&event, "id = ?", c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch event"})
+		return nil, false
+	}
+	return &event, true
+}
+
+// @Summary Get an event's check-in manifest
+// @Description Get every paid booking of an event, signed with the e-ticket key, for scanners to cache and check tickets without a connection (staff only). Scanners verify the signature of "manifest:" followed by the payload with the ticket public key, then decode the base64url JSON payload. Tickets issued after the manifest still verify by their own signature.
+// @Tags checkin
+// @Produce json
+// @Param id path string true "Event ID"
+// @Security Bearer
+// @Success 200 {object} models.SignedManifest
+// @Failure 404 {object} models.ErrorResponse
+// @Router /events/{id}/checkin/manifest [get]
+func ManifestHandler(c *gin.Context) {
+	event, ok := findEvent(c)
+	if !ok {
+		return
+	}
+
+	// Cancelled bookings are listed so scanners can say why they are refused
+	var bookings []models.Booking
+	if err := database.GetDB().Preload("User").Preload("TicketType").Preload("Attendees").
+		Where("event_id = ? AND status <> ?", event.ID, models.BookingStatusPendingPayment).
+		Order("created_at ASC").
+		Find(&bookings).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch bookings"})
+		return
+	}
+
+	manifest := models.Manifest{
+		EventID:     event.ID,
+		GeneratedAt: time.Now().UTC(),
+		Entries:     make([]models.ManifestEntry, 0, len(bookings)),
+	}
+	for _, b := range bookings {
+		entry := models.ManifestEntry{
+			BookingID:     b.ID,
+			TicketVersion: b.TicketVersion,
+			Status:        b.Status,
+			Quantity:      b.Quantity,
+			Attendee:      b.HolderName(),
+			CheckedInAt:   b.CheckedInAt,
+		}
+		if b.TicketType != nil {
+			entry.TicketType = b.TicketType.Name
+		}
+		for _, attendee := range b.Attendees {
+			entry.Attendees = append(entry.Attendees, models.ManifestAttendee{
+				ID:          attendee.ID,
+				Name:        attendee.Name,
+				CheckedInAt: attendee.CheckedInAt,
+			})
+		}
+		manifest.Entries = append(manifest.Entries, entry)
+	}
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encode manifest"})
+		return
+	}
+	payload, signature := booking.SignManifest(data)
+
+	c.Header("Cache-Control", "private, no-store")
+	c.JSON(http.StatusOK, models.SignedManifest{Payload: payload, Signature: signature})
+}
+
+// scanWins orders competing scans of a ticket: the earliest scan wins, and
+// scans at the same instant go to the lower device ID (online check-ins
+// have none)
+func scanWins(at time.Time, device string, otherAt time.Time, otherDevice string) bool {
+	if !at.Equal(otherAt) {
+		return at.Before(otherAt)
+	}
+	return device < otherDevice
+}
+
+// syncScan stores one offline scan and reports its outcome
+func syncScan(eventID, deviceID, staffID string, scan models.OfflineScan, now time.Time) models.SyncResult {
+	result := models.SyncResult{Token: scan.Token}
+	reject := func(code, message string) models.SyncResult {
+		result.Result = models.SyncRejected
+		result.Code = code
+		result.Error = message
+		return result
+	}
+
+	claims, err := booking.VerifyTicket(scan.Token)
+	if err != nil {
+		return reject(ErrCodeTicketInvalid, "Ticket signature is not valid")
+	}
+	result.BookingID = claims.BookingID
+
+	scannedAt := scan.ScannedAt.UTC().Truncate(time.Microsecond)
+	if scannedAt.After(now.Add(maxClockSkew)) {
+		return reject(ErrCodeInvalidScanTime, "Scan time is in the future")
+	}
+
+	for attempt := 0; attempt < maxSyncAttempts; attempt++ {
+		var b models.Booking
+		if err := database.GetDB().Preload("Attendees").First(&b, "id = ?", claims.BookingID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return reject(ErrCodeTicketInvalid, "Ticket does not belong to a booking")
+			}
+			return reject("", "Failed to fetch booking")
+		}
+		if b.EventID != eventID {
+			return reject(ErrCodeWrongEvent, "Ticket is for another event")
+		}
+		if refused := superseded(claims, &b); refused != nil {
+			return reject(refused.Code, refused.Error)
+		}
+		h, refused := findHolder(claims, &b)
+		if refused != nil {
+			return reject(refused.Code, refused.Error)
+		}
+
+		// replaces stays nil for tickets that have not been used
+		var replaces *time.Time
+		if refused := h.refusal(); refused != nil {
+			if refused.Code != ErrCodeAlreadyCheckedIn {
+				return reject(refused.Code, refused.Error)
+			}
+			winnerAt, winnerDevice := h.checkedIn()
+			device := ""
+			if winnerDevice != nil {
+				device = *winnerDevice
+			}
+			if winnerAt.Equal(scannedAt) && device == deviceID {
+				// A retried upload of a scan that was already stored
+				result.Result = models.SyncAccepted
+				result.CheckedInAt = winnerAt
+				result.DeviceID = winnerDevice
+				return result
+			}
+			if !scanWins(scannedAt, deviceID, *winnerAt, device) {
+				result.Result = models.SyncDuplicate
+				result.Code = ErrCodeAlreadyCheckedIn
+				result.Error = "Ticket was already used"
+				result.CheckedInAt = winnerAt
+				result.DeviceID = winnerDevice
+				return result
+			}
+			// This scan came first, so it replaces the stored check-in
+			replaces = winnerAt
+		}
+
+		checkedIn, err := h.markCheckedIn(scannedAt, staffID, &deviceID, replaces)
+		if err != nil {
+			return reject("", "Failed to check in")
+		}
+		if checkedIn {
+			result.Result = models.SyncAccepted
+			result.CheckedInAt = &scannedAt
+			result.DeviceID = &deviceID
+			return result
+		}
+		// Another scan of the ticket was stored meanwhile; resolve against it
+	}
+	return reject("", "Ticket is being checked in elsewhere; sync again")
+}
+
+// @Summary Sync offline check-ins
+// @Description Upload check-ins a scanner recorded offline (staff only). When a ticket was scanned more than once, on any devices or online, the earliest scan wins and ties go to the lower device ID, so every upload order gives the same result. Each scan is reported as accepted, duplicate (naming the winning scan) or rejected with the same codes as online check-in, plus invalid_scan_time for scans dated more than 5 minutes in the future. Re-uploading a batch is safe.
+// @Tags checkin
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param request body models.SyncRequest true "Offline scans"
+// @Security Bearer
+// @Success 200 {object} models.SyncResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /events/{id}/checkin/sync [post]
+func SyncHandler(c *gin.Context) {
+	var req models.SyncRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	event, ok := findEvent(c)
+	if !ok {
+		return
+	}
+
+	// Scans are stored in the order they happened; results keep the order
+	// they were sent in
+	order := make([]int, len(req.Scans))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return req.Scans[order[a]].ScannedAt.Before(req.Scans[order[b]].ScannedAt)
+	})
+
+	staffID := c.GetString("userID")
+	now := time.Now()
+	response := models.SyncResponse{Results: make([]models.SyncResult, len(req.Scans))}
+	for _, i := range order {
+		result := syncScan(event.ID, req.DeviceID, staffID, req.Scans[i], now)
+		switch result.Result {
+		case models.SyncAccepted:
+			response.Accepted++
+		case models.SyncDuplicate:
+			response.Duplicates++
+		default:
+			response.Rejected++
+		}
+		response.Results[i] = result
+	}
+
+	c.JSON(http.StatusOK, response)
+}
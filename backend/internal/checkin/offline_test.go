@@ -0,0 +1,117 @@
+package checkin
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"online-task/internal/models"
+	"online-task/pkg/ticket"
+)
+
+func syncScans(t *testing.T, r *gin.Engine, req models.SyncRequest) models.SyncResponse {
+	t.Helper()
+	body, _ := json.Marshal(req)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/events/concert/checkin/sync", bytes.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("sync status = %d, body %s", w.Code, w.Body.String())
+	}
+
+	var response models.SyncResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	return response
+}
+
+func TestManifest(t *testing.T) {
+	r, signer := setupCheckInTest(t)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/events/concert/checkin/manifest", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+	}
+
+	var signed models.SignedManifest
+	json.Unmarshal(w.Body.Bytes(), &signed)
+	data, err := ticket.VerifyPayload(signer.PublicKey(), ticket.ContextManifest, signed.Payload, signed.Signature)
+	if err != nil {
+		t.Fatalf("VerifyPayload() error = %v", err)
+	}
+	var manifest models.Manifest
+	json.Unmarshal(data, &manifest)
+
+	statuses := map[string]string{}
+	for _, entry := range manifest.Entries {
+		statuses[entry.BookingID] = entry.Status
+	}
+	want := map[string]string{"confirmed": models.BookingStatusConfirmed, "other": models.BookingStatusConfirmed, "cancelled": models.BookingStatusCancelled}
+	if len(statuses) != len(want) {
+		t.Fatalf("manifest lists %v, want %v", statuses, want)
+	}
+	for id, status := range want {
+		if statuses[id] != status {
+			t.Errorf("manifest status of %s = %q, want %q", id, statuses[id], status)
+		}
+	}
+}
+
+func TestSyncResolvesConflicts(t *testing.T) {
+	r, signer := setupCheckInTest(t)
+	sign := func(bookingID string) string {
+		token, _ := signer.Sign(ticket.Claims{BookingID: bookingID, EventID: "concert"})
+		return token
+	}
+	base := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+
+	// gate-2 uploads first, but gate-1 scanned the same ticket earlier
+	first := syncScans(t, r, models.SyncRequest{DeviceID: "gate-2", Scans: []models.OfflineScan{
+		{Token: sign("confirmed"), ScannedAt: base.Add(2 * time.Minute)},
+		{Token: sign("cancelled"), ScannedAt: base},
+		{Token: sign("other"), ScannedAt: time.Now().Add(time.Hour)},
+	}})
+	if first.Accepted != 1 || first.Rejected != 2 ||
+		first.Results[1].Code != ErrCodeTicketCancelled || first.Results[2].Code != ErrCodeInvalidScanTime {
+		t.Fatalf("first sync = %+v", first)
+	}
+
+	second := syncScans(t, r, models.SyncRequest{DeviceID: "gate-1", Scans: []models.OfflineScan{
+		{Token: sign("confirmed"), ScannedAt: base.Add(time.Minute)},
+		{Token: sign("confirmed"), ScannedAt: base.Add(3 * time.Minute)},
+	}})
+	if second.Accepted != 1 || second.Duplicates != 1 || second.Results[0].Result != models.SyncAccepted {
+		t.Fatalf("second sync = %+v", second)
+	}
+	if winner := second.Results[1]; winner.DeviceID == nil || *winner.DeviceID != "gate-1" || !winner.CheckedInAt.Equal(base.Add(time.Minute)) {
+		t.Errorf("duplicate names %+v, want gate-1's earlier scan", winner)
+	}
+
+	tests := []struct {
+		name   string
+		device string
+		at     time.Time
+		want   string
+	}{
+		{name: "retried upload", device: "gate-1", at: base.Add(time.Minute), want: models.SyncAccepted},
+		{name: "later scan", device: "gate-2", at: base.Add(2 * time.Minute), want: models.SyncDuplicate},
+		{name: "same instant, higher device", device: "gate-3", at: base.Add(time.Minute), want: models.SyncDuplicate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := syncScans(t, r, models.SyncRequest{DeviceID: tt.device, Scans: []models.OfflineScan{{Token: sign("confirmed"), ScannedAt: tt.at}}})
+			if got.Results[0].Result != tt.want || *got.Results[0].DeviceID != "gate-1" {
+				t.Errorf("result = %+v, want %s with gate-1 winning", got.Results[0], tt.want)
+			}
+		})
+	}
+
+	// Online check-ins see the offline one
+	if _, refused := scan(r, models.CheckInRequest{Token: sign("confirmed")}); refused.Code != ErrCodeAlreadyCheckedIn {
+		t.Errorf("online scan code = %q, want %q", refused.Code, ErrCodeAlreadyCheckedIn)
+	}
+}
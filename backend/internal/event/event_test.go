@@ -1,84 +1,288 @@
 package event
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
 )
 
+func setupEventTest(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&models.Event{}, &models.Tag{}, &models.EventMedia{}, &models.Venue{}, &models.TicketType{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	database.DB = db
+
+	r := gin.New()
+	r.GET("/api/events", GetAllEventsHandler)
+	r.GET("/api/events/:id", GetEventHandler)
+	return r
+}
+
+func seedEvent(t *testing.T, id string, start, end time.Time, timeZone string) {
+	t.Helper()
+	event := models.Event{
+		ID:       id,
+		Name:     id,
+		Date:     start,
+		EndDate:  end,
+		TimeZone: timeZone,
+		Status:   models.EventStatusPublished,
+	}
+	if err := database.GetDB().Create(&event).Error; err != nil {
+		t.Fatalf("failed to create event: %v", err)
+	}
+}
+
 func TestCreateEvent(t *testing.T) {
 	tests := []struct {
 		name        string
-		event       Event
+		req         models.CreateEventRequest
 		wantErr     bool
 		description string
 	}{
 		{
 			name: "valid event",
-			event: Event{
-				Title:       "Test Event",
-				Description: "Test Description",
-				StartTime:   time.Now().Add(24 * time.Hour),
-				EndTime:     time.Now().Add(48 * time.Hour),
-				Location:    "Test Location",
-				CreatorID:   1,
+			req: models.CreateEventRequest{
+				Name:     "Test Event",
+				Date:     time.Now().Add(24 * time.Hour),
+				EndDate:  time.Now().Add(48 * time.Hour),
+				Location: "Test Location",
 			},
 			wantErr:     false,
 			description: "Should create event successfully",
 		},
 		{
 			name: "invalid time range",
-			event: Event{
-				Title:       "Test Event",
-				Description: "Test Description",
-				StartTime:   time.Now().Add(48 * time.Hour),
-				EndTime:     time.Now().Add(24 * time.Hour),
-				Location:    "Test Location",
-				CreatorID:   1,
+			req: models.CreateEventRequest{
+				Name:     "Test Event",
+				Date:     time.Now().Add(48 * time.Hour),
+				EndDate:  time.Now().Add(24 * time.Hour),
+				Location: "Test Location",
 			},
 			wantErr:     true,
 			description: "Should fail when end time is before start time",
 		},
+		{
+			name: "unknown time zone",
+			req: models.CreateEventRequest{
+				Name:     "Test Event",
+				Date:     time.Now().Add(24 * time.Hour),
+				EndDate:  time.Now().Add(48 * time.Hour),
+				TimeZone: "Mars/Olympus_Mons",
+			},
+			wantErr:     true,
+			description: "Should fail for a time zone that is not in the IANA database",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := CreateEvent(&tt.event)
+			err := validateEventTimes(&tt.req)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("%s: CreateEvent() error = %v, wantErr %v", tt.description, err, tt.wantErr)
+				t.Errorf("%s: validateEventTimes() error = %v, wantErr %v", tt.description, err, tt.wantErr)
 			}
 		})
 	}
 }
 
 func TestGetEvent(t *testing.T) {
+	r := setupEventTest(t)
+	start := time.Date(2024, 7, 1, 18, 0, 0, 0, time.UTC)
+	seedEvent(t, "festival", start, start.Add(3*time.Hour), "Europe/Berlin")
+
 	tests := []struct {
-		name    string
-		id      int
-		want    *Event
-		wantErr bool
+		name string
+		id   string
+		want int
 	}{
 		{
-			name:    "existing event",
-			id:      1,
-			wantErr: false,
+			name: "existing event",
+			id:   "festival",
+			want: http.StatusOK,
 		},
 		{
-			name:    "non-existing event",
-			id:      999,
-			wantErr: true,
+			name: "non-existing event",
+			id:   "missing",
+			want: http.StatusNotFound,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := GetEvent(tt.id)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("GetEvent() error = %v, wantErr %v", err, tt.wantErr)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/events/"+tt.id, nil))
+			if w.Code != tt.want {
+				t.Fatalf("GetEventHandler() status = %d, want %d", w.Code, tt.want)
+			}
+			if tt.want != http.StatusOK {
 				return
 			}
-			if !tt.wantErr && got == nil {
-				t.Error("GetEvent() returned nil for existing event")
+
+			var got map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("invalid response: %v", err)
+			}
+			if got["date"] != "2024-07-01T18:00:00Z" || got["localDate"] != "2024-07-01T20:00:00+02:00" {
+				t.Errorf("got date %v, localDate %v", got["date"], got["localDate"])
+			}
+			if got["durationMinutes"] != float64(180) {
+				t.Errorf("got durationMinutes %v, want 180", got["durationMinutes"])
+			}
+		})
+	}
+}
+
+func TestListEventsOverlappingRange(t *testing.T) {
+	r := setupEventTest(t)
+	day := func(d int) time.Time { return time.Date(2024, 7, d, 0, 0, 0, 0, time.UTC) }
+	seedEvent(t, "before", day(1), day(2), "UTC")
+	seedEvent(t, "festival", day(3), day(8), "UTC")
+	seedEvent(t, "after", day(10), day(11), "UTC")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/events?from=2024-07-05T00:00:00Z&to=2024-07-06T00:00:00Z", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+	}
+
+	var events []models.Event
+	if err := json.Unmarshal(w.Body.Bytes(), &events); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if len(events) != 1 || events[0].ID != "festival" {
+		t.Errorf("got %+v, want only the multi-day festival", events)
+	}
+}
+
+func TestDisablingTransfersCancelsPendingOnes(t *testing.T) {
+	r := setupEventTest(t)
+	if err := database.GetDB().AutoMigrate(&models.TicketTransfer{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	r.PUT("/api/events/:id", UpdateEventHandler)
+
+	start := time.Now().Add(72 * time.Hour).UTC().Truncate(time.Second)
+	seedEvent(t, "concert", start, start.Add(2*time.Hour), "Europe/Berlin")
+	database.GetDB().Create(&models.TicketTransfer{ID: "pending", BookingID: "ticket", EventID: "concert", FromUserID: "ada",
+		ToEmail: "bob@example.com", TokenHash: "hash", Status: models.TransferStatusPending, ExpiresAt: start})
+
+	req := meetupRequest(start)
+	req.TransfersDisabled = true
+	if w := sendJSON(r, http.MethodPut, "/api/events/concert", req); w.Code != http.StatusOK {
+		t.Fatalf("update status = %d, body %s", w.Code, w.Body.String())
+	}
+
+	var transfer models.TicketTransfer
+	database.GetDB().First(&transfer, "id = ?", "pending")
+	if transfer.Status != models.TransferStatusCancelled || transfer.CancelledAt == nil {
+		t.Errorf("transfer = %+v, want it cancelled", transfer)
+	}
+}
+
+func TestDeleteEvent(t *testing.T) {
+	r := setupEventTest(t)
+	if err := database.GetDB().AutoMigrate(&models.Booking{}, &models.SeatMap{}, &models.Seat{}, &models.SeatHold{}, &models.SeatReservation{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	r.DELETE("/api/events/:id", DeleteEventHandler)
+
+	start := time.Now().Add(72 * time.Hour)
+	seedEvent(t, "empty", start, start.Add(time.Hour), "UTC")
+	seedEvent(t, "booked", start, start.Add(time.Hour), "UTC")
+	database.GetDB().Create(&models.Booking{ID: "cancelled", UserID: "ada", EventID: "booked", Quantity: 1, Status: models.BookingStatusCancelled})
+
+	// What the empty event sells from, next to a venue's seat map and the
+	// booked event's ticket type
+	empty, venue, hold := "empty", "hall", "hold"
+	database.GetDB().Create(&[]models.TicketType{{ID: "empty-standard", EventID: "empty", Name: "Standard"}, {ID: "booked-standard", EventID: "booked", Name: "Standard"}})
+	database.GetDB().Create(&[]models.SeatMap{{ID: "empty-map", EventID: &empty, Name: "Stage"}, {ID: "venue-map", VenueID: &venue, Name: "Hall"}})
+	database.GetDB().Create(&[]models.Seat{
+		{ID: "empty-a1", SeatMapID: "empty-map", Section: "Stalls", Row: "A", Number: "1"},
+		{ID: "venue-a1", SeatMapID: "venue-map", Section: "Stalls", Row: "A", Number: "1"},
+	})
+	database.GetDB().Create(&models.SeatHold{ID: hold, EventID: "empty", UserID: "ada", Quantity: 1, Status: models.HoldStatusActive, ExpiresAt: start})
+	database.GetDB().Create(&models.SeatReservation{ID: "held-a1", EventID: "empty", SeatID: "empty-a1", HoldID: &hold})
+
+	tests := []struct {
+		name       string
+		id         string
+		wantStatus int
+	}{
+		{name: "without bookings", id: "empty", wantStatus: http.StatusOK},
+		{name: "with a cancelled booking", id: "booked", wantStatus: http.StatusConflict},
+		{name: "unknown event", id: "missing", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/events/"+tt.id, nil))
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
 			}
 		})
 	}
+
+	var kept int64
+	database.GetDB().Model(&models.Booking{}).Where("id = ?", "cancelled").Count(&kept)
+	if kept != 1 {
+		t.Errorf("the cancelled booking was deleted")
+	}
+
+	left := map[string]int64{}
+	for name, model := range map[string]interface{}{
+		"ticket types": &models.TicketType{}, "seat maps": &models.SeatMap{}, "seats": &models.Seat{},
+		"seat holds": &models.SeatHold{}, "seat reservations": &models.SeatReservation{},
+	} {
+		var count int64
+		database.GetDB().Model(model).Count(&count)
+		left[name] = count
+	}
+	want := map[string]int64{"ticket types": 1, "seat maps": 1, "seats": 1, "seat holds": 0, "seat reservations": 0}
+	for name, count := range want {
+		if left[name] != count {
+			t.Errorf("%d %s left, want %d", left[name], name, count)
+		}
+	}
+}
+
+func TestUpdateEventKeepsReservedTickets(t *testing.T) {
+	r := setupEventTest(t)
+	r.PUT("/api/events/:id", UpdateEventHandler)
+	start := time.Now().Add(72 * time.Hour).UTC().Truncate(time.Second)
+	seedEvent(t, "concert", start, start.Add(2*time.Hour), "Europe/Berlin")
+
+	// Tickets are booked after the handler has read the event, just before
+	// it saves its changes
+	database.GetDB().Callback().Update().Before("gorm:update").Register("test:book", func(tx *gorm.DB) {
+		if tx.Statement.Table == "events" {
+			tx.Session(&gorm.Session{NewDB: true}).Exec("UPDATE events SET reserved = 3 WHERE id = ?", "concert")
+		}
+	})
+	if w := sendJSON(r, http.MethodPut, "/api/events/concert", meetupRequest(start)); w.Code != http.StatusOK {
+		t.Fatalf("update status = %d, body %s", w.Code, w.Body.String())
+	}
+
+	var event models.Event
+	database.GetDB().First(&event, "id = ?", "concert")
+	if event.Reserved != 3 || event.Capacity != 30 {
+		t.Errorf("reserved = %d, capacity = %d, want 3 and 30", event.Reserved, event.Capacity)
+	}
 }
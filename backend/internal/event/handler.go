@@ -2,12 +2,16 @@ package event
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
+	"online-task/internal/auth"
+	"online-task/internal/booking"
 	"online-task/internal/models"
+	"online-task/internal/tag"
 	"online-task/pkg/database"
 )
 
@@ -16,20 +20,72 @@ import (
 // @Tags events
 // @Accept json
 // @Produce json
+// @Param tag query string false "Tag ID or slug; includes events tagged with any of its descendants"
+// @Param status query string false "Only events in this status; drafts are only listed for admins" Enums(draft, published, cancelled, postponed, completed)
+// @Param from query string false "Only events still running at or after this RFC 3339 time"
+// @Param to query string false "Only events starting at or before this RFC 3339 time"
+// @Param near query string false "Only events at venues near this lat,lng, nearest first" example(30.0444,31.2357)
+// @Param radius query number false "Search radius in km for near (default 25)"
 // @Success 200 {array} models.Event
 // @Router /events [get]
 func GetAllEventsHandler(c *gin.Context) {
+	db := database.GetDB()
+	query := db.Preload("Tags").Preload("Venue")
+
+	near, err := parseNear(c.Query("near"), c.Query("radius"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if near != nil {
+		query = near.query(query)
+	}
+
+	if !auth.IsAdmin(c) {
+		query = publicStatuses(query)
+	}
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	query, err = overlapping(query, c.Query("from"), c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if tagParam := c.Query("tag"); tagParam != "" {
+		filterTag, err := tag.FindTag(db, tagParam)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tag"})
+			return
+		}
+		tagIDs, err := tag.DescendantIDs(db, filterTag.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tag hierarchy"})
+			return
+		}
+		query = query.Where("events.id IN (?)", db.Table("event_tags").Select("event_id").Where("tag_id IN ?", tagIDs))
+	}
+
 	var events []models.Event
-	if err := database.GetDB().Preload("Tags").Find(&events).Error; err != nil {
+	if err := query.Find(&events).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch events"})
 		return
 	}
+	if near != nil {
+		events = near.apply(events)
+	}
 
 	c.JSON(http.StatusOK, events)
 }
 
 // @Summary Get event by ID
-// @Description Get details of a specific event
+// @Description Get details of a specific event. Drafts are only visible to admins.
 // @Tags events
 // @Accept json
 // @Produce json
@@ -40,7 +96,12 @@ func GetEventHandler(c *gin.Context) {
 	id := c.Param("id")
 	var event models.Event
 
-	if err := database.GetDB().Preload("Tags").First(&event, "id = ?", id).Error; err != nil {
+	query := database.GetDB().Preload("Tags").Preload("Venue").Preload("Media", orderedMedia).Preload("TicketTypes", orderedTicketTypes)
+	if !auth.IsAdmin(c) {
+		query = publicStatuses(query)
+	}
+
+	if err := query.First(&event, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
 			return
@@ -53,13 +114,14 @@ func GetEventHandler(c *gin.Context) {
 }
 
 // @Summary Create a new event
-// @Description Create a new event (admin only)
+// @Description Create a new event (admin only). Events start as drafts unless status is "published". With an rrule, one event is created per occurrence and the series is returned instead. Other events overlapping it at the same venue are listed in venueConflicts.
 // @Tags events
 // @Accept json
 // @Produce json
 // @Param request body models.CreateEventRequest true "Event details"
 // @Security Bearer
 // @Success 201 {object} models.Event
+// @Success 201 {object} models.EventSeries "When rrule is set"
 // @Router /events [post]
 func CreateEventHandler(c *gin.Context) {
 	var req models.CreateEventRequest
@@ -67,16 +129,54 @@ func CreateEventHandler(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if err := validateEventTimes(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateSchedule(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := applyVenue(database.GetDB(), &req); err != nil {
+		if err == errVenueNotFound {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Venue not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch venue"})
+		return
+	}
 
+	models.SortCancellationPolicy(req.CancellationPolicy)
 	event := models.Event{
-		ID:          uuid.New().String(),
-		Name:        req.Name,
-		Description: req.Description,
-		Category:    req.Category,
-		Date:        req.Date,
-		Location:    req.Location,
-		Price:       req.Price,
-		Image:       req.Image,
+		ID:           uuid.New().String(),
+		Name:         req.Name,
+		Description:  req.Description,
+		Category:     req.Category,
+		Date:         req.Date.UTC(),
+		EndDate:      req.EndDate.UTC(),
+		TimeZone:     req.TimeZone,
+		Location:     req.Location,
+		VenueID:      req.VenueID,
+		Price:        *req.Price,
+		Image:        req.Image,
+		Status:       models.EventStatusDraft,
+		PublishAt:    req.PublishAt,
+		SalesStartAt: req.SalesStartAt,
+		SalesEndAt:   req.SalesEndAt,
+		Capacity:     req.Capacity,
+
+		CancellationPolicy: req.CancellationPolicy,
+		TransfersDisabled:  req.TransfersDisabled,
+		MaxTicketsPerOrder: req.MaxTicketsPerOrder,
+		MaxTicketsPerUser:  req.MaxTicketsPerUser,
+	}
+	if req.Status != "" {
+		event.Status = req.Status
+	}
+
+	if req.RRule != "" {
+		createSeries(c, &req, event)
+		return
 	}
 
 	// Start transaction
@@ -104,11 +204,17 @@ func CreateEventHandler(c *gin.Context) {
 	}
 
 	tx.Commit()
+
+	// Overlapping events at the same venue are reported, not rejected
+	if err := findVenueConflicts(database.GetDB(), &event); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check venue conflicts"})
+		return
+	}
 	c.JSON(http.StatusCreated, event)
 }
 
 // @Summary Update an event
-// @Description Update an existing event (admin only)
+// @Description Update an existing event (admin only). For an occurrence of a series only this occurrence changes. Other events overlapping it at the same venue are listed in venueConflicts. The status is changed through the publish, unpublish, cancel and postpone endpoints.
 // @Tags events
 // @Accept json
 // @Produce json
@@ -124,6 +230,26 @@ func UpdateEventHandler(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if err := validateEventTimes(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateSchedule(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := applyVenue(database.GetDB(), &req); err != nil {
+		if err == errVenueNotFound {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Venue not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch venue"})
+		return
+	}
+	if req.RRule != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "An existing event cannot be made recurring"})
+		return
+	}
 
 	var event models.Event
 	if err := database.GetDB().First(&event, "id = ?", id).Error; err != nil {
@@ -135,23 +261,55 @@ func UpdateEventHandler(c *gin.Context) {
 		return
 	}
 
+	if err := checkTicketCurrency(database.GetDB(), event.ID, req.Price.Currency); err != nil {
+		if err == errCurrencyMismatch {
+			c.JSON(http.StatusConflict, gin.H{"error": "The currency cannot be changed while ticket types are priced in another currency"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch ticket types"})
+		return
+	}
+
 	// Update fields
 	event.Name = req.Name
 	event.Description = req.Description
 	event.Category = req.Category
-	event.Date = req.Date
+	event.Date = req.Date.UTC()
+	event.EndDate = req.EndDate.UTC()
+	event.TimeZone = req.TimeZone
+	event.Capacity = req.Capacity
 	event.Location = req.Location
-	event.Price = req.Price
+	event.VenueID = req.VenueID
+	event.Price = *req.Price
 	event.Image = req.Image
+	event.SalesStartAt = req.SalesStartAt
+	event.SalesEndAt = req.SalesEndAt
+	models.SortCancellationPolicy(req.CancellationPolicy)
+	event.CancellationPolicy = req.CancellationPolicy
+	event.TransfersDisabled = req.TransfersDisabled
+	event.MaxTicketsPerOrder = req.MaxTicketsPerOrder
+	event.MaxTicketsPerUser = req.MaxTicketsPerUser
+	if req.PublishAt != nil && event.Status != models.EventStatusDraft {
+		c.JSON(http.StatusConflict, gin.H{"error": "publishAt can only be set on draft events"})
+		return
+	}
+	event.PublishAt = req.PublishAt
 
 	// Start transaction
 	tx := database.GetDB().Begin()
 
-	if err := tx.Save(&event).Error; err != nil {
+	if err := tx.Omit("Reserved").Save(&event).Error; err != nil {
 		tx.Rollback()
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update event"})
 		return
 	}
+	if event.TransfersDisabled {
+		if err := booking.CancelPendingTransfers(tx, event.ID, time.Now()); err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel transfers"})
+			return
+		}
+	}
 
 	// Update tags if provided
 	if len(req.TagIDs) > 0 {
@@ -177,38 +335,70 @@ func UpdateEventHandler(c *gin.Context) {
 	}
 
 	tx.Commit()
+
+	// Overlapping events at the same venue are reported, not rejected
+	if err := findVenueConflicts(database.GetDB(), &event); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check venue conflicts"})
+		return
+	}
 	c.JSON(http.StatusOK, event)
 }
 
+// deleteEventSales removes what an event without bookings sold from: its
+// seat holds and the seats they keep, its own seat map (its venue's stays)
+// and its ticket types
+func deleteEventSales(tx *gorm.DB, eventID string) error {
+	if err := tx.Where("event_id = ?", eventID).Delete(&models.SeatReservation{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("event_id = ?", eventID).Delete(&models.SeatHold{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("seat_map_id IN (?)", tx.Model(&models.SeatMap{}).Select("id").Where("event_id = ?", eventID)).
+		Delete(&models.Seat{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("event_id = ?", eventID).Delete(&models.SeatMap{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("event_id = ?", eventID).Delete(&models.TicketType{}).Error
+}
+
 // @Summary Delete an event
-// @Description Delete an existing event (admin only)
+// @Description Delete an existing event with its ticket types, seat holds and own seat map (admin only). Events with any bookings, even cancelled ones, must be cancelled instead.
 // @Tags events
 // @Accept json
 // @Produce json
 // @Param id path string true "Event ID"
 // @Security Bearer
+// @Failure 409 {object} models.ErrorResponse
 // @Router /events/{id} [delete]
 func DeleteEventHandler(c *gin.Context) {
 	id := c.Param("id")
 
-	// Start transaction
-	tx := database.GetDB().Begin()
-
-	// Delete associated bookings first
-	if err := tx.Where("event_id = ?", id).Delete(&models.Booking{}).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete associated bookings"})
+	// Bookings, even cancelled ones, keep their refund and cancellation
+	// history, so events that have any are cancelled rather than deleted
+	var bookings int64
+	if err := database.GetDB().Model(&models.Booking{}).Where("event_id = ?", id).Count(&bookings).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check bookings"})
+		return
+	}
+	if bookings > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Event has bookings, cancel it instead"})
 		return
 	}
 
+	// Start transaction
+	tx := database.GetDB().Begin()
+
 	// Clear tag associations
 	var event models.Event
 	if err := tx.First(&event, "id = ?", id).Error; err != nil {
+		tx.Rollback()
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
 			return
 		}
-		tx.Rollback()
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch event"})
 		return
 	}
@@ -219,6 +409,19 @@ func DeleteEventHandler(c *gin.Context) {
 		return
 	}
 
+	// Remove gallery images and attachments
+	if err := tx.Where("event_id = ?", id).Delete(&models.EventMedia{}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete event media"})
+		return
+	}
+
+	if err := deleteEventSales(tx, id); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete ticket types and seats"})
+		return
+	}
+
 	// Delete the event
 	if err := tx.Delete(&event).Error; err != nil {
 		tx.Rollback()
@@ -228,4 +431,4 @@ func DeleteEventHandler(c *gin.Context) {
 
 	tx.Commit()
 	c.JSON(http.StatusOK, gin.H{"message": "Event deleted successfully"})
-} 
\ No newline at end of file
+}
@@ -0,0 +1,226 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"online-task/internal/booking"
+	"online-task/internal/models"
+	"online-task/internal/notification"
+	"online-task/pkg/database"
+)
+
+var errStatusChanged = errors.New("event status changed concurrently")
+
+// transitions lists the states each event status may move to
+var transitions = map[string][]string{
+	models.EventStatusDraft:     {models.EventStatusPublished, models.EventStatusCancelled},
+	models.EventStatusPublished: {models.EventStatusDraft, models.EventStatusCancelled, models.EventStatusPostponed, models.EventStatusCompleted},
+	models.EventStatusPostponed: {models.EventStatusPublished, models.EventStatusCancelled},
+	models.EventStatusCancelled: {},
+	models.EventStatusCompleted: {},
+}
+
+// CanTransition reports whether an event may move from one status to another
+func CanTransition(from, to string) bool {
+	for _, allowed := range transitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// publicStatuses filters out events that are not visible to the public
+func publicStatuses(db *gorm.DB) *gorm.DB {
+	return db.Where("status <> ?", models.EventStatusDraft)
+}
+
+// changeStatus moves the event in the path to the given status and runs
+// the optional side effect in the same transaction
+func changeStatus(c *gin.Context, to string, sideEffect func(tx *gorm.DB, event *models.Event, note string) error) {
+	id := c.Param("id")
+	var req models.EventStatusRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	var event models.Event
+	if err := database.GetDB().First(&event, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch event"})
+		return
+	}
+
+	if !CanTransition(event.Status, to) {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Cannot change event status from %s to %s", event.Status, to)})
+		return
+	}
+
+	err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		// Guard against a concurrent status change since the event was read
+		result := tx.Model(&models.Event{}).
+			Where("id = ? AND status = ?", event.ID, event.Status).
+			Updates(map[string]interface{}{"status": to, "status_note": req.Note})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errStatusChanged
+		}
+		if sideEffect != nil {
+			return sideEffect(tx, &event, req.Note)
+		}
+		return nil
+	})
+	if err == errStatusChanged {
+		c.JSON(http.StatusConflict, gin.H{"error": "Event status was changed by another request"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update event status"})
+		return
+	}
+
+	if err := database.GetDB().Preload("Tags").First(&event, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch event"})
+		return
+	}
+
+	c.JSON(http.StatusOK, event)
+}
+
+// notifyAttendees queues a notification for every active booking of the event
+func notifyAttendees(tx *gorm.DB, event *models.Event, notificationType, message string) error {
+	var bookings []models.Booking
+	if err := tx.Where("event_id = ? AND status <> ?", event.ID, models.BookingStatusCancelled).Find(&bookings).Error; err != nil {
+		return err
+	}
+
+	notifications := make([]models.Notification, 0, len(bookings))
+	for _, booking := range bookings {
+		notifications = append(notifications, models.Notification{
+			Type:   notificationType,
+			UserID: booking.UserID,
+			// Guests, who have no account, are reached by email
+			Email:     booking.GuestEmail,
+			EventID:   event.ID,
+			BookingID: booking.ID,
+			Message:   message,
+		})
+	}
+	return notification.Emit(tx, notifications...)
+}
+
+// @Summary Publish an event
+// @Description Make a draft or postponed event public and open for booking (admin only)
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Security Bearer
+// @Success 200 {object} models.Event
+// @Failure 409 {object} models.ErrorResponse
+// @Router /events/{id}/publish [post]
+func PublishEventHandler(c *gin.Context) {
+	changeStatus(c, models.EventStatusPublished, nil)
+}
+
+// @Summary Unpublish an event
+// @Description Move a published event back to draft, hiding it from public listings (admin only)
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Security Bearer
+// @Success 200 {object} models.Event
+// @Failure 409 {object} models.ErrorResponse
+// @Router /events/{id}/unpublish [post]
+func UnpublishEventHandler(c *gin.Context) {
+	changeStatus(c, models.EventStatusDraft, nil)
+}
+
+// @Summary Cancel an event
+// @Description Call off an event (admin only). Its bookings are kept but marked cancelled, paid bookings are refunded in full and attendees are notified.
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param request body models.EventStatusRequest false "Reason shown to attendees"
+// @Security Bearer
+// @Success 200 {object} models.Event
+// @Failure 409 {object} models.ErrorResponse
+// @Router /events/{id}/cancel [post]
+func CancelEventHandler(c *gin.Context) {
+	changeStatus(c, models.EventStatusCancelled, func(tx *gorm.DB, event *models.Event, note string) error {
+		message := fmt.Sprintf("%s has been cancelled.", event.Name)
+		if note != "" {
+			message += " " + note
+		}
+		if err := notifyAttendees(tx, event, models.NotificationEventCancelled, message); err != nil {
+			return err
+		}
+
+		// Paid bookings are refunded in full; the payment reaper issues the refunds
+		var paid []models.Booking
+		if err := tx.Where("event_id = ? AND status = ? AND paid_at IS NOT NULL", event.ID, models.BookingStatusConfirmed).
+			Find(&paid).Error; err != nil {
+			return err
+		}
+		for i := range paid {
+			if _, err := booking.RecordRefund(tx, &paid[i], 100, models.RefundReasonEventCancelled); err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&models.Booking{}).
+			Where("event_id = ? AND status <> ?", event.ID, models.BookingStatusCancelled).
+			Updates(map[string]interface{}{"status": models.BookingStatusCancelled, "cancelled_at": time.Now()}).Error
+	})
+}
+
+// @Summary Postpone an event
+// @Description Mark a published event as postponed until a new date is set and it is published again (admin only). Attendees keep their bookings and are notified.
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param request body models.EventStatusRequest false "Reason shown to attendees"
+// @Security Bearer
+// @Success 200 {object} models.Event
+// @Failure 409 {object} models.ErrorResponse
+// @Router /events/{id}/postpone [post]
+func PostponeEventHandler(c *gin.Context) {
+	changeStatus(c, models.EventStatusPostponed, func(tx *gorm.DB, event *models.Event, note string) error {
+		message := fmt.Sprintf("%s has been postponed.", event.Name)
+		if note != "" {
+			message += " " + note
+		}
+		return notifyAttendees(tx, event, models.NotificationEventPostponed, message)
+	})
+}
+
+// @Summary Complete an event
+// @Description Mark a published event as having taken place (admin only)
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Security Bearer
+// @Success 200 {object} models.Event
+// @Failure 409 {object} models.ErrorResponse
+// @Router /events/{id}/complete [post]
+func CompleteEventHandler(c *gin.Context) {
+	changeStatus(c, models.EventStatusCompleted, nil)
+}
@@ -0,0 +1,212 @@
+package event
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+	"online-task/pkg/money"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{models.EventStatusDraft, models.EventStatusPublished, true},
+		{models.EventStatusDraft, models.EventStatusCancelled, true},
+		{models.EventStatusDraft, models.EventStatusPostponed, false},
+		{models.EventStatusDraft, models.EventStatusCompleted, false},
+		{models.EventStatusPublished, models.EventStatusDraft, true},
+		{models.EventStatusPublished, models.EventStatusPostponed, true},
+		{models.EventStatusPublished, models.EventStatusCompleted, true},
+		{models.EventStatusPostponed, models.EventStatusPublished, true},
+		{models.EventStatusPostponed, models.EventStatusCompleted, false},
+		{models.EventStatusCancelled, models.EventStatusPublished, false},
+		{models.EventStatusCompleted, models.EventStatusCancelled, false},
+		{"unknown", models.EventStatusPublished, false},
+	}
+	for _, tt := range tests {
+		if got := CanTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+// lifecycleRouter registers the status routes on the event test router
+func lifecycleRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	r := setupEventTest(t)
+	if err := database.GetDB().AutoMigrate(&models.Booking{}, &models.Refund{}, &models.Notification{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	r.POST("/api/events/:id/publish", PublishEventHandler)
+	r.POST("/api/events/:id/unpublish", UnpublishEventHandler)
+	r.POST("/api/events/:id/cancel", CancelEventHandler)
+	r.POST("/api/events/:id/postpone", PostponeEventHandler)
+	r.POST("/api/events/:id/complete", CompleteEventHandler)
+	return r
+}
+
+func seedEventWithStatus(t *testing.T, id, status string) {
+	t.Helper()
+	start := time.Now().Add(72 * time.Hour)
+	seedEvent(t, id, start, start.Add(2*time.Hour), "UTC")
+	database.GetDB().Model(&models.Event{}).Where("id = ?", id).Update("status", status)
+}
+
+func TestChangeEventStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		from       string
+		action     string
+		wantStatus int
+		want       string
+	}{
+		{name: "publish a draft", from: models.EventStatusDraft, action: "publish", wantStatus: http.StatusOK, want: models.EventStatusPublished},
+		{name: "unpublish", from: models.EventStatusPublished, action: "unpublish", wantStatus: http.StatusOK, want: models.EventStatusDraft},
+		{name: "postpone", from: models.EventStatusPublished, action: "postpone", wantStatus: http.StatusOK, want: models.EventStatusPostponed},
+		{name: "publish a postponed event", from: models.EventStatusPostponed, action: "publish", wantStatus: http.StatusOK, want: models.EventStatusPublished},
+		{name: "complete", from: models.EventStatusPublished, action: "complete", wantStatus: http.StatusOK, want: models.EventStatusCompleted},
+		{name: "cancel a draft", from: models.EventStatusDraft, action: "cancel", wantStatus: http.StatusOK, want: models.EventStatusCancelled},
+		{name: "postpone a draft", from: models.EventStatusDraft, action: "postpone", wantStatus: http.StatusConflict, want: models.EventStatusDraft},
+		{name: "complete a postponed event", from: models.EventStatusPostponed, action: "complete", wantStatus: http.StatusConflict, want: models.EventStatusPostponed},
+		{name: "publish a cancelled event", from: models.EventStatusCancelled, action: "publish", wantStatus: http.StatusConflict, want: models.EventStatusCancelled},
+		{name: "cancel a completed event", from: models.EventStatusCompleted, action: "cancel", wantStatus: http.StatusConflict, want: models.EventStatusCompleted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := lifecycleRouter(t)
+			seedEventWithStatus(t, "concert", tt.from)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/events/concert/"+tt.action, nil))
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			var event models.Event
+			database.GetDB().First(&event, "id = ?", "concert")
+			if event.Status != tt.want {
+				t.Errorf("event status = %s, want %s", event.Status, tt.want)
+			}
+		})
+	}
+
+	r := lifecycleRouter(t)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/events/missing/publish", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown event: status = %d, want 404", w.Code)
+	}
+}
+
+func TestCancelEventWithBookings(t *testing.T) {
+	r := lifecycleRouter(t)
+	seedEventWithStatus(t, "concert", models.EventStatusPublished)
+	seedEventWithStatus(t, "opera", models.EventStatusPublished)
+
+	paidAt := time.Now().Add(-time.Hour)
+	intent := "pi_paid"
+	database.GetDB().Create(&[]models.Booking{
+		{ID: "paid", UserID: "ada", EventID: "concert", Quantity: 2, Status: models.BookingStatusConfirmed,
+			Total: money.New(4000, "USD"), PaidAt: &paidAt, PaymentIntentID: &intent},
+		{ID: "unpaid", UserID: "bob", EventID: "concert", Quantity: 1, Status: models.BookingStatusPendingPayment, Total: money.New(2000, "USD")},
+		{ID: "guest", GuestName: "Grace", GuestEmail: "grace@example.com", EventID: "concert", Quantity: 1, Status: models.BookingStatusPendingPayment},
+		{ID: "already-cancelled", UserID: "eve", EventID: "concert", Quantity: 1, Status: models.BookingStatusCancelled},
+		{ID: "other-event", UserID: "ada", EventID: "opera", Quantity: 1, Status: models.BookingStatusConfirmed},
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/events/concert/cancel", strings.NewReader(`{"note":"The band is ill."}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("cancel status = %d, body %s", w.Code, w.Body.String())
+	}
+	var event models.Event
+	json.Unmarshal(w.Body.Bytes(), &event)
+	if event.Status != models.EventStatusCancelled || event.StatusNote != "The band is ill." {
+		t.Errorf("event = %s %q, want cancelled with the note", event.Status, event.StatusNote)
+	}
+
+	statuses := map[string]string{}
+	var bookings []models.Booking
+	database.GetDB().Find(&bookings)
+	for _, booking := range bookings {
+		statuses[booking.ID] = booking.Status
+	}
+	for id, want := range map[string]string{
+		"paid":              models.BookingStatusCancelled,
+		"unpaid":            models.BookingStatusCancelled,
+		"guest":             models.BookingStatusCancelled,
+		"already-cancelled": models.BookingStatusCancelled,
+		"other-event":       models.BookingStatusConfirmed,
+	} {
+		if statuses[id] != want {
+			t.Errorf("booking %s = %s, want %s", id, statuses[id], want)
+		}
+	}
+
+	// Only the paid booking is refunded, in full
+	var refunds []models.Refund
+	database.GetDB().Find(&refunds)
+	if len(refunds) != 1 || refunds[0].BookingID != "paid" || refunds[0].Amount != money.New(4000, "USD") ||
+		refunds[0].Reason != models.RefundReasonEventCancelled || refunds[0].Status != models.RefundStatusPending {
+		t.Errorf("refunds = %+v, want a pending full refund of the paid booking", refunds)
+	}
+
+	// Holders of the active bookings are told why, guests by email
+	var sent []models.Notification
+	database.GetDB().Where("type = ?", models.NotificationEventCancelled).Order("booking_id").Find(&sent)
+	if len(sent) != 3 {
+		t.Fatalf("sent %d notifications, want 3: %+v", len(sent), sent)
+	}
+	for _, n := range sent {
+		if !strings.Contains(n.Message, "The band is ill.") {
+			t.Errorf("message %q does not give the reason", n.Message)
+		}
+	}
+	if sent[0].BookingID != "guest" || sent[0].Email != "grace@example.com" {
+		t.Errorf("guest notification = %+v, want it sent to grace@example.com", sent[0])
+	}
+
+	// Cancelling twice is refused and refunds nothing more
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/events/concert/cancel", nil))
+	var count int64
+	database.GetDB().Model(&models.Refund{}).Count(&count)
+	if w.Code != http.StatusConflict || count != 1 {
+		t.Errorf("second cancel: status = %d with %d refunds, want 409 and 1", w.Code, count)
+	}
+}
+
+func TestPostponeEventNotifiesAttendees(t *testing.T) {
+	r := lifecycleRouter(t)
+	seedEventWithStatus(t, "concert", models.EventStatusPublished)
+	database.GetDB().Create(&[]models.Booking{
+		{ID: "active", UserID: "ada", EventID: "concert", Quantity: 1, Status: models.BookingStatusConfirmed},
+		{ID: "cancelled", UserID: "bob", EventID: "concert", Quantity: 1, Status: models.BookingStatusCancelled},
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/events/concert/postpone", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("postpone status = %d, body %s", w.Code, w.Body.String())
+	}
+
+	var sent []models.Notification
+	database.GetDB().Where("type = ?", models.NotificationEventPostponed).Find(&sent)
+	if len(sent) != 1 || sent[0].BookingID != "active" || sent[0].UserID != "ada" {
+		t.Errorf("notifications = %+v, want one for the active booking", sent)
+	}
+	var booking models.Booking
+	database.GetDB().First(&booking, "id = ?", "active")
+	if booking.Status != models.BookingStatusConfirmed {
+		t.Errorf("booking status = %s, want it kept", booking.Status)
+	}
+}
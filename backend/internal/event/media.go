@@ -0,0 +1,217 @@
+package event
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+)
+
+// orderedMedia preloads event media in gallery order
+func orderedMedia(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC")
+}
+
+// findEventMedia loads a media item and checks it belongs to the event in the path
+func findEventMedia(c *gin.Context) (*models.EventMedia, bool) {
+	var media models.EventMedia
+	if err := database.GetDB().First(&media, "id = ? AND event_id = ?", c.Param("mediaId"), c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Media not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch media"})
+		return nil, false
+	}
+	return &media, true
+}
+
+// @Summary Attach media to an event
+// @Description Add a gallery image or downloadable attachment to the end of an event's media list (admin only)
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param request body models.AddEventMediaRequest true "Media details"
+// @Security Bearer
+// @Success 201 {object} models.EventMedia
+// @Router /events/{id}/media [post]
+func AddEventMediaHandler(c *gin.Context) {
+	id := c.Param("id")
+	var req models.AddEventMediaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var event models.Event
+	if err := database.GetDB().First(&event, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch event"})
+		return
+	}
+
+	var count int64
+	if err := database.GetDB().Model(&models.EventMedia{}).Where("event_id = ?", id).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch media"})
+		return
+	}
+
+	media := models.EventMedia{
+		ID:       uuid.New().String(),
+		EventID:  id,
+		Type:     req.Type,
+		URL:      req.URL,
+		Caption:  req.Caption,
+		AltText:  req.AltText,
+		Position: int(count),
+	}
+
+	if err := database.GetDB().Create(&media).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to attach media"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, media)
+}
+
+// @Summary Update event media
+// @Description Update the caption and alt text of an event's media item (admin only)
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param mediaId path string true "Media ID"
+// @Param request body models.UpdateEventMediaRequest true "Media details"
+// @Security Bearer
+// @Success 200 {object} models.EventMedia
+// @Router /events/{id}/media/{mediaId} [put]
+func UpdateEventMediaHandler(c *gin.Context) {
+	var req models.UpdateEventMediaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	media, ok := findEventMedia(c)
+	if !ok {
+		return
+	}
+
+	media.Caption = req.Caption
+	media.AltText = req.AltText
+
+	if err := database.GetDB().Save(media).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update media"})
+		return
+	}
+
+	c.JSON(http.StatusOK, media)
+}
+
+// @Summary Reorder event media
+// @Description Set the display order of an event's media. The request must list every media ID of the event exactly once (admin only)
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param request body models.ReorderEventMediaRequest true "Media IDs in display order"
+// @Security Bearer
+// @Success 200 {array} models.EventMedia
+// @Router /events/{id}/media [put]
+func ReorderEventMediaHandler(c *gin.Context) {
+	id := c.Param("id")
+	var req models.ReorderEventMediaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var media []models.EventMedia
+	if err := database.GetDB().Where("event_id = ?", id).Find(&media).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch media"})
+		return
+	}
+
+	existing := make(map[string]bool, len(media))
+	for _, item := range media {
+		existing[item.ID] = true
+	}
+	seen := make(map[string]bool, len(req.MediaIDs))
+	for _, mediaID := range req.MediaIDs {
+		if !existing[mediaID] || seen[mediaID] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Media IDs must list every media item of the event exactly once"})
+			return
+		}
+		seen[mediaID] = true
+	}
+	if len(seen) != len(existing) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Media IDs must list every media item of the event exactly once"})
+		return
+	}
+
+	// Start transaction
+	tx := database.GetDB().Begin()
+
+	for position, mediaID := range req.MediaIDs {
+		if err := tx.Model(&models.EventMedia{}).Where("id = ?", mediaID).Update("position", position).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reorder media"})
+			return
+		}
+	}
+
+	tx.Commit()
+
+	if err := orderedMedia(database.GetDB()).Where("event_id = ?", id).Find(&media).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch media"})
+		return
+	}
+
+	c.JSON(http.StatusOK, media)
+}
+
+// @Summary Remove media from an event
+// @Description Remove a gallery image or attachment from an event (admin only)
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param mediaId path string true "Media ID"
+// @Security Bearer
+// @Success 200 {object} models.SuccessResponse
+// @Router /events/{id}/media/{mediaId} [delete]
+func DeleteEventMediaHandler(c *gin.Context) {
+	media, ok := findEventMedia(c)
+	if !ok {
+		return
+	}
+
+	// Start transaction
+	tx := database.GetDB().Begin()
+
+	if err := tx.Delete(media).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove media"})
+		return
+	}
+
+	// Close the gap left in the ordering
+	if err := tx.Model(&models.EventMedia{}).
+		Where("event_id = ? AND position > ?", media.EventID, media.Position).
+		Update("position", gorm.Expr("position - 1")).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reorder media"})
+		return
+	}
+
+	tx.Commit()
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: "Media removed successfully"})
+}
@@ -0,0 +1,203 @@
+package event
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"online-task/internal/auth"
+	"online-task/internal/models"
+	"online-task/pkg/database"
+	"online-task/pkg/jwt"
+)
+
+// setupMediaTest registers the media routes behind the admin check, as the
+// server does, and returns tokens for an admin and a regular user
+func setupMediaTest(t *testing.T) (r *gin.Engine, adminToken, userToken string) {
+	t.Helper()
+	t.Setenv("JWT_SECRET", "media-test-secret")
+	r = setupEventTest(t)
+	admin := r.Group("/api/events/:id/media", auth.AuthMiddleware(), auth.AdminMiddleware())
+	admin.POST("", AddEventMediaHandler)
+	admin.PUT("", ReorderEventMediaHandler)
+	admin.PUT("/:mediaId", UpdateEventMediaHandler)
+	admin.DELETE("/:mediaId", DeleteEventMediaHandler)
+
+	start := time.Now().Add(72 * time.Hour)
+	seedEvent(t, "concert", start, start.Add(2*time.Hour), "UTC")
+
+	adminToken, _ = jwt.GenerateToken("admin", models.RoleAdmin)
+	userToken, _ = jwt.GenerateToken("ada", "user")
+	return r, adminToken, userToken
+}
+
+func mediaRequest(r *gin.Engine, method, path, token string, body interface{}) *httptest.ResponseRecorder {
+	data, _ := json.Marshal(body)
+	req := httptest.NewRequest(method, path, bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// attachMedia adds media to the concert and returns their IDs in order
+func attachMedia(t *testing.T, r *gin.Engine, token string, urls ...string) []string {
+	t.Helper()
+	ids := make([]string, 0, len(urls))
+	for _, url := range urls {
+		w := mediaRequest(r, http.MethodPost, "/api/events/concert/media", token, models.AddEventMediaRequest{Type: "image", URL: url})
+		if w.Code != http.StatusCreated {
+			t.Fatalf("attach status = %d, body %s", w.Code, w.Body.String())
+		}
+		var media models.EventMedia
+		json.Unmarshal(w.Body.Bytes(), &media)
+		ids = append(ids, media.ID)
+	}
+	return ids
+}
+
+// galleryOrder lists the concert's media IDs by position
+func galleryOrder(t *testing.T) []string {
+	t.Helper()
+	var media []models.EventMedia
+	orderedMedia(database.GetDB()).Where("event_id = ?", "concert").Find(&media)
+	ids := make([]string, 0, len(media))
+	for i, item := range media {
+		if item.Position != i {
+			t.Errorf("media %s at position %d, want %d", item.ID, item.Position, i)
+		}
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
+
+func TestAddEventMedia(t *testing.T) {
+	r, adminToken, _ := setupMediaTest(t)
+
+	tests := []struct {
+		name       string
+		event      string
+		req        models.AddEventMediaRequest
+		wantStatus int
+	}{
+		{name: "uploaded image", event: "concert", req: models.AddEventMediaRequest{Type: "image", URL: "/uploads/images/stage.jpg", Caption: "Main stage", AltText: "Crowd"}, wantStatus: http.StatusCreated},
+		{name: "uploaded attachment", event: "concert", req: models.AddEventMediaRequest{Type: "attachment", URL: "/uploads/documents/menu.pdf"}, wantStatus: http.StatusCreated},
+		{name: "unknown type", event: "concert", req: models.AddEventMediaRequest{Type: "video", URL: "/uploads/videos/intro.mp4"}, wantStatus: http.StatusBadRequest},
+		{name: "without a URL", event: "concert", req: models.AddEventMediaRequest{Type: "image"}, wantStatus: http.StatusBadRequest},
+		{name: "unknown event", event: "missing", req: models.AddEventMediaRequest{Type: "image", URL: "/uploads/images/stage.jpg"}, wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := mediaRequest(r, http.MethodPost, "/api/events/"+tt.event+"/media", adminToken, tt.req)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+		})
+	}
+
+	// Media are appended to the gallery and returned with the event
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/events/concert", nil))
+	var event models.Event
+	json.Unmarshal(w.Body.Bytes(), &event)
+	if len(event.Media) != 2 || event.Media[0].URL != "/uploads/images/stage.jpg" || event.Media[0].Caption != "Main stage" ||
+		event.Media[1].Type != "attachment" || event.Media[1].Position != 1 {
+		t.Errorf("event media = %+v, want the image then the attachment", event.Media)
+	}
+}
+
+func TestReorderEventMedia(t *testing.T) {
+	r, adminToken, _ := setupMediaTest(t)
+	ids := attachMedia(t, r, adminToken, "/uploads/images/a.jpg", "/uploads/images/b.jpg", "/uploads/images/c.jpg")
+
+	tests := []struct {
+		name       string
+		mediaIDs   []string
+		wantStatus int
+	}{
+		{name: "missing an ID", mediaIDs: []string{ids[2], ids[0]}, wantStatus: http.StatusBadRequest},
+		{name: "unknown ID", mediaIDs: []string{ids[2], ids[0], "other"}, wantStatus: http.StatusBadRequest},
+		{name: "repeated ID", mediaIDs: []string{ids[2], ids[0], ids[0], ids[1]}, wantStatus: http.StatusBadRequest},
+		{name: "every ID once", mediaIDs: []string{ids[2], ids[0], ids[1]}, wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := mediaRequest(r, http.MethodPut, "/api/events/concert/media", adminToken, models.ReorderEventMediaRequest{MediaIDs: tt.mediaIDs})
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+		})
+	}
+
+	order := galleryOrder(t)
+	if len(order) != 3 || order[0] != ids[2] || order[1] != ids[0] || order[2] != ids[1] {
+		t.Errorf("order = %v, want %v", order, []string{ids[2], ids[0], ids[1]})
+	}
+}
+
+func TestUpdateAndDeleteEventMedia(t *testing.T) {
+	r, adminToken, _ := setupMediaTest(t)
+	ids := attachMedia(t, r, adminToken, "/uploads/images/a.jpg", "/uploads/images/b.jpg", "/uploads/images/c.jpg")
+
+	w := mediaRequest(r, http.MethodPut, "/api/events/concert/media/"+ids[1], adminToken, models.UpdateEventMediaRequest{Caption: "Backstage", AltText: "Band tuning up"})
+	var updated models.EventMedia
+	json.Unmarshal(w.Body.Bytes(), &updated)
+	if w.Code != http.StatusOK || updated.Caption != "Backstage" || updated.AltText != "Band tuning up" || updated.URL != "/uploads/images/b.jpg" {
+		t.Errorf("update: got %d %+v, want the caption and alt text changed", w.Code, updated)
+	}
+
+	// Removing the middle item closes the gap in the ordering
+	if w := mediaRequest(r, http.MethodDelete, "/api/events/concert/media/"+ids[1], adminToken, nil); w.Code != http.StatusOK {
+		t.Fatalf("delete status = %d, body %s", w.Code, w.Body.String())
+	}
+	if order := galleryOrder(t); len(order) != 2 || order[0] != ids[0] || order[1] != ids[2] {
+		t.Errorf("order = %v, want %v", order, []string{ids[0], ids[2]})
+	}
+
+	// Media are only found through their own event
+	seedEvent(t, "opera", time.Now().Add(96*time.Hour), time.Now().Add(98*time.Hour), "UTC")
+	if w := mediaRequest(r, http.MethodDelete, "/api/events/opera/media/"+ids[0], adminToken, nil); w.Code != http.StatusNotFound {
+		t.Errorf("delete through another event: status = %d, want 404", w.Code)
+	}
+	if w := mediaRequest(r, http.MethodPut, "/api/events/concert/media/"+ids[1], adminToken, models.UpdateEventMediaRequest{}); w.Code != http.StatusNotFound {
+		t.Errorf("update of removed media: status = %d, want 404", w.Code)
+	}
+}
+
+func TestEventMediaRoutesAreAdminOnly(t *testing.T) {
+	r, adminToken, userToken := setupMediaTest(t)
+	ids := attachMedia(t, r, adminToken, "/uploads/images/a.jpg")
+
+	requests := []struct {
+		method string
+		path   string
+		body   interface{}
+	}{
+		{http.MethodPost, "/api/events/concert/media", models.AddEventMediaRequest{Type: "image", URL: "/uploads/images/b.jpg"}},
+		{http.MethodPut, "/api/events/concert/media", models.ReorderEventMediaRequest{MediaIDs: ids}},
+		{http.MethodPut, "/api/events/concert/media/" + ids[0], models.UpdateEventMediaRequest{Caption: "Mine"}},
+		{http.MethodDelete, "/api/events/concert/media/" + ids[0], nil},
+	}
+	for _, req := range requests {
+		if w := mediaRequest(r, req.method, req.path, "", req.body); w.Code != http.StatusUnauthorized {
+			t.Errorf("%s %s without a token: status = %d, want 401", req.method, req.path, w.Code)
+		}
+		if w := mediaRequest(r, req.method, req.path, userToken, req.body); w.Code != http.StatusForbidden {
+			t.Errorf("%s %s as a user: status = %d, want 403", req.method, req.path, w.Code)
+		}
+	}
+
+	var media models.EventMedia
+	database.GetDB().First(&media, "id = ?", ids[0])
+	if media.Caption != "" || len(galleryOrder(t)) != 1 {
+		t.Errorf("media changed by requests that were refused: %+v", media)
+	}
+}
@@ -0,0 +1,53 @@
+package event
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+)
+
+// validateSchedule checks that the publishing and sales times of a request
+// are consistent with each other and with the event date
+func validateSchedule(req *models.CreateEventRequest) error {
+	if req.SalesStartAt != nil && req.SalesEndAt != nil && !req.SalesEndAt.After(*req.SalesStartAt) {
+		return errors.New("salesEndAt must be after salesStartAt")
+	}
+	if req.SalesStartAt != nil && req.SalesStartAt.After(req.Date) {
+		return errors.New("salesStartAt must not be after the event date")
+	}
+	if req.PublishAt != nil && req.Status == models.EventStatusPublished {
+		return errors.New("publishAt can only be set on draft events")
+	}
+	return nil
+}
+
+// publishDueEvents publishes draft events whose scheduled publish time has
+// passed and returns how many were published
+func publishDueEvents() (int64, error) {
+	result := database.GetDB().Model(&models.Event{}).
+		Where("status = ? AND publish_at IS NOT NULL AND publish_at <= ?", models.EventStatusDraft, time.Now()).
+		Updates(map[string]interface{}{"status": models.EventStatusPublished, "publish_at": nil})
+	return result.RowsAffected, result.Error
+}
+
+// StartScheduler periodically publishes events that are due
+func StartScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			published, err := publishDueEvents()
+			if err != nil {
+				log.Printf("Failed to publish scheduled events: %v", err)
+				continue
+			}
+			if published > 0 {
+				log.Printf("Published %d scheduled events", published)
+			}
+		}
+	}()
+}
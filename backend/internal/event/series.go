@@ -0,0 +1,424 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/teambition/rrule-go"
+	"gorm.io/gorm"
+
+	"online-task/internal/auth"
+	"online-task/internal/booking"
+	"online-task/internal/models"
+	"online-task/pkg/database"
+)
+
+// maxOccurrences caps how many events a single recurrence rule may generate
+const maxOccurrences = 366
+
+var errNotInSeries = errors.New("event is not part of a series")
+
+// expandOccurrences returns the UTC start times generated by rule from start.
+// The rule is evaluated in loc so occurrences keep their local time across
+// daylight saving changes. Start times listed in exdates are skipped.
+func expandOccurrences(rule string, exdates []time.Time, start time.Time, loc *time.Location) ([]time.Time, error) {
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	opt, err := rrule.StrToROptionInLocation(rule, loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rrule: %v", err)
+	}
+	opt.Dtstart = start.In(loc)
+	r, err := rrule.NewRRule(*opt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rrule: %v", err)
+	}
+
+	set := rrule.Set{}
+	set.RRule(r)
+	for _, exdate := range exdates {
+		set.ExDate(exdate.In(loc))
+	}
+
+	var starts []time.Time
+	next := set.Iterator()
+	for occurrence, ok := next(); ok; occurrence, ok = next() {
+		if len(starts) == maxOccurrences {
+			return nil, fmt.Errorf("rrule generates more than %d occurrences; limit it with COUNT or UNTIL", maxOccurrences)
+		}
+		starts = append(starts, occurrence.UTC())
+	}
+	if len(starts) == 0 {
+		return nil, errors.New("rrule generates no occurrences")
+	}
+	return starts, nil
+}
+
+// shiftTime moves an optional time by d
+func shiftTime(t *time.Time, d time.Duration) *time.Time {
+	if t == nil {
+		return nil
+	}
+	shifted := t.Add(d)
+	return &shifted
+}
+
+// createSeries creates a series and one event per occurrence of the
+// request's rrule, each a copy of template moved to its own start time
+func createSeries(c *gin.Context, req *models.CreateEventRequest, template models.Event) {
+	loc, _ := time.LoadLocation(req.TimeZone)
+	starts, err := expandOccurrences(req.RRule, req.ExDates, template.Date, loc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	series := models.EventSeries{
+		ID:       uuid.New().String(),
+		RRule:    strings.TrimPrefix(strings.TrimSpace(req.RRule), "RRULE:"),
+		ExDates:  req.ExDates,
+		TimeZone: req.TimeZone,
+	}
+
+	// Start transaction
+	tx := database.GetDB().Begin()
+
+	if err := tx.Create(&series).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create series"})
+		return
+	}
+
+	var tags []models.Tag
+	if len(req.TagIDs) > 0 {
+		if err := tx.Find(&tags, "id IN ?", req.TagIDs).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tags"})
+			return
+		}
+	}
+
+	duration := template.EndDate.Sub(template.Date)
+	for _, start := range starts {
+		offset := start.Sub(template.Date)
+		occurrence := template
+		occurrence.ID = uuid.New().String()
+		occurrence.SeriesID = &series.ID
+		occurrence.Date = start
+		occurrence.EndDate = start.Add(duration)
+		occurrence.SalesStartAt = shiftTime(template.SalesStartAt, offset)
+		occurrence.SalesEndAt = shiftTime(template.SalesEndAt, offset)
+
+		if err := tx.Create(&occurrence).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create event"})
+			return
+		}
+		if len(tags) > 0 {
+			if err := tx.Model(&occurrence).Association("Tags").Append(tags); err != nil {
+				tx.Rollback()
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add tags"})
+				return
+			}
+		}
+		series.Events = append(series.Events, occurrence)
+	}
+
+	tx.Commit()
+
+	for i := range series.Events {
+		if err := findVenueConflicts(database.GetDB(), &series.Events[i]); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check venue conflicts"})
+			return
+		}
+	}
+	c.JSON(http.StatusCreated, series)
+}
+
+// civilDays returns the number of calendar days from a to b, ignoring the
+// time of day
+func civilDays(a, b time.Time) int {
+	dayA := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	dayB := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(dayB.Sub(dayA).Hours() / 24)
+}
+
+// applyToOccurrences applies req to every occurrence. anchor is the
+// occurrence the request's times were chosen for: the others move by the
+// same number of days and take the same local start time and duration.
+// Cancelled and completed occurrences are left untouched.
+func applyToOccurrences(tx *gorm.DB, anchor models.Event, occurrences []models.Event, req *models.CreateEventRequest) error {
+	oldLoc, err := time.LoadLocation(anchor.TimeZone)
+	if err != nil {
+		oldLoc = time.UTC
+	}
+	loc, _ := time.LoadLocation(req.TimeZone)
+
+	newStart := req.Date.In(loc)
+	dayShift := civilDays(anchor.Date.In(oldLoc), newStart)
+	duration := req.EndDate.Sub(req.Date)
+	models.SortCancellationPolicy(req.CancellationPolicy)
+
+	var tags []models.Tag
+	if len(req.TagIDs) > 0 {
+		if err := tx.Find(&tags, "id IN ?", req.TagIDs).Error; err != nil {
+			return err
+		}
+	}
+
+	for i := range occurrences {
+		occurrence := &occurrences[i]
+		if occurrence.Status == models.EventStatusCancelled || occurrence.Status == models.EventStatusCompleted {
+			continue
+		}
+
+		if err := checkTicketCurrency(tx, occurrence.ID, req.Price.Currency); err != nil {
+			return err
+		}
+
+		local := occurrence.Date.In(oldLoc)
+		start := time.Date(local.Year(), local.Month(), local.Day()+dayShift,
+			newStart.Hour(), newStart.Minute(), newStart.Second(), 0, loc).UTC()
+		offset := start.Sub(req.Date)
+
+		occurrence.Name = req.Name
+		occurrence.Description = req.Description
+		occurrence.Category = req.Category
+		occurrence.Location = req.Location
+		occurrence.VenueID = req.VenueID
+		occurrence.Price = *req.Price
+		occurrence.Image = req.Image
+		occurrence.Capacity = req.Capacity
+		occurrence.TimeZone = req.TimeZone
+		occurrence.Date = start
+		occurrence.EndDate = start.Add(duration)
+		occurrence.SalesStartAt = shiftTime(req.SalesStartAt, offset)
+		occurrence.SalesEndAt = shiftTime(req.SalesEndAt, offset)
+		occurrence.CancellationPolicy = req.CancellationPolicy
+		occurrence.TransfersDisabled = req.TransfersDisabled
+		occurrence.MaxTicketsPerOrder = req.MaxTicketsPerOrder
+		occurrence.MaxTicketsPerUser = req.MaxTicketsPerUser
+
+		if err := tx.Omit("Reserved").Save(occurrence).Error; err != nil {
+			return err
+		}
+		if occurrence.TransfersDisabled {
+			if err := booking.CancelPendingTransfers(tx, occurrence.ID, time.Now()); err != nil {
+				return err
+			}
+		}
+		if len(tags) > 0 {
+			if err := tx.Model(occurrence).Association("Tags").Replace(tags); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// bindSeriesEdit reads and validates the body of a series edit
+func bindSeriesEdit(c *gin.Context) (*models.CreateEventRequest, bool) {
+	var req models.CreateEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if err := validateEventTimes(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if err := validateSchedule(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if req.RRule != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The recurrence rule of an existing series cannot be changed"})
+		return nil, false
+	}
+	if err := applyVenue(database.GetDB(), &req); err != nil {
+		if err == errVenueNotFound {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Venue not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch venue"})
+		return nil, false
+	}
+	return &req, true
+}
+
+// respondWithSeries writes the series with its occurrences in date order
+func respondWithSeries(c *gin.Context, id string) {
+	var series models.EventSeries
+	err := database.GetDB().
+		Preload("Events", func(db *gorm.DB) *gorm.DB {
+			if !auth.IsAdmin(c) {
+				db = publicStatuses(db)
+			}
+			return db.Order("date ASC")
+		}).
+		Preload("Events.Tags").
+		Preload("Events.Venue").
+		First(&series, "id = ?", id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Series not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch series"})
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// @Summary Get an event series
+// @Description Get a recurring series with its occurrences in date order. Draft occurrences are only listed for admins.
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path string true "Series ID"
+// @Success 200 {object} models.EventSeries
+// @Failure 404 {object} models.ErrorResponse
+// @Router /series/{id} [get]
+func GetSeriesHandler(c *gin.Context) {
+	respondWithSeries(c, c.Param("id"))
+}
+
+// @Summary Update a whole series
+// @Description Apply changes to every occurrence of a series (admin only). The request's date and endDate are for the first occurrence; the others move by the same number of days and take the same local start time and duration. Cancelled and completed occurrences are not changed.
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path string true "Series ID"
+// @Param request body models.CreateEventRequest true "Event details"
+// @Security Bearer
+// @Success 200 {object} models.EventSeries
+// @Failure 404 {object} models.ErrorResponse
+// @Router /series/{id} [put]
+func UpdateSeriesHandler(c *gin.Context) {
+	req, ok := bindSeriesEdit(c)
+	if !ok {
+		return
+	}
+
+	var series models.EventSeries
+	if err := database.GetDB().First(&series, "id = ?", c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Series not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch series"})
+		return
+	}
+
+	err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		var occurrences []models.Event
+		if err := tx.Where("series_id = ?", series.ID).Order("date ASC").Find(&occurrences).Error; err != nil {
+			return err
+		}
+		if len(occurrences) == 0 {
+			return nil
+		}
+		if err := applyToOccurrences(tx, occurrences[0], occurrences, req); err != nil {
+			return err
+		}
+		return tx.Model(&series).Update("time_zone", req.TimeZone).Error
+	})
+	if err == errCurrencyMismatch {
+		c.JSON(http.StatusConflict, gin.H{"error": "The currency cannot be changed while ticket types are priced in another currency"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update series"})
+		return
+	}
+
+	respondWithSeries(c, series.ID)
+}
+
+// @Summary Update this and following occurrences
+// @Description Apply changes to an occurrence and every later occurrence of its series (admin only). Unless it is the first occurrence, they are split off into a new series so later whole-series edits of the original leave them alone.
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID of the first occurrence to change"
+// @Param request body models.CreateEventRequest true "Event details"
+// @Security Bearer
+// @Success 200 {object} models.EventSeries
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /events/{id}/following [put]
+func UpdateFollowingOccurrencesHandler(c *gin.Context) {
+	req, ok := bindSeriesEdit(c)
+	if !ok {
+		return
+	}
+
+	var event models.Event
+	if err := database.GetDB().First(&event, "id = ?", c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch event"})
+		return
+	}
+
+	seriesID := ""
+	err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if event.SeriesID == nil {
+			return errNotInSeries
+		}
+		var series models.EventSeries
+		if err := tx.First(&series, "id = ?", *event.SeriesID).Error; err != nil {
+			return err
+		}
+
+		var earlier int64
+		if err := tx.Model(&models.Event{}).Where("series_id = ? AND date < ?", series.ID, event.Date).Count(&earlier).Error; err != nil {
+			return err
+		}
+		if earlier > 0 {
+			split := models.EventSeries{
+				ID:       uuid.New().String(),
+				RRule:    series.RRule,
+				ExDates:  series.ExDates,
+				TimeZone: req.TimeZone,
+			}
+			if err := tx.Create(&split).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&models.Event{}).
+				Where("series_id = ? AND date >= ?", series.ID, event.Date).
+				Update("series_id", split.ID).Error; err != nil {
+				return err
+			}
+			series = split
+		}
+		seriesID = series.ID
+
+		var occurrences []models.Event
+		if err := tx.Where("series_id = ?", series.ID).Order("date ASC").Find(&occurrences).Error; err != nil {
+			return err
+		}
+		return applyToOccurrences(tx, event, occurrences, req)
+	})
+	if err == errNotInSeries {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Event is not part of a series"})
+		return
+	}
+	if err == errCurrencyMismatch {
+		c.JSON(http.StatusConflict, gin.H{"error": "The currency cannot be changed while ticket types are priced in another currency"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update occurrences"})
+		return
+	}
+
+	respondWithSeries(c, seriesID)
+}
@@ -0,0 +1,141 @@
+package event
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+	"online-task/pkg/money"
+)
+
+func seriesRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	r := setupEventTest(t)
+	if err := database.GetDB().AutoMigrate(&models.EventSeries{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	r.POST("/api/events", CreateEventHandler)
+	r.PUT("/api/events/:id/following", UpdateFollowingOccurrencesHandler)
+	r.PUT("/api/series/:id", UpdateSeriesHandler)
+	return r
+}
+
+func sendJSON(r *gin.Engine, method, path string, body interface{}) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(body)
+	req := httptest.NewRequest(method, path, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func meetupRequest(start time.Time) models.CreateEventRequest {
+	return models.CreateEventRequest{
+		Name:        "Go meetup",
+		Description: "Weekly talks",
+		Category:    "Tech",
+		Date:        start,
+		EndDate:     start.Add(2 * time.Hour),
+		TimeZone:    "Europe/Berlin",
+		Location:    "Berlin",
+		Price:       &money.Money{Amount: 1000, Currency: "USD"},
+		Image:       "/uploads/images/meetup.png",
+		Capacity:    30,
+	}
+}
+
+func TestExpandOccurrences(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	// 19:00 local on the Tuesday before the March 2024 daylight saving change
+	start := time.Date(2024, 3, 26, 19, 0, 0, 0, berlin).AddDate(0, 0, -7)
+	exdate := start.AddDate(0, 0, 14)
+
+	starts, err := expandOccurrences("RRULE:FREQ=WEEKLY;COUNT=4", []time.Time{exdate}, start, berlin)
+	if err != nil {
+		t.Fatalf("expandOccurrences() error = %v", err)
+	}
+	if len(starts) != 3 {
+		t.Fatalf("got %d occurrences, want 3", len(starts))
+	}
+	for _, s := range starts {
+		if s.Equal(exdate) {
+			t.Errorf("excluded date %v was generated", exdate)
+		}
+		if local := s.In(berlin); local.Hour() != 19 {
+			t.Errorf("occurrence %v starts at %d:00 local, want 19:00", s, local.Hour())
+		}
+	}
+
+	if _, err := expandOccurrences("FREQ=DAILY", nil, start, berlin); err == nil {
+		t.Error("expected an error for an unbounded rule")
+	}
+	if _, err := expandOccurrences("FREQ=SOMETIMES", nil, start, berlin); err == nil {
+		t.Error("expected an error for an invalid rule")
+	}
+}
+
+func TestCreateAndEditSeries(t *testing.T) {
+	r := seriesRouter(t)
+	start := time.Date(2030, 1, 1, 18, 0, 0, 0, time.UTC)
+	req := meetupRequest(start)
+	req.RRule = "FREQ=WEEKLY;COUNT=4"
+	req.Status = models.EventStatusPublished
+
+	w := sendJSON(r, http.MethodPost, "/api/events", req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create: status %d, body %s", w.Code, w.Body.String())
+	}
+	var series models.EventSeries
+	if err := json.Unmarshal(w.Body.Bytes(), &series); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if len(series.Events) != 4 {
+		t.Fatalf("got %d occurrences, want 4", len(series.Events))
+	}
+	for _, occurrence := range series.Events {
+		if occurrence.Capacity != 30 || occurrence.SeriesID == nil || *occurrence.SeriesID != series.ID {
+			t.Errorf("occurrence not linked with its own capacity: %+v", occurrence)
+		}
+	}
+
+	// Move the third and fourth meetups an hour later; they split off
+	third := series.Events[2]
+	edit := meetupRequest(third.Date.Add(time.Hour))
+	edit.Name = "Go meetup (late)"
+	w = sendJSON(r, http.MethodPut, "/api/events/"+third.ID+"/following", edit)
+	if w.Code != http.StatusOK {
+		t.Fatalf("following: status %d, body %s", w.Code, w.Body.String())
+	}
+	var split models.EventSeries
+	if err := json.Unmarshal(w.Body.Bytes(), &split); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if split.ID == series.ID || len(split.Events) != 2 {
+		t.Fatalf("expected the last two occurrences in a new series, got %+v", split)
+	}
+	if !split.Events[1].Date.Equal(series.Events[3].Date.Add(time.Hour)) || split.Events[1].Name != "Go meetup (late)" {
+		t.Errorf("following occurrence not updated: %+v", split.Events[1])
+	}
+
+	// Whole-series edits of the original only touch the first two
+	edit = meetupRequest(start)
+	edit.Capacity = 50
+	w = sendJSON(r, http.MethodPut, "/api/series/"+series.ID, edit)
+	if w.Code != http.StatusOK {
+		t.Fatalf("series: status %d, body %s", w.Code, w.Body.String())
+	}
+	var original models.EventSeries
+	if err := json.Unmarshal(w.Body.Bytes(), &original); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if len(original.Events) != 2 || original.Events[1].Capacity != 50 {
+		t.Errorf("series edit not applied to the remaining occurrences: %+v", original.Events)
+	}
+}
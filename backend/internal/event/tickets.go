@@ -0,0 +1,232 @@
+package event
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+)
+
+var errCurrencyMismatch = errors.New("ticket types are priced in another currency")
+
+// orderedTicketTypes preloads ticket types in display order
+func orderedTicketTypes(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC, price_amount ASC")
+}
+
+// checkTicketCurrency returns errCurrencyMismatch if any ticket type of the
+// event is priced in a currency other than the given one, so bookings of an
+// event are always in a single currency
+func checkTicketCurrency(db *gorm.DB, eventID, currency string) error {
+	var count int64
+	if err := db.Model(&models.TicketType{}).Where("event_id = ? AND price_currency <> ?", eventID, currency).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return errCurrencyMismatch
+	}
+	return nil
+}
+
+// validateTicketType checks the sales window of a ticket type request
+func validateTicketType(req *models.CreateTicketTypeRequest) error {
+	if req.SalesStartAt != nil && req.SalesEndAt != nil && !req.SalesEndAt.After(*req.SalesStartAt) {
+		return errors.New("salesEndAt must be after salesStartAt")
+	}
+	return nil
+}
+
+// findTicketType loads a ticket type and checks it belongs to the event in the path
+func findTicketType(c *gin.Context) (*models.TicketType, bool) {
+	var ticketType models.TicketType
+	if err := database.GetDB().First(&ticketType, "id = ? AND event_id = ?", c.Param("typeId"), c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Ticket type not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch ticket type"})
+		return nil, false
+	}
+	return &ticketType, true
+}
+
+// @Summary List ticket types
+// @Description Get the ticket types of an event with their prices and how many are sold
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Success 200 {array} models.TicketType
+// @Router /events/{id}/ticket-types [get]
+func GetTicketTypesHandler(c *gin.Context) {
+	var ticketTypes []models.TicketType
+	if err := orderedTicketTypes(database.GetDB()).Where("event_id = ?", c.Param("id")).Find(&ticketTypes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch ticket types"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ticketTypes)
+}
+
+// @Summary Add a ticket type
+// @Description Add a ticket type such as early-bird or VIP to an event (admin only)
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param request body models.CreateTicketTypeRequest true "Ticket type details"
+// @Security Bearer
+// @Success 201 {object} models.TicketType
+// @Router /events/{id}/ticket-types [post]
+func CreateTicketTypeHandler(c *gin.Context) {
+	id := c.Param("id")
+	var req models.CreateTicketTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateTicketType(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var event models.Event
+	if err := database.GetDB().First(&event, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch event"})
+		return
+	}
+
+	if req.Price.Currency != event.Price.Currency {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Ticket type price must be in the event currency " + event.Price.Currency})
+		return
+	}
+
+	ticketType := models.TicketType{
+		ID:           uuid.New().String(),
+		EventID:      id,
+		Name:         req.Name,
+		Description:  req.Description,
+		Price:        *req.Price,
+		Quota:        req.Quota,
+		Zone:         req.Zone,
+		SalesStartAt: req.SalesStartAt,
+		SalesEndAt:   req.SalesEndAt,
+		Position:     req.Position,
+	}
+
+	if err := database.GetDB().Create(&ticketType).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create ticket type"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, ticketType)
+}
+
+// @Summary Update a ticket type
+// @Description Update a ticket type of an event (admin only). The quota cannot be lowered below the number already sold.
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param typeId path string true "Ticket type ID"
+// @Param request body models.CreateTicketTypeRequest true "Ticket type details"
+// @Security Bearer
+// @Success 200 {object} models.TicketType
+// @Failure 409 {object} models.ErrorResponse
+// @Router /events/{id}/ticket-types/{typeId} [put]
+func UpdateTicketTypeHandler(c *gin.Context) {
+	var req models.CreateTicketTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateTicketType(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ticketType, ok := findTicketType(c)
+	if !ok {
+		return
+	}
+
+	var event models.Event
+	if err := database.GetDB().First(&event, "id = ?", ticketType.EventID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch event"})
+		return
+	}
+	if req.Price.Currency != event.Price.Currency {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Ticket type price must be in the event currency " + event.Price.Currency})
+		return
+	}
+
+	// Sold is changed by concurrent bookings, so it is never written here and
+	// the quota is only lowered if it still covers what has been sold
+	result := database.GetDB().Model(&models.TicketType{}).
+		Where("id = ? AND (? = 0 OR sold <= ?)", ticketType.ID, req.Quota, req.Quota).
+		Updates(map[string]interface{}{
+			"name":           req.Name,
+			"description":    req.Description,
+			"price_amount":   req.Price.Amount,
+			"price_currency": req.Price.Currency,
+			"quota":          req.Quota,
+			"zone":           req.Zone,
+			"sales_start_at": req.SalesStartAt,
+			"sales_end_at":   req.SalesEndAt,
+			"position":       req.Position,
+		})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update ticket type"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Quota is lower than the number of tickets already sold"})
+		return
+	}
+
+	if err := database.GetDB().First(ticketType, "id = ?", ticketType.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch ticket type"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ticketType)
+}
+
+// @Summary Delete a ticket type
+// @Description Remove a ticket type from an event (admin only). Ticket types with sold tickets cannot be deleted; set their quota to what was sold to stop sales instead.
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param typeId path string true "Ticket type ID"
+// @Security Bearer
+// @Success 200 {object} models.SuccessResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /events/{id}/ticket-types/{typeId} [delete]
+func DeleteTicketTypeHandler(c *gin.Context) {
+	ticketType, ok := findTicketType(c)
+	if !ok {
+		return
+	}
+
+	result := database.GetDB().Where("id = ? AND sold = 0", ticketType.ID).Delete(&models.TicketType{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete ticket type"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Ticket type has sold tickets"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: "Ticket type deleted successfully"})
+}
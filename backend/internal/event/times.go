@@ -0,0 +1,46 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"online-task/internal/models"
+)
+
+// validateEventTimes checks the start, end and time zone of a request and
+// defaults the time zone to UTC
+func validateEventTimes(req *models.CreateEventRequest) error {
+	if req.TimeZone == "" {
+		req.TimeZone = "UTC"
+	}
+	if _, err := time.LoadLocation(req.TimeZone); err != nil {
+		return fmt.Errorf("unknown time zone %q", req.TimeZone)
+	}
+	if !req.EndDate.After(req.Date) {
+		return errors.New("endDate must be after date")
+	}
+	return nil
+}
+
+// overlapping limits the query to events that overlap the from/to query
+// range. Either bound may be omitted.
+func overlapping(query *gorm.DB, from, to string) (*gorm.DB, error) {
+	if from != "" {
+		start, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return nil, errors.New("from must be an RFC 3339 date-time")
+		}
+		query = query.Where("end_date >= ?", start.UTC())
+	}
+	if to != "" {
+		end, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return nil, errors.New("to must be an RFC 3339 date-time")
+		}
+		query = query.Where("date <= ?", end.UTC())
+	}
+	return query, nil
+}
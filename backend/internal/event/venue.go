@@ -0,0 +1,131 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"online-task/internal/models"
+	"online-task/pkg/geo"
+)
+
+// defaultRadiusKm is used by the near filter when no radius is given
+const defaultRadiusKm = 25
+
+var errVenueNotFound = errors.New("venue not found")
+
+// applyVenue fills the location and capacity of a request from its venue
+// when they were left empty
+func applyVenue(db *gorm.DB, req *models.CreateEventRequest) error {
+	if req.VenueID == nil || *req.VenueID == "" {
+		req.VenueID = nil
+		return nil
+	}
+
+	var venue models.Venue
+	if err := db.First(&venue, "id = ?", *req.VenueID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errVenueNotFound
+		}
+		return err
+	}
+	if req.Location == "" {
+		req.Location = fmt.Sprintf("%s, %s", venue.Name, venue.Address)
+	}
+	if req.Capacity == 0 {
+		req.Capacity = venue.DefaultCapacity
+	}
+	return nil
+}
+
+// findVenueConflicts sets the event's VenueConflicts to the other events at
+// its venue whose times overlap it. Cancelled events are ignored.
+func findVenueConflicts(db *gorm.DB, event *models.Event) error {
+	event.VenueConflicts = nil
+	if event.VenueID == nil {
+		return nil
+	}
+
+	var others []models.Event
+	err := db.Where("venue_id = ? AND id <> ? AND status <> ? AND date < ? AND end_date > ?",
+		*event.VenueID, event.ID, models.EventStatusCancelled, event.EndDate, event.Date).
+		Order("date ASC").
+		Find(&others).Error
+	if err != nil {
+		return err
+	}
+	for _, other := range others {
+		event.VenueConflicts = append(event.VenueConflicts, models.VenueConflict{
+			EventID: other.ID,
+			Name:    other.Name,
+			Date:    other.Date,
+			EndDate: other.EndDate,
+		})
+	}
+	return nil
+}
+
+// nearFilter is a parsed near=lat,lng&radius=km query
+type nearFilter struct {
+	lat, lng, radiusKm float64
+}
+
+// parseNear reads the near and radius query values. It returns nil when
+// near is not set.
+func parseNear(near, radius string) (*nearFilter, error) {
+	if near == "" {
+		return nil, nil
+	}
+	parts := strings.Split(near, ",")
+	if len(parts) != 2 {
+		return nil, errors.New("near must be lat,lng")
+	}
+	lat, errLat := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	lng, errLng := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if errLat != nil || errLng != nil || lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		return nil, errors.New("near must be a valid lat,lng")
+	}
+
+	filter := &nearFilter{lat: lat, lng: lng, radiusKm: defaultRadiusKm}
+	if radius != "" {
+		km, err := strconv.ParseFloat(radius, 64)
+		if err != nil || km <= 0 {
+			return nil, errors.New("radius must be a positive number of kilometres")
+		}
+		filter.radiusKm = km
+	}
+	return filter, nil
+}
+
+// query limits events to venues inside the filter's bounding box. The exact
+// distance is checked afterwards by apply.
+func (f *nearFilter) query(db *gorm.DB) *gorm.DB {
+	minLat, maxLat, minLng, maxLng := geo.BoundingBox(f.lat, f.lng, f.radiusKm)
+	return db.Joins("Venue").
+		Where("Venue.latitude BETWEEN ? AND ? AND Venue.longitude BETWEEN ? AND ?", minLat, maxLat, minLng, maxLng)
+}
+
+// apply drops events outside the radius, sets their distance and sorts the
+// rest nearest first
+func (f *nearFilter) apply(events []models.Event) []models.Event {
+	nearby := events[:0]
+	for _, event := range events {
+		if event.Venue == nil {
+			continue
+		}
+		distance := geo.Distance(f.lat, f.lng, event.Venue.Latitude, event.Venue.Longitude)
+		if distance > f.radiusKm {
+			continue
+		}
+		event.DistanceKm = &distance
+		nearby = append(nearby, event)
+	}
+	sort.SliceStable(nearby, func(i, j int) bool {
+		return *nearby[i].DistanceKm < *nearby[j].DistanceKm
+	})
+	return nearby
+}
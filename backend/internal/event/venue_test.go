@@ -0,0 +1,82 @@
+package event
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+)
+
+func seedVenue(t *testing.T, id string, lat, lng float64) {
+	t.Helper()
+	venue := models.Venue{ID: id, Name: id, Address: id, Latitude: lat, Longitude: lng, DefaultCapacity: 200}
+	if err := database.GetDB().Create(&venue).Error; err != nil {
+		t.Fatalf("failed to create venue: %v", err)
+	}
+}
+
+func TestListEventsNearLocation(t *testing.T) {
+	r := setupEventTest(t)
+	if err := database.GetDB().AutoMigrate(&models.Venue{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	seedVenue(t, "zamalek", 30.0425, 31.2243)
+	seedVenue(t, "giza", 29.9792, 31.1342)
+	seedVenue(t, "alexandria", 31.2001, 29.9187)
+
+	start := time.Date(2030, 5, 1, 18, 0, 0, 0, time.UTC)
+	for _, id := range []string{"zamalek", "giza", "alexandria"} {
+		seedEvent(t, "at-"+id, start, start.Add(time.Hour), "UTC")
+		venueID := id
+		database.GetDB().Model(&models.Event{}).Where("id = ?", "at-"+id).Update("venue_id", venueID)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/events?near=30.0444,31.2357&radius=20", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+	}
+
+	var events []models.Event
+	if err := json.Unmarshal(w.Body.Bytes(), &events); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if len(events) != 2 || events[0].ID != "at-zamalek" || events[1].ID != "at-giza" {
+		t.Fatalf("got %d events, want zamalek then giza", len(events))
+	}
+	if events[0].DistanceKm == nil || *events[0].DistanceKm > *events[1].DistanceKm {
+		t.Errorf("events not sorted by distance")
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/events?near=somewhere", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("invalid near: status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestFindVenueConflicts(t *testing.T) {
+	setupEventTest(t)
+	if err := database.GetDB().AutoMigrate(&models.Venue{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	seedVenue(t, "hall", 30.0425, 31.2243)
+	venueID := "hall"
+
+	start := time.Date(2030, 5, 1, 18, 0, 0, 0, time.UTC)
+	seedEvent(t, "concert", start, start.Add(3*time.Hour), "UTC")
+	seedEvent(t, "next-day", start.Add(24*time.Hour), start.Add(27*time.Hour), "UTC")
+	database.GetDB().Model(&models.Event{}).Where("1 = 1").Update("venue_id", venueID)
+
+	event := models.Event{ID: "talk", VenueID: &venueID, Date: start.Add(2 * time.Hour), EndDate: start.Add(4 * time.Hour)}
+	if err := findVenueConflicts(database.GetDB(), &event); err != nil {
+		t.Fatalf("findVenueConflicts() error = %v", err)
+	}
+	if len(event.VenueConflicts) != 1 || event.VenueConflicts[0].EventID != "concert" {
+		t.Errorf("got conflicts %+v, want only the concert", event.VenueConflicts)
+	}
+}
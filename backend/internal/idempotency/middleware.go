@@ -0,0 +1,240 @@
+package idempotency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"online-task/internal/booking"
+	"online-task/internal/models"
+	"online-task/pkg/database"
+)
+
+const (
+	// Header carries the client's key for a request it may retry
+	Header = "Idempotency-Key"
+	// ReplayedHeader is set on responses replayed for a retry
+	ReplayedHeader = "Idempotent-Replayed"
+)
+
+// Error codes of requests whose key cannot be used
+const (
+	ErrCodeKeyReused  = "idempotency_key_reused"
+	ErrCodeInProgress = "idempotency_in_progress"
+)
+
+// maxKeyLength bounds the keys clients may send
+const maxKeyLength = 255
+
+// ttl is how long responses are kept for retries, from IDEMPOTENCY_TTL_HOURS
+// (default 24)
+func ttl() time.Duration {
+	hours, err := strconv.Atoi(os.Getenv("IDEMPOTENCY_TTL_HOURS"))
+	if err != nil || hours <= 0 {
+		hours = 24
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// recorder keeps a copy of the response written by the handler
+type recorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// scope is who a key belongs to, so clients cannot replay each other's
+// responses: the signed-in user, else the guest's booking token. It is empty
+// for anonymous callers, who have nothing to tell them apart.
+func scope(c *gin.Context) string {
+	if userID, ok := c.Get("userID"); ok {
+		return "user:" + userID.(string)
+	}
+	token := c.GetHeader(booking.GuestTokenHeader)
+	if token == "" {
+		token = c.Query("token")
+	}
+	if token == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(token))
+	return "guest:" + hex.EncodeToString(sum[:])
+}
+
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// claim records the key for a request about to be handled. It reports false
+// if the key is already in use, replacing records that have expired. A key
+// held by a request that is still running is never taken over, however long
+// it runs, since both requests would then book or pay; one abandoned when the
+// server stopped is freed when it expires.
+func claim(record *models.IdempotencyKey, now time.Time) (bool, error) {
+	var claimed bool
+	err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("scope = ? AND request_key = ?", record.Scope, record.Key).
+			Where("expires_at <= ?", now).
+			Delete(&models.IdempotencyKey{}).Error; err != nil {
+			return err
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+		claimed = result.RowsAffected == 1
+		return result.Error
+	})
+	return claimed, err
+}
+
+// release forgets a key so the request can be retried
+func release(record *models.IdempotencyKey) {
+	database.GetDB().Where("scope = ? AND request_key = ?", record.Scope, record.Key).Delete(&models.IdempotencyKey{})
+}
+
+// Middleware makes mutating requests that carry an Idempotency-Key header
+// safe to retry. The first response with a key is stored for
+// IDEMPOTENCY_TTL_HOURS and replayed to retries with the same key and
+// request, marked by the Idempotent-Replayed header. Reusing a key for
+// another request fails with 422, and retrying while the first request is
+// still being handled with 409, until it finishes or the key expires. Server errors are not stored, so those
+// requests can be retried. Responses to anonymous callers, such as a new
+// guest booking with its token, are never stored, as anyone could replay
+// them. It must run after authentication.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(Header)
+		method := c.Request.Method
+		if key == "" || method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions {
+			c.Next()
+			return
+		}
+		scope := scope(c)
+		if scope == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key must be at most 255 characters"})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		now := time.Now()
+		record := models.IdempotencyKey{
+			Scope:       scope,
+			Key:         key,
+			Fingerprint: fingerprint(c.Request, body),
+			ExpiresAt:   now.Add(ttl()),
+			CreatedAt:   now,
+		}
+		claimed, err := claim(&record, now)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to check Idempotency-Key"})
+			return
+		}
+		if !claimed {
+			replay(c, &record)
+			return
+		}
+
+		w := &recorder{ResponseWriter: c.Writer}
+		c.Writer = w
+		defer func() {
+			if r := recover(); r != nil {
+				release(&record)
+				panic(r)
+			}
+		}()
+		c.Next()
+
+		if w.Status() >= http.StatusInternalServerError {
+			release(&record)
+			return
+		}
+		database.GetDB().Model(&models.IdempotencyKey{}).
+			Where("scope = ? AND request_key = ?", record.Scope, record.Key).
+			Updates(map[string]interface{}{
+				"status_code":  w.Status(),
+				"content_type": w.Header().Get("Content-Type"),
+				"body":         w.body.Bytes(),
+			})
+	}
+}
+
+// replay answers a request whose key is already in use
+func replay(c *gin.Context, record *models.IdempotencyKey) {
+	var stored models.IdempotencyKey
+	if err := database.GetDB().First(&stored, "scope = ? AND request_key = ?", record.Scope, record.Key).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			// The first request failed and gave the key up in the meantime
+			c.AbortWithStatusJSON(http.StatusConflict, models.CodedErrorResponse{Error: "A request with this Idempotency-Key is still being handled", Code: ErrCodeInProgress})
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to check Idempotency-Key"})
+		return
+	}
+
+	switch {
+	case stored.Fingerprint != record.Fingerprint:
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, models.CodedErrorResponse{Error: "Idempotency-Key was already used for another request", Code: ErrCodeKeyReused})
+	case stored.StatusCode == 0:
+		c.AbortWithStatusJSON(http.StatusConflict, models.CodedErrorResponse{Error: "A request with this Idempotency-Key is still being handled", Code: ErrCodeInProgress})
+	default:
+		c.Header(ReplayedHeader, "true")
+		c.Data(stored.StatusCode, stored.ContentType, stored.Body)
+		c.Abort()
+	}
+}
+
+// removeExpiredKeys deletes stored responses past their TTL and returns how
+// many were removed
+func removeExpiredKeys(now time.Time) (int64, error) {
+	result := database.GetDB().Where("expires_at <= ?", now).Delete(&models.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
+
+// StartCleaner periodically removes stored responses past their TTL
+func StartCleaner(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			removed, err := removeExpiredKeys(time.Now())
+			if err != nil {
+				log.Printf("Failed to remove expired idempotency keys: %v", err)
+				continue
+			}
+			if removed > 0 {
+				log.Printf("Removed %d expired idempotency keys", removed)
+			}
+		}
+	}()
+}
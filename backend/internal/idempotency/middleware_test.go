@@ -0,0 +1,151 @@
+package idempotency
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+)
+
+// setupIdempotencyTest returns a router whose handler counts the bookings it
+// makes and fails with 500 for the "broken" event
+func setupIdempotencyTest(t *testing.T) (*gin.Engine, *int) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&models.IdempotencyKey{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	database.DB = db
+
+	booked := 0
+	r := gin.New()
+	user := r.Group("/api", func(c *gin.Context) {
+		if id := c.GetHeader("X-User"); id != "" {
+			c.Set("userID", id)
+		}
+	}, Middleware())
+	user.POST("/bookings", func(c *gin.Context) {
+		var req struct {
+			EventID string `json:"eventId"`
+		}
+		c.ShouldBindJSON(&req)
+		if req.EventID == "broken" {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create booking"})
+			return
+		}
+		booked++
+		c.JSON(http.StatusCreated, gin.H{"eventId": req.EventID, "booking": booked})
+	})
+	return r, &booked
+}
+
+func send(r *gin.Engine, user, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/bookings", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if user != "" {
+		req.Header.Set("X-User", user)
+	}
+	if key != "" {
+		req.Header.Set(Header, key)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotentRetries(t *testing.T) {
+	r, booked := setupIdempotencyTest(t)
+	first := send(r, "user-1", "key-1", `{"eventId":"concert"}`)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("first request status = %d, body %s", first.Code, first.Body.String())
+	}
+
+	tests := []struct {
+		name         string
+		user         string
+		key          string
+		body         string
+		wantStatus   int
+		wantReplayed bool
+		wantBooked   int
+	}{
+		{name: "retry", user: "user-1", key: "key-1", body: `{"eventId":"concert"}`, wantStatus: http.StatusCreated, wantReplayed: true, wantBooked: 1},
+		{name: "key reused for another body", user: "user-1", key: "key-1", body: `{"eventId":"opera"}`, wantStatus: http.StatusUnprocessableEntity, wantBooked: 1},
+		{name: "same key of another user", user: "user-2", key: "key-1", body: `{"eventId":"concert"}`, wantStatus: http.StatusCreated, wantBooked: 2},
+		{name: "without a key", user: "user-1", body: `{"eventId":"concert"}`, wantStatus: http.StatusCreated, wantBooked: 3},
+		{name: "key too long", user: "user-1", key: strings.Repeat("k", maxKeyLength+1), body: `{}`, wantStatus: http.StatusBadRequest, wantBooked: 3},
+		{name: "anonymous", key: "guest-key", body: `{"eventId":"concert"}`, wantStatus: http.StatusCreated, wantBooked: 4},
+		{name: "another anonymous caller", key: "guest-key", body: `{"eventId":"concert"}`, wantStatus: http.StatusCreated, wantBooked: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := send(r, tt.user, tt.key, tt.body)
+			replayed := w.Header().Get(ReplayedHeader) == "true"
+			if w.Code != tt.wantStatus || replayed != tt.wantReplayed || *booked != tt.wantBooked {
+				t.Errorf("got %d replayed=%v booked=%d, want %d replayed=%v booked=%d, body %s",
+					w.Code, replayed, *booked, tt.wantStatus, tt.wantReplayed, tt.wantBooked, w.Body.String())
+			}
+			if tt.wantReplayed && w.Body.String() != first.Body.String() {
+				t.Errorf("replayed body = %s, want %s", w.Body.String(), first.Body.String())
+			}
+		})
+	}
+
+	// Nothing an anonymous caller was sent, such as a guest's booking
+	// token, is kept for someone else to replay
+	var stored int64
+	database.GetDB().Model(&models.IdempotencyKey{}).Where("request_key = ?", "guest-key").Count(&stored)
+	if stored != 0 {
+		t.Errorf("stored %d responses to anonymous callers, want none", stored)
+	}
+}
+
+func TestIdempotencyKeyLifetime(t *testing.T) {
+	r, booked := setupIdempotencyTest(t)
+
+	// Server errors give the key back for the retry
+	if w := send(r, "user-1", "failing", `{"eventId":"broken"}`); w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", w.Code)
+	}
+	if w := send(r, "user-1", "failing", `{"eventId":"broken"}`); w.Header().Get(ReplayedHeader) != "" {
+		t.Errorf("a server error was replayed")
+	}
+
+	// A retry while the first request is still handled is refused, however
+	// long that request has been running
+	now := time.Now()
+	body := `{"eventId":"concert"}`
+	busy := fingerprint(httptest.NewRequest(http.MethodPost, "/api/bookings", nil), []byte(body))
+	database.GetDB().Create(&models.IdempotencyKey{Scope: "user:user-1", Key: "busy", Fingerprint: busy, ExpiresAt: now.Add(time.Hour), CreatedAt: now.Add(-10 * time.Minute)})
+	if w := send(r, "user-1", "busy", body); w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), ErrCodeInProgress) {
+		t.Errorf("in progress: got %d %s, want 409 %s", w.Code, w.Body.String(), ErrCodeInProgress)
+	}
+	if *booked != 0 {
+		t.Errorf("booked %d times while the first request held the key, want 0", *booked)
+	}
+
+	// Expired keys are used afresh
+	send(r, "user-1", "old", body)
+	removed, err := removeExpiredKeys(now.Add(ttl() + time.Minute))
+	if err != nil || removed != 2 {
+		t.Fatalf("removeExpiredKeys() = %d, %v, want 2", removed, err)
+	}
+	if w := send(r, "user-1", "old", body); w.Header().Get(ReplayedHeader) != "" || *booked != 2 {
+		t.Errorf("expired key: replayed = %q, booked = %d, want a new booking", w.Header().Get(ReplayedHeader), *booked)
+	}
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Attendee is a named ticket holder of a group booking. Each attendee has
+// their own ticket and is checked in on their own.
+type Attendee struct {
+	ID        string `gorm:"primarykey" json:"id"`
+	BookingID string `gorm:"not null;index" json:"bookingId"`
+	Name      string `gorm:"not null" json:"name" example:"Grace Hopper"`
+	Email     string `gorm:"not null" json:"email" example:"grace@example.com"`
+	// CheckedInAt, CheckedInBy and CheckedInDevice record the attendee's
+	// arrival like the booking's fields do for unnamed bookings
+	CheckedInAt     *time.Time `json:"checkedInAt,omitempty" format:"date-time" example:"2024-03-25T18:45:00Z"`
+	CheckedInBy     *string    `json:"checkedInBy,omitempty"`
+	CheckedInDevice *string    `json:"checkedInDevice,omitempty" example:"gate-2"`
+	CreatedAt       time.Time  `json:"createdAt" format:"date-time" example:"2024-03-20T10:00:00Z"`
+}
+
+// AttendeeRequest names one ticket holder of a booking
+type AttendeeRequest struct {
+	Name  string `json:"name" binding:"required,max=100" example:"Grace Hopper"`
+	Email string `json:"email" binding:"required,email" example:"grace@example.com"`
+}
+
+// AttendeeResponse is an attendee with their own ticket
+type AttendeeResponse struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name" example:"Grace Hopper"`
+	Email       string     `json:"email" example:"grace@example.com"`
+	CheckedInAt *time.Time `json:"checkedInAt,omitempty" format:"date-time" example:"2024-03-25T18:45:00Z"`
+	// Ticket is the attendee's signed e-ticket token once the booking is
+	// confirmed
+	Ticket string `json:"ticket,omitempty" example:"eyJhIjoiM2Q...In0.Zx8p..."`
+}
@@ -4,28 +4,191 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+
+	"online-task/pkg/money"
+	"online-task/pkg/payment"
+)
+
+// Booking states
+const (
+	BookingStatusPendingPayment = "pending_payment"
+	BookingStatusConfirmed      = "confirmed"
+	BookingStatusCancelled      = "cancelled"
+	BookingStatusAttended       = "attended"
 )
 
 type Booking struct {
-	ID          string         `gorm:"primarykey" json:"id"`
-	UserID      string         `gorm:"not null" json:"userId"`
-	EventID     string         `gorm:"not null" json:"eventId"`
-	BookingDate time.Time      `json:"bookingDate" format:"date-time" example:"2024-03-20T10:00:00Z"`
-	CreatedAt   time.Time      `json:"createdAt" format:"date-time" example:"2024-03-20T10:00:00Z"`
-	UpdatedAt   time.Time      `json:"updatedAt" format:"date-time" example:"2024-03-20T10:00:00Z"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
-	Event       Event          `json:"event,omitempty"`
-	User        User           `json:"user,omitempty"`
+	ID string `gorm:"primarykey" json:"id"`
+	// UserID is empty for guest bookings until the guest registers with
+	// GuestEmail from a magic link
+	UserID     string `gorm:"not null;index" json:"userId"`
+	GuestName  string `json:"guestName,omitempty" example:"Ada Lovelace"`
+	GuestEmail string `gorm:"index" json:"guestEmail,omitempty" example:"ada@example.com"`
+	// GuestTokenHash is the SHA-256 of the token in the guest's magic link
+	GuestTokenHash string  `json:"-"`
+	EventID        string  `gorm:"not null" json:"eventId"`
+	TicketTypeID   *string `gorm:"index" json:"ticketTypeId,omitempty"`
+	Quantity       int     `gorm:"not null;default:1" json:"quantity" example:"2"`
+	// UnitPrice is the ticket price when the booking was made
+	UnitPrice money.Money `gorm:"embedded;embeddedPrefix:unit_price_" json:"unitPrice"`
+	// Discount is taken off by the promo code the booking was made with
+	PromoCodeID *string     `gorm:"index" json:"promoCodeId,omitempty"`
+	Discount    money.Money `gorm:"embedded;embeddedPrefix:discount_" json:"discount"`
+	// Total is UnitPrice times Quantity less the Discount
+	Total money.Money `gorm:"embedded;embeddedPrefix:total_" json:"total"`
+	// Paid bookings stay pending_payment until the provider confirms the
+	// payment by webhook, and are released once PaymentExpiresAt passes
+	PaymentProvider  string     `json:"paymentProvider,omitempty" example:"stripe"`
+	PaymentIntentID  *string    `gorm:"uniqueIndex" json:"paymentIntentId,omitempty"`
+	PaymentExpiresAt *time.Time `gorm:"index" json:"paymentExpiresAt,omitempty" format:"date-time" example:"2024-03-20T10:15:00Z"`
+	PaidAt           *time.Time `json:"paidAt,omitempty" format:"date-time" example:"2024-03-20T10:05:00Z"`
+	BookingDate      time.Time  `json:"bookingDate" format:"date-time" example:"2024-03-20T10:00:00Z"`
+	Status           string     `gorm:"default:confirmed;index" json:"status" example:"confirmed"`
+	CancelledAt      *time.Time `json:"cancelledAt,omitempty" format:"date-time" example:"2024-03-21T10:00:00Z"`
+	// CheckedInAt and CheckedInBy record when and by which staff member an
+	// attended booking was scanned at the door
+	CheckedInAt *time.Time `json:"checkedInAt,omitempty" format:"date-time" example:"2024-03-25T18:45:00Z"`
+	CheckedInBy *string    `json:"checkedInBy,omitempty"`
+	// CheckedInDevice is the scanner of an offline check-in
+	CheckedInDevice *string `json:"checkedInDevice,omitempty" example:"gate-2"`
+	// TicketVersion is signed into the e-ticket and bumped when the booking
+	// is transferred, so tickets issued to earlier owners stop admitting
+	TicketVersion int            `gorm:"not null;default:0" json:"ticketVersion"`
+	CreatedAt     time.Time      `json:"createdAt" format:"date-time" example:"2024-03-20T10:00:00Z"`
+	UpdatedAt     time.Time      `json:"updatedAt" format:"date-time" example:"2024-03-20T10:00:00Z"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+	Event         Event          `json:"event,omitempty"`
+	TicketType    *TicketType    `json:"ticketType,omitempty"`
+	User          User           `json:"user,omitempty"`
+	Refunds       []Refund       `json:"refunds,omitempty"`
+	// Attendees name the holder of each ticket of a group booking
+	Attendees []Attendee `json:"attendees,omitempty"`
+	// Seats are the reserved seats of a booking for a seated event
+	Seats []SeatReservation `json:"seats,omitempty"`
+}
+
+// HolderName is the name of the booking's user, or of the guest who made it
+func (b *Booking) HolderName() string {
+	if b.User.Username != "" {
+		return b.User.Username
+	}
+	return b.GuestName
+}
+
+// HolderEmail is the email address of the booking's user, or of the guest
+// who made it
+func (b *Booking) HolderEmail() string {
+	if b.User.Email != "" {
+		return b.User.Email
+	}
+	return b.GuestEmail
 }
 
 type CreateBookingRequest struct {
 	EventID string `json:"eventId" binding:"required"`
+	// TicketTypeID is required for events that sell ticket types
+	TicketTypeID string `json:"ticketTypeId,omitempty"`
+	// Quantity defaults to the number of attendees, or 1. The event's
+	// maxTicketsPerOrder limits it.
+	Quantity  int    `json:"quantity,omitempty" binding:"omitempty,min=1,max=100" example:"2"`
+	PromoCode string `json:"promoCode,omitempty" example:"SPRING25"`
+	// Attendees, when given, name the holder of every ticket; each gets
+	// their own ticket instead of one for the whole booking
+	Attendees []AttendeeRequest `json:"attendees,omitempty" binding:"omitempty,max=100,dive"`
+	// Seats are the seat IDs to book at events with a seat map, one per
+	// ticket
+	Seats []string `json:"seats,omitempty" binding:"omitempty,max=100"`
+	// HoldID books the tickets of a seat hold of the user, which must be
+	// for the same ticket type and quantity
+	HoldID string `json:"holdId,omitempty"`
 }
 
 type BookingResponse struct {
-	ID        string    `json:"id"`
-	UserID    string    `json:"userId"`
-	EventID   string    `json:"eventId"`
-	Event     Event     `json:"event"`
-	CreatedAt time.Time `json:"createdAt"`
-} 
\ No newline at end of file
+	ID         string      `json:"id"`
+	UserID     string      `json:"userId"`
+	GuestName  string      `json:"guestName,omitempty"`
+	GuestEmail string      `json:"guestEmail,omitempty"`
+	EventID    string      `json:"eventId"`
+	Status     string      `json:"status"`
+	TicketType *TicketType `json:"ticketType,omitempty"`
+	Quantity   int         `json:"quantity"`
+	UnitPrice  money.Money `json:"unitPrice"`
+	Discount   money.Money `json:"discount"`
+	Total      money.Money `json:"total"`
+	// Payment is included while the booking awaits payment
+	Payment          *payment.Intent `json:"payment,omitempty"`
+	PaymentExpiresAt *time.Time      `json:"paymentExpiresAt,omitempty"`
+	PaidAt           *time.Time      `json:"paidAt,omitempty"`
+	Event            Event           `json:"event"`
+	CreatedAt        time.Time       `json:"createdAt"`
+	CancelledAt      *time.Time      `json:"cancelledAt,omitempty"`
+	CheckedInAt      *time.Time      `json:"checkedInAt,omitempty"`
+	Refunds          []Refund        `json:"refunds,omitempty"`
+	// Ticket is the signed e-ticket token of a confirmed booking, as encoded
+	// in its QR code. Group bookings have a ticket per attendee instead.
+	Ticket    string             `json:"ticket,omitempty" example:"eyJiIjoiNmY...In0.kq3v..."`
+	Attendees []AttendeeResponse `json:"attendees,omitempty"`
+	Seats     []Seat             `json:"seats,omitempty"`
+	// GuestToken opens a new guest booking, as the link emailed to the
+	// guest does. It is only returned when the booking is created.
+	GuestToken string `json:"guestToken,omitempty" example:"q8J2nD4xY0..."`
+}
+
+// GuestBookingRequest books tickets without an account
+type GuestBookingRequest struct {
+	CreateBookingRequest
+	Name  string `json:"name" binding:"required,max=100" example:"Ada Lovelace"`
+	Email string `json:"email" binding:"required,email" example:"ada@example.com"`
+}
+
+// GuestLinkRequest asks for the links to a guest's bookings to be emailed
+// again
+type GuestLinkRequest struct {
+	Email string `json:"email" binding:"required,email" example:"ada@example.com"`
+}
+
+// TicketKeyResponse is the public key that verifies ticket tokens
+type TicketKeyResponse struct {
+	Algorithm string `json:"algorithm" example:"Ed25519"`
+	// PublicKey is the base64 encoded raw public key
+	PublicKey string `json:"publicKey" example:"MCowBQYDK2VwAyEA..."`
+}
+
+// PayBookingRequest confirms the payment of a pending booking
+type PayBookingRequest struct {
+	// PaymentMethod is a provider payment method; with the fake provider
+	// "fake_card_declined" simulates a declined card
+	PaymentMethod string `json:"paymentMethod,omitempty" example:"pm_card_visa"`
+}
+
+// AdminBooking is a booking as listed to admins, with who holds it
+type AdminBooking struct {
+	BookingResponse
+	HolderName  string `json:"holderName" example:"Ada Lovelace"`
+	HolderEmail string `json:"holderEmail" example:"ada@example.com"`
+}
+
+// AdminBookingList is one page of an event's bookings
+type AdminBookingList struct {
+	Bookings []AdminBooking `json:"bookings"`
+	Total    int64          `json:"total" example:"120"`
+	Page     int            `json:"page" example:"1"`
+	PageSize int            `json:"pageSize" example:"50"`
+}
+
+// AdminCancelBookingRequest gives the reason an admin cancels a booking for
+type AdminCancelBookingRequest struct {
+	Reason string `json:"reason,omitempty" binding:"max=500" example:"Duplicate booking"`
+	// RefundPercent of a paid booking's total is refunded, all of it by
+	// default
+	RefundPercent *int `json:"refundPercent,omitempty" binding:"omitempty,min=0,max=100" example:"100"`
+}
+
+// MoveBookingRequest moves a booking to another event or ticket type
+type MoveBookingRequest struct {
+	EventID string `json:"eventId" binding:"required"`
+	// TicketTypeID is required for events that sell ticket types
+	TicketTypeID string `json:"ticketTypeId,omitempty"`
+	// Seats are the seat IDs at events with a seat map, one per ticket
+	Seats []string `json:"seats,omitempty" binding:"omitempty,max=100"`
+}
@@ -0,0 +1,122 @@
+package models
+
+import "time"
+
+// CheckInRequest is a ticket scanned at the door
+type CheckInRequest struct {
+	// Token is the content of the ticket's QR code
+	Token string `json:"token" binding:"required"`
+	// EventID, when given, rejects tickets for other events
+	EventID string `json:"eventId,omitempty"`
+}
+
+// CheckInResponse describes the attendee let in by a scan
+type CheckInResponse struct {
+	BookingID string `json:"bookingId"`
+	// AttendeeID is set when the ticket of a group booking's attendee was
+	// scanned; Attendee is then their name
+	AttendeeID  string    `json:"attendeeId,omitempty"`
+	EventID     string    `json:"eventId"`
+	Attendee    string    `json:"attendee" example:"johndoe"`
+	TicketType  string    `json:"ticketType,omitempty" example:"VIP"`
+	Quantity    int       `json:"quantity" example:"2"`
+	CheckedInAt time.Time `json:"checkedInAt" format:"date-time" example:"2024-03-25T18:45:00Z"`
+	CheckedInBy string    `json:"checkedInBy"`
+}
+
+// CheckInErrorResponse explains why a scanned ticket was refused. Tickets
+// that were already used say when and by whom.
+type CheckInErrorResponse struct {
+	Error       string     `json:"error" example:"Ticket has already been used"`
+	Code        string     `json:"code" example:"already_checked_in"`
+	CheckedInAt *time.Time `json:"checkedInAt,omitempty" format:"date-time" example:"2024-03-25T18:45:00Z"`
+	CheckedInBy *string    `json:"checkedInBy,omitempty"`
+}
+
+// CheckInStats counts how many of an event's attendees have arrived
+type CheckInStats struct {
+	EventID string `json:"eventId"`
+	// Bookings and Tickets count confirmed and attended bookings
+	Bookings          int64      `json:"bookings" example:"120"`
+	Tickets           int64      `json:"tickets" example:"250"`
+	CheckedInBookings int64      `json:"checkedInBookings" example:"80"`
+	CheckedInTickets  int64      `json:"checkedInTickets" example:"170"`
+	RemainingTickets  int64      `json:"remainingTickets" example:"80"`
+	LastCheckInAt     *time.Time `json:"lastCheckInAt,omitempty" format:"date-time" example:"2024-03-25T18:45:00Z"`
+}
+
+// Offline sync outcomes
+const (
+	SyncAccepted  = "accepted"
+	SyncDuplicate = "duplicate"
+	SyncRejected  = "rejected"
+)
+
+// ManifestEntry is a booking as a scanner needs to know it offline
+type ManifestEntry struct {
+	BookingID string `json:"bookingId"`
+	// TicketVersion is the version tickets must carry; older ones were
+	// replaced by a transfer
+	TicketVersion int        `json:"ticketVersion" example:"0"`
+	Status        string     `json:"status" example:"confirmed"`
+	Quantity      int        `json:"quantity" example:"2"`
+	Attendee      string     `json:"attendee" example:"johndoe"`
+	TicketType    string     `json:"ticketType,omitempty" example:"VIP"`
+	CheckedInAt   *time.Time `json:"checkedInAt,omitempty" format:"date-time" example:"2024-03-25T18:45:00Z"`
+	// Attendees of a group booking are admitted by their own tickets only
+	Attendees []ManifestAttendee `json:"attendees,omitempty"`
+}
+
+// ManifestAttendee is a named ticket holder of a group booking
+type ManifestAttendee struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name" example:"Grace Hopper"`
+	CheckedInAt *time.Time `json:"checkedInAt,omitempty" format:"date-time" example:"2024-03-25T18:45:00Z"`
+}
+
+// Manifest lists an event's bookings for scanners to cache
+type Manifest struct {
+	EventID     string          `json:"eventId"`
+	GeneratedAt time.Time       `json:"generatedAt" format:"date-time" example:"2024-03-25T17:00:00Z"`
+	Entries     []ManifestEntry `json:"entries"`
+}
+
+// SignedManifest is a manifest signed with the e-ticket key. Payload is the
+// base64url JSON manifest and Signature the base64url Ed25519 signature of
+// the payload string.
+type SignedManifest struct {
+	Payload   string `json:"payload"`
+	Signature string `json:"signature"`
+}
+
+// OfflineScan is a ticket scanned while the scanner was offline
+type OfflineScan struct {
+	Token     string    `json:"token" binding:"required"`
+	ScannedAt time.Time `json:"scannedAt" binding:"required" format:"date-time" example:"2024-03-25T18:45:00Z"`
+}
+
+// SyncRequest uploads a scanner's offline check-ins
+type SyncRequest struct {
+	DeviceID string        `json:"deviceId" binding:"required,max=64" example:"gate-2"`
+	Scans    []OfflineScan `json:"scans" binding:"required,min=1,max=500,dive"`
+}
+
+// SyncResult is the outcome of one offline scan. Duplicates name the scan
+// that won.
+type SyncResult struct {
+	Token       string     `json:"token"`
+	BookingID   string     `json:"bookingId,omitempty"`
+	Result      string     `json:"result" example:"duplicate"`
+	Code        string     `json:"code,omitempty" example:"already_checked_in"`
+	Error       string     `json:"error,omitempty" example:"Ticket has already been used"`
+	CheckedInAt *time.Time `json:"checkedInAt,omitempty" format:"date-time" example:"2024-03-25T18:44:10Z"`
+	DeviceID    *string    `json:"deviceId,omitempty" example:"gate-1"`
+}
+
+// SyncResponse lists the outcome of every scan in the order they were sent
+type SyncResponse struct {
+	Accepted   int          `json:"accepted" example:"48"`
+	Duplicates int          `json:"duplicates" example:"2"`
+	Rejected   int          `json:"rejected" example:"1"`
+	Results    []SyncResult `json:"results"`
+}
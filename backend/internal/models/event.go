@@ -4,38 +4,149 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+
+	"online-task/pkg/money"
+)
+
+// Event lifecycle states
+const (
+	EventStatusDraft     = "draft"
+	EventStatusPublished = "published"
+	EventStatusCancelled = "cancelled"
+	EventStatusPostponed = "postponed"
+	EventStatusCompleted = "completed"
 )
 
 type Event struct {
-	ID          string         `gorm:"primarykey" json:"id"`
-	Name        string         `gorm:"not null" json:"name"`
-	Description string         `json:"description"`
-	Category    string         `json:"category"`
-	Date        time.Time      `json:"date" format:"date-time" example:"2024-03-20T15:00:00Z"`
-	Location    string         `json:"location"`
-	Price       float64        `json:"price"`
-	Image       string         `json:"image"`
-	CreatedAt   time.Time      `json:"createdAt" format:"date-time" example:"2024-03-20T10:00:00Z"`
-	UpdatedAt   time.Time      `json:"updatedAt" format:"date-time" example:"2024-03-20T10:00:00Z"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
-	Tags        []Tag          `gorm:"many2many:event_tags;" json:"tags,omitempty"`
+	ID          string `gorm:"primarykey" json:"id"`
+	Name        string `gorm:"not null" json:"name"`
+	Description string `json:"description"`
+	Category    string `json:"category"`
+	// Date is when the event starts. Date and EndDate are stored in UTC;
+	// TimeZone is used to present them in local time.
+	Date     time.Time `gorm:"index" json:"date" format:"date-time" example:"2024-03-20T15:00:00Z"`
+	EndDate  time.Time `gorm:"index" json:"endDate" format:"date-time" example:"2024-03-20T18:00:00Z"`
+	TimeZone string    `gorm:"default:UTC" json:"timeZone" example:"Europe/Berlin"`
+	// Computed after loading from TimeZone, not stored
+	LocalDate       time.Time `gorm:"-" json:"localDate" format:"date-time" example:"2024-03-20T16:00:00+01:00"`
+	LocalEndDate    time.Time `gorm:"-" json:"localEndDate" format:"date-time" example:"2024-03-20T19:00:00+01:00"`
+	DurationMinutes int       `gorm:"-" json:"durationMinutes" example:"180"`
+	VenueID         *string   `gorm:"index" json:"venueId,omitempty"`
+	Venue           *Venue    `json:"venue,omitempty"`
+	// Computed for proximity searches and venue conflict checks, not stored
+	DistanceKm     *float64        `gorm:"-" json:"distanceKm,omitempty" example:"2.4"`
+	VenueConflicts []VenueConflict `gorm:"-" json:"venueConflicts,omitempty"`
+	// SeriesID links an occurrence to the recurring series it was generated from
+	SeriesID *string `gorm:"index" json:"seriesId,omitempty"`
+	// Capacity is the number of bookings the event accepts; 0 means unlimited
+	Capacity int `gorm:"default:0" json:"capacity" example:"50"`
+	// Reserved counts the tickets of active bookings and seat holds. It is
+	// only changed by conditional updates; saving an event must omit it.
+	Reserved   int         `gorm:"not null;default:0" json:"-"`
+	Location   string      `json:"location"`
+	Price      money.Money `gorm:"embedded;embeddedPrefix:price_" json:"price"`
+	Image      string      `json:"image"`
+	Status     string      `gorm:"default:draft;index" json:"status" example:"published"`
+	StatusNote string      `json:"statusNote,omitempty" example:"Rescheduled due to weather"`
+	// CancellationPolicy decides refunds for cancelled bookings;
+	// DefaultCancellationPolicy applies when it is empty
+	CancellationPolicy []CancellationRule `gorm:"serializer:json" json:"cancellationPolicy,omitempty"`
+	// TransfersDisabled stops attendees from passing their tickets on
+	TransfersDisabled bool `gorm:"not null;default:false" json:"transfersDisabled"`
+	// MaxTicketsPerOrder limits a single booking; 0 means
+	// DefaultMaxTicketsPerOrder. MaxTicketsPerUser limits all of a user's
+	// active bookings together; 0 means unlimited.
+	MaxTicketsPerOrder int `gorm:"not null;default:0" json:"maxTicketsPerOrder" example:"10"`
+	MaxTicketsPerUser  int `gorm:"not null;default:0" json:"maxTicketsPerUser" example:"4"`
+	// PublishAt schedules a draft to be published automatically
+	PublishAt    *time.Time     `gorm:"index" json:"publishAt,omitempty" format:"date-time" example:"2024-03-01T09:00:00Z"`
+	SalesStartAt *time.Time     `json:"salesStartAt,omitempty" format:"date-time" example:"2024-03-05T09:00:00Z"`
+	SalesEndAt   *time.Time     `json:"salesEndAt,omitempty" format:"date-time" example:"2024-03-20T12:00:00Z"`
+	CreatedAt    time.Time      `json:"createdAt" format:"date-time" example:"2024-03-20T10:00:00Z"`
+	UpdatedAt    time.Time      `json:"updatedAt" format:"date-time" example:"2024-03-20T10:00:00Z"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+	Tags         []Tag          `gorm:"many2many:event_tags;" json:"tags,omitempty"`
+	Media        []EventMedia   `json:"media,omitempty"`
+	TicketTypes  []TicketType   `json:"ticketTypes,omitempty"`
+}
+
+// AfterFind fills in the local times and duration of a loaded event
+func (e *Event) AfterFind(tx *gorm.DB) error {
+	e.setLocalTimes()
+	return nil
+}
+
+// AfterSave keeps the computed times in step with a created or updated event
+func (e *Event) AfterSave(tx *gorm.DB) error {
+	e.setLocalTimes()
+	return nil
+}
+
+func (e *Event) setLocalTimes() {
+	loc, err := time.LoadLocation(e.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	e.Date = e.Date.UTC()
+	e.EndDate = e.EndDate.UTC()
+	e.LocalDate = e.Date.In(loc)
+	e.LocalEndDate = e.EndDate.In(loc)
+	e.DurationMinutes = int(e.EndDate.Sub(e.Date).Minutes())
+}
+
+// DefaultMaxTicketsPerOrder applies to events without their own order limit
+const DefaultMaxTicketsPerOrder = 20
+
+// OrderLimit is the most tickets a single booking of the event may hold
+func (e *Event) OrderLimit() int {
+	if e.MaxTicketsPerOrder > 0 {
+		return e.MaxTicketsPerOrder
+	}
+	return DefaultMaxTicketsPerOrder
 }
 
 type Tag struct {
-	ID        string         `gorm:"primarykey" json:"id"`
-	Name      string         `gorm:"unique;not null" json:"name"`
-	CreatedAt time.Time      `json:"createdAt"`
-	UpdatedAt time.Time      `json:"updatedAt"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID             string         `gorm:"primarykey" json:"id"`
+	Name           string         `gorm:"not null" json:"name"`
+	NormalizedName string         `gorm:"uniqueIndex:idx_tags_normalized_name,where:deleted_at IS NULL" json:"-"`
+	Slug           string         `gorm:"uniqueIndex:idx_tags_slug,where:deleted_at IS NULL" json:"slug" example:"live-music"`
+	ParentID       *string        `gorm:"index" json:"parentId"`
+	CreatedAt      time.Time      `json:"createdAt"`
+	UpdatedAt      time.Time      `json:"updatedAt"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+	Children       []Tag          `gorm:"foreignKey:ParentID" json:"children,omitempty"`
 }
 
 type CreateEventRequest struct {
-	Name        string    `json:"name" binding:"required"`
-	Description string    `json:"description" binding:"required"`
-	Category    string    `json:"category" binding:"required"`
-	Date        time.Time `json:"date" binding:"required" format:"date-time" example:"2024-03-20T15:00:00Z"`
-	Location    string    `json:"location" binding:"required"`
-	Price       float64   `json:"price" binding:"required,min=0"`
-	Image       string    `json:"image" binding:"required"`
-	TagIDs      []string  `json:"tagIds,omitempty"`
-} 
\ No newline at end of file
+	Name         string       `json:"name" binding:"required"`
+	Description  string       `json:"description" binding:"required"`
+	Category     string       `json:"category" binding:"required"`
+	Date         time.Time    `json:"date" binding:"required" format:"date-time" example:"2024-03-20T15:00:00Z"`
+	EndDate      time.Time    `json:"endDate" binding:"required" format:"date-time" example:"2024-03-20T18:00:00Z"`
+	TimeZone     string       `json:"timeZone,omitempty" example:"Europe/Berlin"`
+	Location     string       `json:"location" binding:"required_without=VenueID"`
+	VenueID      *string      `json:"venueId,omitempty"`
+	Price        *money.Money `json:"price" binding:"required"`
+	Image        string       `json:"image" binding:"required"`
+	Status       string       `json:"status,omitempty" binding:"omitempty,oneof=draft published" example:"draft"`
+	PublishAt    *time.Time   `json:"publishAt,omitempty" format:"date-time" example:"2024-03-01T09:00:00Z"`
+	SalesStartAt *time.Time   `json:"salesStartAt,omitempty" format:"date-time" example:"2024-03-05T09:00:00Z"`
+	SalesEndAt   *time.Time   `json:"salesEndAt,omitempty" format:"date-time" example:"2024-03-20T12:00:00Z"`
+	Capacity     int          `json:"capacity" binding:"min=0" example:"50"`
+	// CancellationPolicy replaces the default refund rules for this event
+	CancellationPolicy []CancellationRule `json:"cancellationPolicy,omitempty" binding:"omitempty,dive"`
+	TransfersDisabled  bool               `json:"transfersDisabled,omitempty"`
+	MaxTicketsPerOrder int                `json:"maxTicketsPerOrder,omitempty" binding:"min=0" example:"10"`
+	MaxTicketsPerUser  int                `json:"maxTicketsPerUser,omitempty" binding:"min=0" example:"4"`
+	// RRule makes the request create a recurring series (RFC 5545, e.g.
+	// FREQ=WEEKLY;BYDAY=TU;COUNT=10) whose first occurrence is Date to EndDate
+	RRule   string      `json:"rrule,omitempty" example:"FREQ=WEEKLY;BYDAY=TU;COUNT=10"`
+	ExDates []time.Time `json:"exdates,omitempty"`
+	TagIDs  []string    `json:"tagIds,omitempty"`
+}
+
+// EventStatusRequest carries an optional note explaining a status change,
+// shown to attendees for cancellations and postponements
+type EventStatusRequest struct {
+	Note string `json:"note" example:"Rescheduled due to weather"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Seat hold states
+const (
+	HoldStatusActive   = "active"
+	HoldStatusConsumed = "consumed"
+	HoldStatusReleased = "released"
+	HoldStatusExpired  = "expired"
+)
+
+// SeatHold sets tickets of an event aside for a user while they check out.
+// Its tickets count as taken until a booking consumes the hold, the user
+// releases it or it expires.
+type SeatHold struct {
+	ID           string  `gorm:"primarykey" json:"id"`
+	EventID      string  `gorm:"not null;index" json:"eventId"`
+	TicketTypeID *string `gorm:"index" json:"ticketTypeId,omitempty"`
+	UserID       string  `gorm:"not null;index" json:"userId"`
+	Quantity     int     `gorm:"not null" json:"quantity" example:"2"`
+	Status       string  `gorm:"not null;default:active;index" json:"status" example:"active"`
+	// ExpiresAt is when the hold's tickets are released unless booked
+	ExpiresAt time.Time `gorm:"index" json:"expiresAt" format:"date-time" example:"2024-03-20T10:10:00Z"`
+	// BookingID is the booking that consumed the hold
+	BookingID *string `json:"bookingId,omitempty"`
+	// Seats are the reserved seats the hold keeps, for seated events
+	Seats     []SeatReservation `gorm:"foreignKey:HoldID" json:"seats,omitempty"`
+	CreatedAt time.Time         `json:"createdAt" format:"date-time" example:"2024-03-20T10:00:00Z"`
+	UpdatedAt time.Time         `json:"updatedAt" format:"date-time" example:"2024-03-20T10:00:00Z"`
+}
+
+// CreateHoldRequest holds tickets of an event, or of one of its ticket types
+type CreateHoldRequest struct {
+	EventID string `json:"eventId" binding:"required"`
+	// TicketTypeID is required for events that sell ticket types
+	TicketTypeID string `json:"ticketTypeId,omitempty"`
+	Quantity     int    `json:"quantity,omitempty" binding:"omitempty,min=1,max=100" example:"2"`
+	// Seats are the seat IDs to hold at events with a seat map
+	Seats []string `json:"seats,omitempty" binding:"omitempty,max=100"`
+}
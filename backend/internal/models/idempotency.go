@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// IdempotencyKey records the response to a request sent with an
+// Idempotency-Key header, so that a retry with the same key gets the same
+// response instead of repeating the change
+type IdempotencyKey struct {
+	// Scope is who the key belongs to: a user, or a guest by their token
+	Scope string `gorm:"primaryKey"`
+	Key   string `gorm:"primaryKey;column:request_key"`
+	// Fingerprint is a hash of the request's method, path and body
+	Fingerprint string `gorm:"not null"`
+	// StatusCode is 0 while the first request is still being handled
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	ExpiresAt   time.Time `gorm:"index"`
+	CreatedAt   time.Time
+}
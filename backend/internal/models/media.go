@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+)
+
+// Event media types
+const (
+	MediaTypeImage      = "image"
+	MediaTypeAttachment = "attachment"
+)
+
+// EventMedia is a gallery image or downloadable attachment of an event
+type EventMedia struct {
+	ID        string    `gorm:"primarykey" json:"id"`
+	EventID   string    `gorm:"index;not null" json:"eventId"`
+	Type      string    `gorm:"not null" json:"type" example:"image"`
+	URL       string    `gorm:"not null" json:"url" example:"/uploads/images/1710928800000000000.jpg"`
+	Caption   string    `json:"caption"`
+	AltText   string    `json:"altText"`
+	Position  int       `json:"position"`
+	CreatedAt time.Time `json:"createdAt" format:"date-time" example:"2024-03-20T10:00:00Z"`
+	UpdatedAt time.Time `json:"updatedAt" format:"date-time" example:"2024-03-20T10:00:00Z"`
+}
+
+// AddEventMediaRequest represents the request body for attaching media to an event
+type AddEventMediaRequest struct {
+	Type    string `json:"type" binding:"required,oneof=image attachment" example:"image"`
+	URL     string `json:"url" binding:"required" example:"/uploads/images/1710928800000000000.jpg"`
+	Caption string `json:"caption" example:"Main stage"`
+	AltText string `json:"altText" example:"Crowd in front of the main stage"`
+}
+
+// UpdateEventMediaRequest represents the request body for editing media details
+type UpdateEventMediaRequest struct {
+	Caption string `json:"caption" example:"Main stage"`
+	AltText string `json:"altText" example:"Crowd in front of the main stage"`
+}
+
+// ReorderEventMediaRequest lists all media IDs of an event in their new order
+type ReorderEventMediaRequest struct {
+	MediaIDs []string `json:"mediaIds" binding:"required"`
+}
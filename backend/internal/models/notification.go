@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+)
+
+// Notification types
+const (
+	NotificationEventCancelled = "event.cancelled"
+	NotificationEventPostponed = "event.postponed"
+	NotificationTicketOffered  = "ticket.transfer_offered"
+	NotificationTicketAccepted = "ticket.transfer_accepted"
+	NotificationTicketIssued   = "ticket.issued"
+	// NotificationBookingCancelled and NotificationBookingMoved tell the
+	// holder of a booking an admin changed
+	NotificationBookingCancelled = "booking.cancelled"
+	NotificationBookingMoved     = "booking.moved"
+	// NotificationGuestBookingLink carries the magic link to a guest booking
+	NotificationGuestBookingLink = "booking.guest_link"
+)
+
+// Notification is an outbox entry for a message to a user, written in the
+// same transaction as the change that caused it and delivered afterwards
+type Notification struct {
+	ID     string `gorm:"primarykey" json:"id"`
+	Type   string `gorm:"not null;index" json:"type" example:"event.cancelled"`
+	UserID string `gorm:"index" json:"userId"`
+	// Email addresses notifications to people who may not have an account
+	Email     string     `json:"email,omitempty"`
+	EventID   string     `gorm:"index" json:"eventId,omitempty"`
+	BookingID string     `json:"bookingId,omitempty"`
+	Message   string     `json:"message"`
+	Attempts  int        `json:"-"`
+	LastError string     `json:"-"`
+	SentAt    *time.Time `gorm:"index" json:"sentAt,omitempty" format:"date-time" example:"2024-03-20T10:00:00Z"`
+	CreatedAt time.Time  `json:"createdAt" format:"date-time" example:"2024-03-20T10:00:00Z"`
+}
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"online-task/pkg/money"
+)
+
+// Promo code discount types
+const (
+	PromoTypePercent = "percent"
+	PromoTypeFixed   = "fixed"
+)
+
+// PromoCode discounts bookings by a percentage or a fixed amount. Codes can be
+// limited to some events or tags, a validity window and a number of uses.
+type PromoCode struct {
+	ID string `gorm:"primarykey" json:"id"`
+	// Code is what customers enter, stored in upper case
+	Code string `gorm:"uniqueIndex;not null" json:"code" example:"SPRING25"`
+	Type string `gorm:"not null" json:"type" example:"percent"`
+	// PercentOff is the discount of percent codes
+	PercentOff int `json:"percentOff,omitempty" example:"25"`
+	// AmountOff is the discount of fixed codes, taken off the booking total
+	AmountOff money.Money `gorm:"embedded;embeddedPrefix:amount_off_" json:"amountOff"`
+	// MaxUses and MaxUsesPerUser limit redemptions; 0 means unlimited
+	MaxUses        int        `json:"maxUses" example:"100"`
+	MaxUsesPerUser int        `json:"maxUsesPerUser" example:"1"`
+	Uses           int        `json:"uses" example:"12"`
+	ValidFrom      *time.Time `json:"validFrom,omitempty" format:"date-time" example:"2024-03-01T00:00:00Z"`
+	ValidUntil     *time.Time `json:"validUntil,omitempty" format:"date-time" example:"2024-04-01T00:00:00Z"`
+	// EventIDs and TagIDs restrict the code to those events and events with
+	// those tags; a code without either applies to every event
+	EventIDs  []string       `gorm:"serializer:json" json:"eventIds"`
+	TagIDs    []string       `gorm:"serializer:json" json:"tagIds"`
+	Active    bool           `gorm:"default:true" json:"active"`
+	CreatedAt time.Time      `json:"createdAt" format:"date-time" example:"2024-03-20T10:00:00Z"`
+	UpdatedAt time.Time      `json:"updatedAt" format:"date-time" example:"2024-03-20T10:00:00Z"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// PromoCodeRequest represents the request body for creating or updating a promo code
+type PromoCodeRequest struct {
+	Code           string       `json:"code" binding:"required,alphanum,max=32" example:"SPRING25"`
+	Type           string       `json:"type" binding:"required,oneof=percent fixed" example:"percent"`
+	PercentOff     int          `json:"percentOff" binding:"min=0,max=100" example:"25"`
+	AmountOff      *money.Money `json:"amountOff,omitempty"`
+	MaxUses        int          `json:"maxUses" binding:"min=0" example:"100"`
+	MaxUsesPerUser int          `json:"maxUsesPerUser" binding:"min=0" example:"1"`
+	ValidFrom      *time.Time   `json:"validFrom,omitempty" format:"date-time" example:"2024-03-01T00:00:00Z"`
+	ValidUntil     *time.Time   `json:"validUntil,omitempty" format:"date-time" example:"2024-04-01T00:00:00Z"`
+	EventIDs       []string     `json:"eventIds"`
+	TagIDs         []string     `json:"tagIds"`
+	Active         *bool        `json:"active,omitempty" example:"true"`
+}
+
+// ValidAt reports whether the code is active and inside its validity window
+func (p *PromoCode) ValidAt(now time.Time) bool {
+	if !p.Active {
+		return false
+	}
+	if p.ValidFrom != nil && now.Before(*p.ValidFrom) {
+		return false
+	}
+	return p.ValidUntil == nil || now.Before(*p.ValidUntil)
+}
+
+// AppliesTo reports whether the code may be used for the event, whose tags
+// must be loaded
+func (p *PromoCode) AppliesTo(event *Event) bool {
+	if len(p.EventIDs) == 0 && len(p.TagIDs) == 0 {
+		return true
+	}
+	for _, id := range p.EventIDs {
+		if id == event.ID {
+			return true
+		}
+	}
+	for _, id := range p.TagIDs {
+		for _, tag := range event.Tags {
+			if tag.ID == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Discount returns the amount taken off subtotal, which is never more than
+// subtotal. Fixed codes only discount totals in their own currency.
+func (p *PromoCode) Discount(subtotal money.Money) (money.Money, error) {
+	discount := money.New(0, subtotal.Currency)
+	switch p.Type {
+	case PromoTypePercent:
+		discount = subtotal.Percent(p.PercentOff)
+	case PromoTypeFixed:
+		if p.AmountOff.Currency != subtotal.Currency {
+			return discount, money.ErrCurrencyMismatch
+		}
+		discount = p.AmountOff
+	}
+	if discount.Amount > subtotal.Amount {
+		discount.Amount = subtotal.Amount
+	}
+	return discount, nil
+}
+
+// BookingQuote previews the price of a booking
+type BookingQuote struct {
+	EventID      string      `json:"eventId"`
+	TicketTypeID string      `json:"ticketTypeId,omitempty"`
+	Quantity     int         `json:"quantity" example:"2"`
+	UnitPrice    money.Money `json:"unitPrice"`
+	Subtotal     money.Money `json:"subtotal"`
+	PromoCode    string      `json:"promoCode,omitempty" example:"SPRING25"`
+	Discount     money.Money `json:"discount"`
+	Total        money.Money `json:"total"`
+}
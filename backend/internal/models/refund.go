@@ -0,0 +1,80 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"online-task/pkg/money"
+)
+
+// Refund states
+const (
+	RefundStatusPending   = "pending"
+	RefundStatusSucceeded = "succeeded"
+	RefundStatusFailed    = "failed"
+)
+
+// Refund reasons
+const (
+	RefundReasonCustomerCancelled = "customer_cancelled"
+	RefundReasonEventCancelled    = "event_cancelled"
+	RefundReasonLatePayment       = "late_payment"
+	RefundReasonAdminCancelled    = "admin_cancelled"
+)
+
+// CancellationRule refunds RefundPercent of the price for bookings cancelled
+// at least HoursBefore hours before the event starts
+type CancellationRule struct {
+	HoursBefore   int `json:"hoursBefore" binding:"min=0" example:"168"`
+	RefundPercent int `json:"refundPercent" binding:"min=0,max=100" example:"100"`
+}
+
+// DefaultCancellationPolicy applies to events without their own policy: a full
+// refund up to 7 days before the event, half up to 48 hours before and none after
+var DefaultCancellationPolicy = []CancellationRule{
+	{HoursBefore: 7 * 24, RefundPercent: 100},
+	{HoursBefore: 48, RefundPercent: 50},
+}
+
+// RefundPercent returns the share of the price refunded for a booking
+// cancelled at the given time, from the most generous rule that still applies
+func (e *Event) RefundPercent(now time.Time) int {
+	policy := e.CancellationPolicy
+	if len(policy) == 0 {
+		policy = DefaultCancellationPolicy
+	}
+
+	percent := 0
+	notice := e.Date.Sub(now)
+	for _, rule := range policy {
+		if notice >= time.Duration(rule.HoursBefore)*time.Hour && rule.RefundPercent > percent {
+			percent = rule.RefundPercent
+		}
+	}
+	return percent
+}
+
+// SortCancellationPolicy orders rules from the longest notice to the shortest
+func SortCancellationPolicy(policy []CancellationRule) {
+	sort.Slice(policy, func(i, j int) bool {
+		return policy[i].HoursBefore > policy[j].HoursBefore
+	})
+}
+
+// Refund is money returned for a paid booking. It is recorded before the
+// payment provider is called, and failed refunds are retried.
+type Refund struct {
+	ID        string      `gorm:"primarykey" json:"id"`
+	BookingID string      `gorm:"index;not null" json:"bookingId"`
+	UserID    string      `gorm:"index;not null" json:"userId"`
+	Amount    money.Money `gorm:"embedded;embeddedPrefix:amount_" json:"amount"`
+	// Percent is the share of the booking total refunded under the policy
+	Percent          int       `json:"percent" example:"50"`
+	Reason           string    `json:"reason" example:"customer_cancelled"`
+	Status           string    `gorm:"default:pending;index" json:"status" example:"succeeded"`
+	ProviderRefundID string    `json:"providerRefundId,omitempty" example:"re_3MtwBwLkdIwHu7ix0Z0T1pCk"`
+	Attempts         int       `json:"-"`
+	LastError        string    `json:"-"`
+	CreatedAt        time.Time `json:"createdAt" format:"date-time" example:"2024-03-20T10:00:00Z"`
+	UpdatedAt        time.Time `json:"updatedAt" format:"date-time" example:"2024-03-20T10:00:00Z"`
+}
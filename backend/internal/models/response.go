@@ -5,6 +5,12 @@ type ErrorResponse struct {
 	Error string `json:"error" example:"error message"`
 }
 
+// CodedErrorResponse is an error response with a machine-readable code
+type CodedErrorResponse struct {
+	Error string `json:"error" example:"Ticket sales have not started yet"`
+	Code  string `json:"code" example:"sales_not_started"`
+}
+
 // SuccessResponse represents a success response with a message
 type SuccessResponse struct {
 	Message string `json:"message" example:"operation successful"`
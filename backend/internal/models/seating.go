@@ -0,0 +1,93 @@
+package models
+
+import "time"
+
+// Seat states in an event's seat availability
+const (
+	SeatStateAvailable = "available"
+	SeatStateHeld      = "held"
+	SeatStateBooked    = "booked"
+)
+
+// SeatMap lays out the seats of a venue, or of an event whose layout differs
+// from its venue's. Events with a seat map, their own or their venue's, sell
+// reserved seats.
+type SeatMap struct {
+	ID        string    `gorm:"primarykey" json:"id"`
+	VenueID   *string   `gorm:"uniqueIndex" json:"venueId,omitempty"`
+	EventID   *string   `gorm:"uniqueIndex" json:"eventId,omitempty"`
+	Name      string    `gorm:"not null" json:"name" example:"Main hall"`
+	Seats     []Seat    `json:"seats"`
+	CreatedAt time.Time `json:"createdAt" format:"date-time" example:"2024-03-20T10:00:00Z"`
+	UpdatedAt time.Time `json:"updatedAt" format:"date-time" example:"2024-03-20T10:00:00Z"`
+}
+
+// Seat is one seat of a seat map
+type Seat struct {
+	ID         string `gorm:"primarykey" json:"id"`
+	SeatMapID  string `gorm:"not null;uniqueIndex:idx_seat_label" json:"-"`
+	Section    string `gorm:"not null;uniqueIndex:idx_seat_label" json:"section" example:"Stalls"`
+	Row        string `gorm:"not null;uniqueIndex:idx_seat_label" json:"row" example:"A"`
+	Number     string `gorm:"not null;uniqueIndex:idx_seat_label" json:"number" example:"12"`
+	Accessible bool   `json:"accessible"`
+	// Zone is the seat's price zone; ticket types with the same zone sell it
+	Zone     string `json:"zone,omitempty" example:"premium"`
+	Position int    `json:"-"`
+}
+
+// SeatReservation gives a seat at an event to a booking or a seat hold. Its
+// unique index on the event and seat means a seat can only be taken once.
+type SeatReservation struct {
+	ID        string    `gorm:"primarykey" json:"id"`
+	EventID   string    `gorm:"not null;uniqueIndex:idx_event_seat" json:"eventId"`
+	SeatID    string    `gorm:"not null;uniqueIndex:idx_event_seat" json:"seatId"`
+	BookingID *string   `gorm:"index" json:"bookingId,omitempty"`
+	HoldID    *string   `gorm:"index" json:"holdId,omitempty"`
+	Seat      *Seat     `json:"seat,omitempty"`
+	CreatedAt time.Time `json:"createdAt" format:"date-time" example:"2024-03-20T10:00:00Z"`
+}
+
+// SeatMapRequest defines a seat map section by section and row by row,
+// replacing any previous map
+type SeatMapRequest struct {
+	Name     string               `json:"name" binding:"required" example:"Main hall"`
+	Sections []SeatSectionRequest `json:"sections" binding:"required,min=1,dive"`
+}
+
+type SeatSectionRequest struct {
+	Name string `json:"name" binding:"required" example:"Stalls"`
+	// Zone is the price zone of the section's seats unless a row or seat
+	// sets its own
+	Zone string           `json:"zone,omitempty" example:"standard"`
+	Rows []SeatRowRequest `json:"rows" binding:"required,min=1,dive"`
+}
+
+// SeatRowRequest lists a row's seats, or numbers Count seats from 1
+type SeatRowRequest struct {
+	Label      string        `json:"label" binding:"required" example:"A"`
+	Count      int           `json:"count,omitempty" binding:"omitempty,min=1,max=500" example:"20"`
+	Seats      []SeatRequest `json:"seats,omitempty" binding:"omitempty,max=500,dive"`
+	Accessible bool          `json:"accessible,omitempty"`
+	Zone       string        `json:"zone,omitempty" example:"premium"`
+}
+
+type SeatRequest struct {
+	Number     string `json:"number" binding:"required" example:"12"`
+	Accessible bool   `json:"accessible,omitempty"`
+	Zone       string `json:"zone,omitempty" example:"premium"`
+}
+
+// SeatState is a seat with whether it can still be booked for an event
+type SeatState struct {
+	Seat
+	State string `json:"state" example:"available"`
+}
+
+// SeatAvailability is the state of every seat of an event's seat map
+type SeatAvailability struct {
+	EventID   string      `json:"eventId"`
+	SeatMapID string      `json:"seatMapId"`
+	Name      string      `json:"name" example:"Main hall"`
+	Available int         `json:"available" example:"180"`
+	Seats     []SeatState `json:"seats"`
+}
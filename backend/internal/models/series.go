@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EventSeries groups the occurrences generated from a recurrence rule. Each
+// occurrence is a regular Event with its own capacity and bookings.
+type EventSeries struct {
+	ID        string         `gorm:"primarykey" json:"id"`
+	RRule     string         `gorm:"not null" json:"rrule" example:"FREQ=WEEKLY;BYDAY=TU;COUNT=10"`
+	ExDates   []time.Time    `gorm:"serializer:json" json:"exdates,omitempty"`
+	TimeZone  string         `json:"timeZone" example:"Europe/Berlin"`
+	CreatedAt time.Time      `json:"createdAt" format:"date-time" example:"2024-03-20T10:00:00Z"`
+	UpdatedAt time.Time      `json:"updatedAt" format:"date-time" example:"2024-03-20T10:00:00Z"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	Events    []Event        `gorm:"foreignKey:SeriesID" json:"events,omitempty"`
+}
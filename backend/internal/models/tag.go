@@ -2,5 +2,23 @@ package models
 
 // CreateTagRequest represents the request body for creating a tag
 type CreateTagRequest struct {
-	Name string `json:"name" binding:"required" example:"conference"`
-} 
\ No newline at end of file
+	Name     string  `json:"name" binding:"required" example:"conference"`
+	ParentID *string `json:"parentId,omitempty" example:"5f0c7c3e-8a52-4bde-9a55-0d1f6b1c2a10"`
+}
+
+// TagWithCount is a tag together with the number of events using it
+type TagWithCount struct {
+	Tag
+	EventCount int64 `json:"eventCount" example:"12"`
+}
+
+// MergeTagRequest represents the request body for merging a tag into another
+type MergeTagRequest struct {
+	TargetID string `json:"targetId" binding:"required" example:"5f0c7c3e-8a52-4bde-9a55-0d1f6b1c2a10"`
+}
+
+// MergeTagResponse reports the result of a tag merge
+type MergeTagResponse struct {
+	Tag         Tag   `json:"tag"`
+	MovedEvents int64 `json:"movedEvents" example:"4"`
+}
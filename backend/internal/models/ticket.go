@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"online-task/pkg/money"
+)
+
+// TicketType is a kind of ticket sold for an event, such as early-bird or
+// VIP, with its own price, quota and optional sales window
+type TicketType struct {
+	ID          string      `gorm:"primarykey" json:"id"`
+	EventID     string      `gorm:"index;not null" json:"eventId"`
+	Name        string      `gorm:"not null" json:"name" example:"Early bird"`
+	Description string      `json:"description" example:"Limited discounted tickets"`
+	Price       money.Money `gorm:"embedded;embeddedPrefix:price_" json:"price"`
+	// Quota is the number of tickets of this type for sale; 0 means unlimited
+	Quota int `json:"quota" example:"100"`
+	// Sold counts tickets taken by active bookings and seat holds
+	Sold int `gorm:"not null;default:0" json:"sold" example:"42"`
+	// Zone limits the ticket type to seats of the seat map's price zone
+	Zone         string         `json:"zone,omitempty" example:"premium"`
+	SalesStartAt *time.Time     `json:"salesStartAt,omitempty" format:"date-time" example:"2024-03-01T09:00:00Z"`
+	SalesEndAt   *time.Time     `json:"salesEndAt,omitempty" format:"date-time" example:"2024-03-10T23:59:00Z"`
+	Position     int            `json:"position"`
+	CreatedAt    time.Time      `json:"createdAt" format:"date-time" example:"2024-03-20T10:00:00Z"`
+	UpdatedAt    time.Time      `json:"updatedAt" format:"date-time" example:"2024-03-20T10:00:00Z"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// CreateTicketTypeRequest represents the request body for creating or updating a
+// ticket type. The price must be in the currency of the event.
+type CreateTicketTypeRequest struct {
+	Name         string       `json:"name" binding:"required" example:"Early bird"`
+	Description  string       `json:"description" example:"Limited discounted tickets"`
+	Price        *money.Money `json:"price" binding:"required"`
+	Quota        int          `json:"quota" binding:"min=0" example:"100"`
+	Zone         string       `json:"zone,omitempty" example:"premium"`
+	SalesStartAt *time.Time   `json:"salesStartAt,omitempty" format:"date-time" example:"2024-03-01T09:00:00Z"`
+	SalesEndAt   *time.Time   `json:"salesEndAt,omitempty" format:"date-time" example:"2024-03-10T23:59:00Z"`
+	Position     int          `json:"position"`
+}
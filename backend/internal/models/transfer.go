@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Ticket transfer states
+const (
+	TransferStatusPending   = "pending"
+	TransferStatusAccepted  = "accepted"
+	TransferStatusCancelled = "cancelled"
+	TransferStatusExpired   = "expired"
+)
+
+// TicketTransfer hands a booking from its owner to the holder of an email
+// address. Accepted transfers stay as the booking's ownership history.
+type TicketTransfer struct {
+	ID         string  `gorm:"primarykey" json:"id"`
+	BookingID  string  `gorm:"not null;index" json:"bookingId"`
+	EventID    string  `gorm:"not null;index" json:"eventId"`
+	FromUserID string  `gorm:"not null;index" json:"fromUserId"`
+	ToEmail    string  `gorm:"not null;index" json:"toEmail" example:"colleague@example.com"`
+	ToUserID   *string `gorm:"index" json:"toUserId,omitempty"`
+	// TokenHash is the SHA-256 of the token sent to the recipient; the
+	// token itself is not stored
+	TokenHash   string     `gorm:"not null;uniqueIndex" json:"-"`
+	Status      string     `gorm:"not null;default:pending;index" json:"status" example:"pending"`
+	ExpiresAt   time.Time  `json:"expiresAt" format:"date-time" example:"2024-03-23T10:00:00Z"`
+	AcceptedAt  *time.Time `json:"acceptedAt,omitempty" format:"date-time" example:"2024-03-21T10:00:00Z"`
+	CancelledAt *time.Time `json:"cancelledAt,omitempty" format:"date-time" example:"2024-03-21T10:00:00Z"`
+	CreatedAt   time.Time  `json:"createdAt" format:"date-time" example:"2024-03-20T10:00:00Z"`
+	UpdatedAt   time.Time  `json:"updatedAt" format:"date-time" example:"2024-03-20T10:00:00Z"`
+	Event       *Event     `json:"event,omitempty"`
+}
+
+// CreateTransferRequest starts a transfer of a booking
+type CreateTransferRequest struct {
+	Email string `json:"email" binding:"required,email" example:"colleague@example.com"`
+}
+
+// AcceptTransferRequest claims a ticket with the token sent to the recipient
+type AcceptTransferRequest struct {
+	Token string `json:"token" binding:"required"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+)
+
+// Upload statuses
+const (
+	UploadStatusPending     = "pending"
+	UploadStatusPublished   = "published"
+	UploadStatusQuarantined = "quarantined"
+)
+
+// Upload tracks a resumable (tus) upload from creation until it is stored
+type Upload struct {
+	ID            string     `gorm:"primarykey" json:"id"`
+	UserID        string     `gorm:"index" json:"userId"`
+	Filename      string     `gorm:"not null" json:"filename"`
+	MediaType     string     `gorm:"not null" json:"mediaType" example:"video"`
+	Length        int64      `gorm:"not null" json:"length"`
+	Offset        int64      `json:"offset"`
+	Metadata      string     `json:"-"`
+	Status        string     `gorm:"default:pending" json:"status" example:"published"`
+	URL           string     `json:"url,omitempty"`
+	ScanStatus    string     `json:"scanStatus,omitempty" example:"clean"`
+	ScanSignature string     `json:"scanSignature,omitempty" example:"Eicar-Test-Signature"`
+	ExpiresAt     time.Time  `gorm:"index" json:"expiresAt" format:"date-time" example:"2024-03-21T10:00:00Z"`
+	CompletedAt   *time.Time `json:"completedAt,omitempty" format:"date-time" example:"2024-03-20T10:05:00Z"`
+	CreatedAt     time.Time  `json:"createdAt" format:"date-time" example:"2024-03-20T10:00:00Z"`
+	UpdatedAt     time.Time  `json:"updatedAt" format:"date-time" example:"2024-03-20T10:00:00Z"`
+}
@@ -6,6 +6,13 @@ import (
 	"gorm.io/gorm"
 )
 
+// User roles. Staff scan tickets at the door; admins manage everything.
+const (
+	RoleUser  = "user"
+	RoleStaff = "staff"
+	RoleAdmin = "admin"
+)
+
 type User struct {
 	ID        string         `gorm:"primarykey" json:"id"`
 	Username  string         `gorm:"unique;not null" json:"username"`
@@ -26,6 +33,14 @@ type RegisterRequest struct {
 	Username string `json:"username" binding:"required,min=3"`
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=6"`
+	// GuestToken is the token of a guest booking's magic link, when the
+	// guest registers from it
+	GuestToken string `json:"guestToken,omitempty" example:"q8J2nD4xY0..."`
+}
+
+// UpdateRoleRequest changes a user's role
+type UpdateRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user staff admin" example:"staff"`
 }
 
 type AuthResponse struct {
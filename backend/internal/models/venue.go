@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Venue is a place where events are held
+type Venue struct {
+	ID              string         `gorm:"primarykey" json:"id"`
+	Name            string         `gorm:"not null" json:"name" example:"Cairo Opera House"`
+	Address         string         `gorm:"not null" json:"address" example:"El Borg, Zamalek, Cairo"`
+	Latitude        float64        `gorm:"index:idx_venues_location" json:"latitude" example:"30.0425"`
+	Longitude       float64        `gorm:"index:idx_venues_location" json:"longitude" example:"31.2243"`
+	DefaultCapacity int            `json:"defaultCapacity" example:"1200"`
+	Accessibility   string         `json:"accessibility" example:"Step-free entrance, accessible toilets"`
+	Photos          []string       `gorm:"serializer:json" json:"photos"`
+	CreatedAt       time.Time      `json:"createdAt" format:"date-time" example:"2024-03-20T10:00:00Z"`
+	UpdatedAt       time.Time      `json:"updatedAt" format:"date-time" example:"2024-03-20T10:00:00Z"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// CreateVenueRequest represents the request body for creating or updating a venue
+type CreateVenueRequest struct {
+	Name            string   `json:"name" binding:"required" example:"Cairo Opera House"`
+	Address         string   `json:"address" binding:"required" example:"El Borg, Zamalek, Cairo"`
+	Latitude        *float64 `json:"latitude" binding:"required,min=-90,max=90" example:"30.0425"`
+	Longitude       *float64 `json:"longitude" binding:"required,min=-180,max=180" example:"31.2243"`
+	DefaultCapacity int      `json:"defaultCapacity" binding:"min=0" example:"1200"`
+	Accessibility   string   `json:"accessibility" example:"Step-free entrance, accessible toilets"`
+	Photos          []string `json:"photos" example:"/uploads/images/1710928800000000000.jpg"`
+}
+
+// VenueConflict is another event at the same venue whose time overlaps
+type VenueConflict struct {
+	EventID string    `json:"eventId"`
+	Name    string    `json:"name" example:"Jazz night"`
+	Date    time.Time `json:"date" format:"date-time" example:"2024-03-20T15:00:00Z"`
+	EndDate time.Time `json:"endDate" format:"date-time" example:"2024-03-20T18:00:00Z"`
+}
@@ -0,0 +1,98 @@
+package notification
+
+import (
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+)
+
+const (
+	maxAttempts = 5
+	batchSize   = 100
+)
+
+// Sender delivers a notification to its recipient
+type Sender interface {
+	Send(n models.Notification) error
+}
+
+// LogSender writes notifications to the application log
+type LogSender struct{}
+
+func (LogSender) Send(n models.Notification) error {
+	recipient := n.UserID
+	if n.Email != "" {
+		recipient = n.Email
+	}
+	log.Printf("Notification %s for %s: %s", n.Type, recipient, n.Message)
+	return nil
+}
+
+var sender Sender = LogSender{}
+
+// SetSender configures how queued notifications are delivered
+func SetSender(s Sender) {
+	sender = s
+}
+
+// Emit queues notifications as part of the given transaction so they are
+// only delivered if the change that caused them is committed
+func Emit(tx *gorm.DB, notifications ...models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	for i := range notifications {
+		if notifications[i].ID == "" {
+			notifications[i].ID = uuid.New().String()
+		}
+	}
+	return tx.Create(&notifications).Error
+}
+
+// deliverPending sends queued notifications and records the outcome
+func deliverPending() (int, error) {
+	var pending []models.Notification
+	if err := database.GetDB().
+		Where("sent_at IS NULL AND attempts < ?", maxAttempts).
+		Order("created_at ASC").
+		Limit(batchSize).
+		Find(&pending).Error; err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, n := range pending {
+		updates := map[string]interface{}{"attempts": n.Attempts + 1}
+		if err := sender.Send(n); err != nil {
+			log.Printf("Failed to deliver notification %s: %v", n.ID, err)
+			updates["last_error"] = err.Error()
+		} else {
+			updates["sent_at"] = time.Now()
+			sent++
+		}
+		if err := database.GetDB().Model(&models.Notification{}).Where("id = ?", n.ID).Updates(updates).Error; err != nil {
+			return sent, err
+		}
+	}
+
+	return sent, nil
+}
+
+// StartDispatcher periodically delivers queued notifications
+func StartDispatcher(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if _, err := deliverPending(); err != nil {
+				log.Printf("Failed to deliver notifications: %v", err)
+			}
+		}
+	}()
+}
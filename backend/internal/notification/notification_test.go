@@ -0,0 +1,74 @@
+package notification
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+)
+
+type recordingSender struct {
+	sent []models.Notification
+	fail bool
+}
+
+func (s *recordingSender) Send(n models.Notification) error {
+	if s.fail {
+		return errors.New("smtp unavailable")
+	}
+	s.sent = append(s.sent, n)
+	return nil
+}
+
+func setupNotificationTest(t *testing.T) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&models.Notification{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	database.DB = db
+}
+
+func TestEmitAndDeliver(t *testing.T) {
+	setupNotificationTest(t)
+	s := &recordingSender{fail: true}
+	SetSender(s)
+	defer SetSender(LogSender{})
+
+	// Notifications emitted in a rolled back transaction are never delivered
+	database.GetDB().Transaction(func(tx *gorm.DB) error {
+		Emit(tx, models.Notification{Type: models.NotificationEventCancelled, UserID: "u1"})
+		return errors.New("rollback")
+	})
+	if err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		return Emit(tx, models.Notification{Type: models.NotificationEventCancelled, UserID: "u2"})
+	}); err != nil {
+		t.Fatalf("Emit() error = %v", err)
+	}
+
+	if sent, err := deliverPending(); err != nil || sent != 0 {
+		t.Fatalf("deliverPending() with failing sender = %d, %v", sent, err)
+	}
+
+	// Failed deliveries are retried
+	s.fail = false
+	if sent, err := deliverPending(); err != nil || sent != 1 {
+		t.Fatalf("deliverPending() = %d, %v, want 1, nil", sent, err)
+	}
+	if len(s.sent) != 1 || s.sent[0].UserID != "u2" {
+		t.Errorf("unexpected deliveries: %+v", s.sent)
+	}
+
+	if sent, _ := deliverPending(); sent != 0 {
+		t.Errorf("notification delivered twice")
+	}
+}
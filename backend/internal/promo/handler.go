@@ -0,0 +1,205 @@
+package promo
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+	"online-task/pkg/money"
+)
+
+// validate checks the rules binding tags cannot express and returns an
+// error message, or an empty string if the request is valid
+func validate(req *models.PromoCodeRequest) string {
+	switch req.Type {
+	case models.PromoTypePercent:
+		if req.PercentOff == 0 {
+			return "percentOff is required for percent codes"
+		}
+	case models.PromoTypeFixed:
+		if req.AmountOff == nil || req.AmountOff.IsZero() {
+			return "amountOff is required for fixed codes"
+		}
+	}
+	if req.ValidFrom != nil && req.ValidUntil != nil && !req.ValidUntil.After(*req.ValidFrom) {
+		return "validUntil must be after validFrom"
+	}
+	return ""
+}
+
+// codeTaken reports whether another promo code, including deleted ones,
+// already uses code
+func codeTaken(db *gorm.DB, code, excludeID string) (bool, error) {
+	var count int64
+	err := db.Unscoped().Model(&models.PromoCode{}).Where("code = ? AND id <> ?", code, excludeID).Count(&count).Error
+	return count > 0, err
+}
+
+// fromRequest copies the request fields onto the promo code
+func fromRequest(promo *models.PromoCode, req *models.PromoCodeRequest) {
+	promo.Code = strings.ToUpper(req.Code)
+	promo.Type = req.Type
+	promo.PercentOff = 0
+	promo.AmountOff = money.Money{}
+	if req.Type == models.PromoTypePercent {
+		promo.PercentOff = req.PercentOff
+	} else {
+		promo.AmountOff = *req.AmountOff
+	}
+	promo.MaxUses = req.MaxUses
+	promo.MaxUsesPerUser = req.MaxUsesPerUser
+	promo.ValidFrom = req.ValidFrom
+	promo.ValidUntil = req.ValidUntil
+	promo.EventIDs = req.EventIDs
+	if promo.EventIDs == nil {
+		promo.EventIDs = []string{}
+	}
+	promo.TagIDs = req.TagIDs
+	if promo.TagIDs == nil {
+		promo.TagIDs = []string{}
+	}
+	promo.Active = req.Active == nil || *req.Active
+}
+
+// @Summary Get all promo codes
+// @Description Get all promo codes with their usage, newest first (admin only)
+// @Tags promo-codes
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {array} models.PromoCode
+// @Router /promo-codes [get]
+func GetAllPromoCodesHandler(c *gin.Context) {
+	var promos []models.PromoCode
+	if err := database.GetDB().Order("created_at DESC").Find(&promos).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch promo codes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, promos)
+}
+
+// @Summary Create a promo code
+// @Description Create a percentage or fixed-amount discount code (admin only). Codes are stored in upper case and matched ignoring case. Leaving eventIds and tagIds empty makes the code valid for every event.
+// @Tags promo-codes
+// @Accept json
+// @Produce json
+// @Param request body models.PromoCodeRequest true "Promo code details"
+// @Security Bearer
+// @Success 201 {object} models.PromoCode
+// @Failure 409 {object} models.ErrorResponse
+// @Router /promo-codes [post]
+func CreatePromoCodeHandler(c *gin.Context) {
+	var req models.PromoCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if message := validate(&req); message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return
+	}
+
+	promo := models.PromoCode{ID: uuid.New().String()}
+	fromRequest(&promo, &req)
+
+	taken, err := codeTaken(database.GetDB(), promo.Code, promo.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check promo code"})
+		return
+	}
+	if taken {
+		c.JSON(http.StatusConflict, gin.H{"error": "A promo code with this code already exists"})
+		return
+	}
+
+	// Select makes Active=false stick instead of falling back to the column default
+	if err := database.GetDB().Select("*").Create(&promo).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create promo code"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, promo)
+}
+
+// @Summary Update a promo code
+// @Description Update an existing promo code (admin only). Its use count is kept.
+// @Tags promo-codes
+// @Accept json
+// @Produce json
+// @Param id path string true "Promo code ID"
+// @Param request body models.PromoCodeRequest true "Promo code details"
+// @Security Bearer
+// @Success 200 {object} models.PromoCode
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /promo-codes/{id} [put]
+func UpdatePromoCodeHandler(c *gin.Context) {
+	var req models.PromoCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if message := validate(&req); message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return
+	}
+
+	var promo models.PromoCode
+	if err := database.GetDB().First(&promo, "id = ?", c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Promo code not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch promo code"})
+		return
+	}
+
+	fromRequest(&promo, &req)
+	taken, err := codeTaken(database.GetDB(), promo.Code, promo.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check promo code"})
+		return
+	}
+	if taken {
+		c.JSON(http.StatusConflict, gin.H{"error": "A promo code with this code already exists"})
+		return
+	}
+
+	// Uses is left out so redemptions made meanwhile are not overwritten
+	if err := database.GetDB().Model(&promo).Select("*").Omit("uses", "created_at").Updates(&promo).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update promo code"})
+		return
+	}
+
+	c.JSON(http.StatusOK, promo)
+}
+
+// @Summary Delete a promo code
+// @Description Delete a promo code (admin only). Bookings made with it keep their discount.
+// @Tags promo-codes
+// @Accept json
+// @Produce json
+// @Param id path string true "Promo code ID"
+// @Security Bearer
+// @Success 200 {object} models.SuccessResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /promo-codes/{id} [delete]
+func DeletePromoCodeHandler(c *gin.Context) {
+	result := database.GetDB().Delete(&models.PromoCode{}, "id = ?", c.Param("id"))
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete promo code"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Promo code not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: "Promo code deleted successfully"})
+}
@@ -0,0 +1,100 @@
+package promo
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+	"online-task/pkg/money"
+)
+
+func setupPromoTest(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&models.PromoCode{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	database.DB = db
+
+	r := gin.New()
+	r.GET("/api/promo-codes", GetAllPromoCodesHandler)
+	r.POST("/api/promo-codes", CreatePromoCodeHandler)
+	r.PUT("/api/promo-codes/:id", UpdatePromoCodeHandler)
+	r.DELETE("/api/promo-codes/:id", DeletePromoCodeHandler)
+	return r
+}
+
+func send(r *gin.Engine, method, path string, body interface{}) *httptest.ResponseRecorder {
+	data, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(method, path, bytes.NewReader(data)))
+	return w
+}
+
+func TestCreatePromoCodeValidation(t *testing.T) {
+	amount := money.New(500, "USD")
+
+	tests := []struct {
+		name string
+		req  models.PromoCodeRequest
+		want int
+	}{
+		{name: "percent", req: models.PromoCodeRequest{Code: "spring25", Type: models.PromoTypePercent, PercentOff: 25}, want: http.StatusCreated},
+		{name: "fixed", req: models.PromoCodeRequest{Code: "FIVE", Type: models.PromoTypeFixed, AmountOff: &amount}, want: http.StatusCreated},
+		{name: "percent without percentOff", req: models.PromoCodeRequest{Code: "ZERO", Type: models.PromoTypePercent}, want: http.StatusBadRequest},
+		{name: "fixed without amountOff", req: models.PromoCodeRequest{Code: "NONE", Type: models.PromoTypeFixed}, want: http.StatusBadRequest},
+		{name: "over 100 percent", req: models.PromoCodeRequest{Code: "MORE", Type: models.PromoTypePercent, PercentOff: 120}, want: http.StatusBadRequest},
+		{name: "unknown type", req: models.PromoCodeRequest{Code: "BOGO", Type: "bogo"}, want: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := setupPromoTest(t)
+			if w := send(r, http.MethodPost, "/api/promo-codes", tt.req); w.Code != tt.want {
+				t.Errorf("status = %d, want %d, body %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestUpdatePromoCodeKeepsUses(t *testing.T) {
+	r := setupPromoTest(t)
+
+	w := send(r, http.MethodPost, "/api/promo-codes", models.PromoCodeRequest{Code: "spring25", Type: models.PromoTypePercent, PercentOff: 25})
+	var promo models.PromoCode
+	json.Unmarshal(w.Body.Bytes(), &promo)
+	if promo.Code != "SPRING25" || !promo.Active {
+		t.Fatalf("created %+v, want an active SPRING25 code", promo)
+	}
+	if w := send(r, http.MethodPost, "/api/promo-codes", models.PromoCodeRequest{Code: "Spring25", Type: models.PromoTypePercent, PercentOff: 10}); w.Code != http.StatusConflict {
+		t.Errorf("duplicate status = %d, want 409", w.Code)
+	}
+
+	database.GetDB().Model(&models.PromoCode{}).Where("id = ?", promo.ID).Update("uses", 7)
+	inactive := false
+	w = send(r, http.MethodPut, "/api/promo-codes/"+promo.ID, models.PromoCodeRequest{Code: "SPRING30", Type: models.PromoTypePercent, PercentOff: 30, Active: &inactive})
+	if w.Code != http.StatusOK {
+		t.Fatalf("update status = %d, body %s", w.Code, w.Body.String())
+	}
+
+	var stored models.PromoCode
+	database.GetDB().First(&stored, "id = ?", promo.ID)
+	if stored.Code != "SPRING30" || stored.PercentOff != 30 || stored.Active || stored.Uses != 7 {
+		t.Errorf("stored %+v, want an inactive SPRING30 code with 7 uses", stored)
+	}
+}
@@ -0,0 +1,344 @@
+package seating
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+)
+
+var errSeatsBooked = errors.New("seat map has booked seats")
+
+// ForEvent returns the seat map an event is sold by, its own or its venue's,
+// without its seats. It returns nil for events without reserved seating.
+func ForEvent(db *gorm.DB, event *models.Event) (*models.SeatMap, error) {
+	query := db.Where("event_id = ?", event.ID)
+	if event.VenueID != nil {
+		// The event's own map comes first
+		query = query.Or("venue_id = ?", *event.VenueID).Order("event_id IS NULL")
+	}
+
+	var seatMap models.SeatMap
+	if err := query.First(&seatMap).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &seatMap, nil
+}
+
+// buildSeats lays out the seats of a seat map request, rejecting seats that
+// are listed twice
+func buildSeats(seatMapID string, req *models.SeatMapRequest) ([]models.Seat, error) {
+	var seats []models.Seat
+	labels := map[string]bool{}
+	add := func(section, row string, seat models.SeatRequest) error {
+		label := section + "\x00" + row + "\x00" + seat.Number
+		if labels[label] {
+			return errors.New("Seat " + row + seat.Number + " of " + section + " is listed twice")
+		}
+		labels[label] = true
+		seats = append(seats, models.Seat{
+			ID:         uuid.New().String(),
+			SeatMapID:  seatMapID,
+			Section:    section,
+			Row:        row,
+			Number:     seat.Number,
+			Accessible: seat.Accessible,
+			Zone:       seat.Zone,
+			Position:   len(seats),
+		})
+		return nil
+	}
+
+	for _, section := range req.Sections {
+		for _, row := range section.Rows {
+			if (row.Count == 0) == (len(row.Seats) == 0) {
+				return nil, errors.New("Row " + row.Label + " of " + section.Name + " needs either a count or its seats")
+			}
+			zone := row.Zone
+			if zone == "" {
+				zone = section.Zone
+			}
+
+			rowSeats := row.Seats
+			for n := 1; n <= row.Count; n++ {
+				rowSeats = append(rowSeats, models.SeatRequest{Number: strconv.Itoa(n), Accessible: row.Accessible})
+			}
+			for _, seat := range rowSeats {
+				if seat.Zone == "" {
+					seat.Zone = zone
+				}
+				seat.Accessible = seat.Accessible || row.Accessible
+				if err := add(strings.TrimSpace(section.Name), strings.TrimSpace(row.Label), seat); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return seats, nil
+}
+
+// hasBookedSeats reports whether any seat of the map is held or booked
+func hasBookedSeats(tx *gorm.DB, seatMapID string) (bool, error) {
+	var taken int64
+	err := tx.Model(&models.SeatReservation{}).
+		Where("seat_id IN (?)", tx.Model(&models.Seat{}).Select("id").Where("seat_map_id = ?", seatMapID)).
+		Count(&taken).Error
+	return taken > 0, err
+}
+
+// deleteSeatMap removes a seat map and its seats, failing with errSeatsBooked
+// while any of them is taken
+func deleteSeatMap(tx *gorm.DB, seatMap *models.SeatMap) error {
+	booked, err := hasBookedSeats(tx, seatMap.ID)
+	if err != nil {
+		return err
+	}
+	if booked {
+		return errSeatsBooked
+	}
+	if err := tx.Where("seat_map_id = ?", seatMap.ID).Delete(&models.Seat{}).Error; err != nil {
+		return err
+	}
+	return tx.Delete(seatMap).Error
+}
+
+// saveSeatMap replaces the seat map of a venue or event with the request's
+func saveSeatMap(c *gin.Context, venueID, eventID *string) {
+	var req models.SeatMapRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	seatMap := models.SeatMap{ID: uuid.New().String(), VenueID: venueID, EventID: eventID, Name: req.Name}
+	seats, err := buildSeats(seatMap.ID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		var previous models.SeatMap
+		err := owned(tx, venueID, eventID).First(&previous).Error
+		if err == nil {
+			err = deleteSeatMap(tx, &previous)
+		}
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return err
+		}
+
+		if err := tx.Create(&seatMap).Error; err != nil {
+			return err
+		}
+		return tx.CreateInBatches(&seats, 500).Error
+	})
+	if err == errSeatsBooked {
+		c.JSON(http.StatusConflict, gin.H{"error": "Seats of the current seat map are booked"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save seat map"})
+		return
+	}
+
+	seatMap.Seats = seats
+	c.JSON(http.StatusOK, seatMap)
+}
+
+// removeSeatMap deletes the seat map of a venue or event
+func removeSeatMap(c *gin.Context, venueID, eventID *string) {
+	err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		var seatMap models.SeatMap
+		if err := owned(tx, venueID, eventID).First(&seatMap).Error; err != nil {
+			return err
+		}
+		return deleteSeatMap(tx, &seatMap)
+	})
+	if err == gorm.ErrRecordNotFound {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Seat map not found"})
+		return
+	}
+	if err == errSeatsBooked {
+		c.JSON(http.StatusConflict, gin.H{"error": "Seats of the seat map are booked"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete seat map"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Seat map deleted successfully"})
+}
+
+func owned(db *gorm.DB, venueID, eventID *string) *gorm.DB {
+	if venueID != nil {
+		return db.Where("venue_id = ?", *venueID)
+	}
+	return db.Where("event_id = ?", *eventID)
+}
+
+// exists responds 404 unless a record with the path's id exists
+func exists(c *gin.Context, model interface{}, name string) bool {
+	if err := database.GetDB().First(model, "id = ?", c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": name + " not found"})
+			return false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch " + strings.ToLower(name)})
+		return false
+	}
+	return true
+}
+
+// @Summary Get a venue's seat map
+// @Description Get the seat map of a venue with its seats in order
+// @Tags seating
+// @Produce json
+// @Param id path string true "Venue ID"
+// @Success 200 {object} models.SeatMap
+// @Failure 404 {object} models.ErrorResponse
+// @Router /venues/{id}/seat-map [get]
+func GetVenueSeatMapHandler(c *gin.Context) {
+	var seatMap models.SeatMap
+	if err := database.GetDB().Preload("Seats", func(db *gorm.DB) *gorm.DB { return db.Order("position") }).
+		First(&seatMap, "venue_id = ?", c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Seat map not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch seat map"})
+		return
+	}
+
+	c.JSON(http.StatusOK, seatMap)
+}
+
+// @Summary Set a venue's seat map
+// @Description Define the sections, rows and seats of a venue, with accessible seats and price zones (admin only). Events at the venue sell reserved seats from it unless they have their own map. The map can only be replaced while none of its seats are held or booked.
+// @Tags seating
+// @Accept json
+// @Produce json
+// @Param id path string true "Venue ID"
+// @Param request body models.SeatMapRequest true "Seat map"
+// @Security Bearer
+// @Success 200 {object} models.SeatMap
+// @Failure 409 {object} models.ErrorResponse
+// @Router /venues/{id}/seat-map [put]
+func SaveVenueSeatMapHandler(c *gin.Context) {
+	var venue models.Venue
+	if exists(c, &venue, "Venue") {
+		saveSeatMap(c, &venue.ID, nil)
+	}
+}
+
+// @Summary Delete a venue's seat map
+// @Description Remove the seat map of a venue while none of its seats are held or booked (admin only)
+// @Tags seating
+// @Produce json
+// @Param id path string true "Venue ID"
+// @Security Bearer
+// @Success 200 {object} models.SuccessResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /venues/{id}/seat-map [delete]
+func DeleteVenueSeatMapHandler(c *gin.Context) {
+	id := c.Param("id")
+	removeSeatMap(c, &id, nil)
+}
+
+// @Summary Set an event's seat map
+// @Description Give an event a seat map of its own, in place of its venue's (admin only). The map can only be replaced while none of its seats are held or booked.
+// @Tags seating
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param request body models.SeatMapRequest true "Seat map"
+// @Security Bearer
+// @Success 200 {object} models.SeatMap
+// @Failure 409 {object} models.ErrorResponse
+// @Router /events/{id}/seat-map [put]
+func SaveEventSeatMapHandler(c *gin.Context) {
+	var event models.Event
+	if exists(c, &event, "Event") {
+		saveSeatMap(c, nil, &event.ID)
+	}
+}
+
+// @Summary Delete an event's seat map
+// @Description Remove an event's own seat map while none of its seats are held or booked, falling back to its venue's (admin only)
+// @Tags seating
+// @Produce json
+// @Param id path string true "Event ID"
+// @Security Bearer
+// @Success 200 {object} models.SuccessResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /events/{id}/seat-map [delete]
+func DeleteEventSeatMapHandler(c *gin.Context) {
+	id := c.Param("id")
+	removeSeatMap(c, nil, &id)
+}
+
+// @Summary Get seat availability
+// @Description Get every seat of the event's seat map with whether it is available, held during someone's checkout or booked
+// @Tags seating
+// @Produce json
+// @Param id path string true "Event ID"
+// @Success 200 {object} models.SeatAvailability
+// @Failure 404 {object} models.ErrorResponse
+// @Router /events/{id}/seats [get]
+func GetEventSeatsHandler(c *gin.Context) {
+	var event models.Event
+	if !exists(c, &event, "Event") {
+		return
+	}
+
+	seatMap, err := ForEvent(database.GetDB(), &event)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch seat map"})
+		return
+	}
+	if seatMap == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event does not have reserved seating"})
+		return
+	}
+
+	var seats []models.Seat
+	var reservations []models.SeatReservation
+	if err := database.GetDB().Order("position").Find(&seats, "seat_map_id = ?", seatMap.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch seats"})
+		return
+	}
+	if err := database.GetDB().Find(&reservations, "event_id = ?", event.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reserved seats"})
+		return
+	}
+
+	states := make(map[string]string, len(reservations))
+	for _, r := range reservations {
+		states[r.SeatID] = models.SeatStateBooked
+		if r.BookingID == nil {
+			states[r.SeatID] = models.SeatStateHeld
+		}
+	}
+
+	availability := models.SeatAvailability{EventID: event.ID, SeatMapID: seatMap.ID, Name: seatMap.Name, Seats: make([]models.SeatState, 0, len(seats))}
+	for _, seat := range seats {
+		state, taken := states[seat.ID]
+		if !taken {
+			state = models.SeatStateAvailable
+			availability.Available++
+		}
+		availability.Seats = append(availability.Seats, models.SeatState{Seat: seat, State: state})
+	}
+
+	c.JSON(http.StatusOK, availability)
+}
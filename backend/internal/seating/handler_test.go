@@ -0,0 +1,170 @@
+package seating
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+)
+
+func setupSeatingTest(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&models.Venue{}, &models.Event{}, &models.SeatMap{}, &models.Seat{}, &models.SeatReservation{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	database.DB = db
+
+	venueID := "hall"
+	db.Create(&models.Venue{ID: venueID, Name: "Hall", Address: "1 Main St"})
+	db.Create(&models.Event{ID: "concert", Name: "Concert", VenueID: &venueID})
+	db.Create(&models.Event{ID: "elsewhere", Name: "Elsewhere"})
+
+	r := gin.New()
+	r.GET("/api/venues/:id/seat-map", GetVenueSeatMapHandler)
+	r.PUT("/api/venues/:id/seat-map", SaveVenueSeatMapHandler)
+	r.DELETE("/api/venues/:id/seat-map", DeleteVenueSeatMapHandler)
+	r.PUT("/api/events/:id/seat-map", SaveEventSeatMapHandler)
+	r.DELETE("/api/events/:id/seat-map", DeleteEventSeatMapHandler)
+	r.GET("/api/events/:id/seats", GetEventSeatsHandler)
+	return r
+}
+
+func saveMap(r *gin.Engine, path string, req models.SeatMapRequest) (*httptest.ResponseRecorder, models.SeatMap) {
+	body, _ := json.Marshal(req)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, path, bytes.NewReader(body)))
+
+	var seatMap models.SeatMap
+	json.Unmarshal(w.Body.Bytes(), &seatMap)
+	return w, seatMap
+}
+
+func TestSaveSeatMap(t *testing.T) {
+	r := setupSeatingTest(t)
+
+	tests := []struct {
+		name       string
+		req        models.SeatMapRequest
+		wantStatus int
+		wantSeats  int
+	}{
+		{
+			name: "rows by count and by seat",
+			req: models.SeatMapRequest{Name: "Main", Sections: []models.SeatSectionRequest{
+				{Name: "Stalls", Zone: "standard", Rows: []models.SeatRowRequest{
+					{Label: "A", Count: 4, Zone: "premium"},
+					{Label: "B", Seats: []models.SeatRequest{{Number: "1", Accessible: true}, {Number: "2"}}},
+				}},
+				{Name: "Balcony", Rows: []models.SeatRowRequest{{Label: "A", Count: 3}}},
+			}},
+			wantStatus: http.StatusOK,
+			wantSeats:  9,
+		},
+		{
+			name: "row without seats",
+			req: models.SeatMapRequest{Name: "Main", Sections: []models.SeatSectionRequest{
+				{Name: "Stalls", Rows: []models.SeatRowRequest{{Label: "A"}}},
+			}},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "seat listed twice",
+			req: models.SeatMapRequest{Name: "Main", Sections: []models.SeatSectionRequest{
+				{Name: "Stalls", Rows: []models.SeatRowRequest{{Label: "A", Seats: []models.SeatRequest{{Number: "1"}, {Number: "1"}}}}},
+			}},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, seatMap := saveMap(r, "/api/venues/hall/seat-map", tt.req)
+			if w.Code != tt.wantStatus || len(seatMap.Seats) != tt.wantSeats {
+				t.Errorf("got %d with %d seats, want %d with %d, body %s", w.Code, len(seatMap.Seats), tt.wantStatus, tt.wantSeats, w.Body.String())
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/venues/hall/seat-map", nil))
+	var seatMap models.SeatMap
+	json.Unmarshal(w.Body.Bytes(), &seatMap)
+	if w.Code != http.StatusOK || len(seatMap.Seats) != 9 {
+		t.Fatalf("got %d with %d seats, want the saved map", w.Code, len(seatMap.Seats))
+	}
+	first, accessible := seatMap.Seats[0], seatMap.Seats[4]
+	if first.Section != "Stalls" || first.Row != "A" || first.Number != "1" || first.Zone != "premium" {
+		t.Errorf("first seat = %+v, want Stalls A1 in the row's zone", first)
+	}
+	if !accessible.Accessible || accessible.Zone != "standard" {
+		t.Errorf("seat B1 = %+v, want accessible in the section's zone", accessible)
+	}
+
+	if w, _ := saveMap(r, "/api/venues/missing/seat-map", models.SeatMapRequest{Name: "Main", Sections: []models.SeatSectionRequest{
+		{Name: "Stalls", Rows: []models.SeatRowRequest{{Label: "A", Count: 1}}},
+	}}); w.Code != http.StatusNotFound {
+		t.Errorf("unknown venue: status = %d, want 404", w.Code)
+	}
+}
+
+func TestEventSeats(t *testing.T) {
+	r := setupSeatingTest(t)
+	_, venueMap := saveMap(r, "/api/venues/hall/seat-map", models.SeatMapRequest{Name: "Main", Sections: []models.SeatSectionRequest{
+		{Name: "Stalls", Rows: []models.SeatRowRequest{{Label: "A", Count: 3}}},
+	}})
+
+	bookingID, holdID := "booking", "hold"
+	database.GetDB().Create(&models.SeatReservation{ID: "r1", EventID: "concert", SeatID: venueMap.Seats[0].ID, BookingID: &bookingID})
+	database.GetDB().Create(&models.SeatReservation{ID: "r2", EventID: "concert", SeatID: venueMap.Seats[1].ID, HoldID: &holdID})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/events/concert/seats", nil))
+	var availability models.SeatAvailability
+	json.Unmarshal(w.Body.Bytes(), &availability)
+	if w.Code != http.StatusOK || availability.SeatMapID != venueMap.ID || availability.Available != 1 || len(availability.Seats) != 3 {
+		t.Fatalf("got %d %+v, want the venue's map with one seat available", w.Code, availability)
+	}
+	for i, want := range []string{models.SeatStateBooked, models.SeatStateHeld, models.SeatStateAvailable} {
+		if got := availability.Seats[i].State; got != want {
+			t.Errorf("seat %d state = %s, want %s", i, got, want)
+		}
+	}
+
+	if w, _ := saveMap(r, "/api/venues/hall/seat-map", models.SeatMapRequest{Name: "New", Sections: []models.SeatSectionRequest{
+		{Name: "Stalls", Rows: []models.SeatRowRequest{{Label: "A", Count: 5}}},
+	}}); w.Code != http.StatusConflict {
+		t.Errorf("replacing a booked map: status = %d, want 409", w.Code)
+	}
+
+	// The event's own map takes the place of the venue's
+	_, eventMap := saveMap(r, "/api/events/concert/seat-map", models.SeatMapRequest{Name: "Standing", Sections: []models.SeatSectionRequest{
+		{Name: "Floor", Rows: []models.SeatRowRequest{{Label: "1", Count: 2}}},
+	}})
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/events/concert/seats", nil))
+	json.Unmarshal(w.Body.Bytes(), &availability)
+	if availability.SeatMapID != eventMap.ID || availability.Available != 2 {
+		t.Errorf("got map %s with %d available, want the event's own map", availability.SeatMapID, availability.Available)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/events/elsewhere/seats", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("event without seating: status = %d, want 404", w.Code)
+	}
+}
@@ -1,7 +1,9 @@
 package tag
 
 import (
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -9,16 +11,111 @@ import (
 
 	"online-task/internal/models"
 	"online-task/pkg/database"
+	"online-task/pkg/slug"
 )
 
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// uniqueSlug derives a slug from the name, adding a numeric suffix when
+// another tag already uses it
+func uniqueSlug(db *gorm.DB, name, excludeID string) (string, error) {
+	base := slug.Make(name)
+	if base == "" {
+		base = "tag"
+	}
+
+	candidate := base
+	for i := 2; ; i++ {
+		var count int64
+		if err := db.Model(&models.Tag{}).Where("slug = ? AND id <> ?", candidate, excludeID).Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// nameTaken reports whether another tag has the same name ignoring case
+func nameTaken(db *gorm.DB, name, excludeID string) (bool, error) {
+	var count int64
+	err := db.Model(&models.Tag{}).Where("normalized_name = ? AND id <> ?", normalizeName(name), excludeID).Count(&count).Error
+	return count > 0, err
+}
+
+// DescendantIDs returns the ID of the tag and of every tag below it in the hierarchy
+func DescendantIDs(db *gorm.DB, id string) ([]string, error) {
+	var ids []string
+	err := db.Raw(`
+		WITH RECURSIVE tree(id) AS (
+			SELECT id FROM tags WHERE id = ? AND deleted_at IS NULL
+			UNION
+			SELECT tags.id FROM tags JOIN tree ON tags.parent_id = tree.id WHERE tags.deleted_at IS NULL
+		)
+		SELECT id FROM tree`, id).Scan(&ids).Error
+	return ids, err
+}
+
+// FindTag looks a tag up by ID or slug
+func FindTag(db *gorm.DB, idOrSlug string) (*models.Tag, error) {
+	var tag models.Tag
+	if err := db.First(&tag, "id = ? OR slug = ?", idOrSlug, idOrSlug).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// validateParent checks that the parent exists and would not create a cycle
+func validateParent(db *gorm.DB, tagID string, parentID *string) (int, string) {
+	if parentID == nil {
+		return 0, ""
+	}
+
+	var parent models.Tag
+	if err := db.First(&parent, "id = ?", *parentID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return http.StatusBadRequest, "Parent tag not found"
+		}
+		return http.StatusInternalServerError, "Failed to fetch parent tag"
+	}
+
+	if tagID == "" {
+		return 0, ""
+	}
+	descendants, err := DescendantIDs(db, tagID)
+	if err != nil {
+		return http.StatusInternalServerError, "Failed to fetch tag hierarchy"
+	}
+	for _, id := range descendants {
+		if id == parent.ID {
+			return http.StatusBadRequest, "A tag cannot be moved below itself or one of its children"
+		}
+	}
+	return 0, ""
+}
+
 // @Summary Get all tags
-// @Description Get a list of all tags
+// @Description Get a flat list of all tags, optionally with the number of events using each
 // @Tags tags
 // @Accept json
 // @Produce json
-// @Success 200 {array} models.Tag
+// @Param withCounts query bool false "Include eventCount for each tag"
+// @Success 200 {array} models.TagWithCount
 // @Router /tags [get]
 func GetAllTagsHandler(c *gin.Context) {
+	if c.Query("withCounts") == "true" {
+		tags := []models.TagWithCount{}
+		if err := withCounts(database.GetDB()).Order("tags.name ASC").Scan(&tags).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tags"})
+			return
+		}
+		c.JSON(http.StatusOK, tags)
+		return
+	}
+
 	var tags []models.Tag
 	if err := database.GetDB().Find(&tags).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tags"})
@@ -28,19 +125,67 @@ func GetAllTagsHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, tags)
 }
 
+// @Summary Get tag tree
+// @Description Get all tags nested under their parents
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Tag
+// @Router /tags/tree [get]
+func GetTagTreeHandler(c *gin.Context) {
+	var tags []models.Tag
+	if err := database.GetDB().Order("name ASC").Find(&tags).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tags"})
+		return
+	}
+
+	c.JSON(http.StatusOK, buildTree(tags))
+}
+
+// buildTree nests tags under their parents. Tags whose parent no longer
+// exists are returned at the top level.
+func buildTree(tags []models.Tag) []models.Tag {
+	byID := make(map[string]bool, len(tags))
+	children := make(map[string][]models.Tag)
+	for _, tag := range tags {
+		byID[tag.ID] = true
+	}
+
+	var roots []models.Tag
+	for _, tag := range tags {
+		if tag.ParentID != nil && byID[*tag.ParentID] {
+			children[*tag.ParentID] = append(children[*tag.ParentID], tag)
+			continue
+		}
+		roots = append(roots, tag)
+	}
+
+	var attach func(nodes []models.Tag) []models.Tag
+	attach = func(nodes []models.Tag) []models.Tag {
+		for i := range nodes {
+			nodes[i].Children = attach(children[nodes[i].ID])
+		}
+		return nodes
+	}
+
+	result := attach(roots)
+	if result == nil {
+		result = []models.Tag{}
+	}
+	return result
+}
+
 // @Summary Get tag by ID
-// @Description Get details of a specific tag
+// @Description Get details of a specific tag by ID or slug
 // @Tags tags
 // @Accept json
 // @Produce json
-// @Param id path string true "Tag ID"
+// @Param id path string true "Tag ID or slug"
 // @Success 200 {object} models.Tag
 // @Router /tags/{id} [get]
 func GetTagHandler(c *gin.Context) {
-	id := c.Param("id")
-	var tag models.Tag
-
-	if err := database.GetDB().First(&tag, "id = ?", id).Error; err != nil {
+	tag, err := FindTag(database.GetDB(), c.Param("id"))
+	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
 			return
@@ -53,13 +198,14 @@ func GetTagHandler(c *gin.Context) {
 }
 
 // @Summary Create a new tag
-// @Description Create a new tag (admin only)
+// @Description Create a new tag, optionally below a parent tag (admin only). Names are unique ignoring case.
 // @Tags tags
 // @Accept json
 // @Produce json
 // @Param request body models.CreateTagRequest true "Tag details"
 // @Security Bearer
 // @Success 201 {object} models.Tag
+// @Failure 409 {object} models.ErrorResponse
 // @Router /tags [post]
 func CreateTagHandler(c *gin.Context) {
 	var req models.CreateTagRequest
@@ -68,12 +214,37 @@ func CreateTagHandler(c *gin.Context) {
 		return
 	}
 
+	db := database.GetDB()
+	if status, message := validateParent(db, "", req.ParentID); status != 0 {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
+
+	taken, err := nameTaken(db, req.Name, "")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check tag name"})
+		return
+	}
+	if taken {
+		c.JSON(http.StatusConflict, gin.H{"error": "A tag with this name already exists"})
+		return
+	}
+
+	tagSlug, err := uniqueSlug(db, req.Name, "")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate slug"})
+		return
+	}
+
 	tag := models.Tag{
-		ID:   uuid.New().String(),
-		Name: req.Name,
+		ID:             uuid.New().String(),
+		Name:           strings.TrimSpace(req.Name),
+		NormalizedName: normalizeName(req.Name),
+		Slug:           tagSlug,
+		ParentID:       req.ParentID,
 	}
 
-	if err := database.GetDB().Create(&tag).Error; err != nil {
+	if err := db.Create(&tag).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create tag"})
 		return
 	}
@@ -82,7 +253,7 @@ func CreateTagHandler(c *gin.Context) {
 }
 
 // @Summary Update a tag
-// @Description Update an existing tag (admin only)
+// @Description Rename a tag or move it below another parent (admin only). The slug follows the new name.
 // @Tags tags
 // @Accept json
 // @Produce json
@@ -90,6 +261,7 @@ func CreateTagHandler(c *gin.Context) {
 // @Param request body models.CreateTagRequest true "Tag details"
 // @Security Bearer
 // @Success 200 {object} models.Tag
+// @Failure 409 {object} models.ErrorResponse
 // @Router /tags/{id} [put]
 func UpdateTagHandler(c *gin.Context) {
 	id := c.Param("id")
@@ -99,8 +271,9 @@ func UpdateTagHandler(c *gin.Context) {
 		return
 	}
 
+	db := database.GetDB()
 	var tag models.Tag
-	if err := database.GetDB().First(&tag, "id = ?", id).Error; err != nil {
+	if err := db.First(&tag, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
 			return
@@ -109,9 +282,34 @@ func UpdateTagHandler(c *gin.Context) {
 		return
 	}
 
-	tag.Name = req.Name
+	if status, message := validateParent(db, tag.ID, req.ParentID); status != 0 {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
+
+	taken, err := nameTaken(db, req.Name, tag.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check tag name"})
+		return
+	}
+	if taken {
+		c.JSON(http.StatusConflict, gin.H{"error": "A tag with this name already exists"})
+		return
+	}
+
+	if normalizeName(req.Name) != tag.NormalizedName || tag.Slug == "" {
+		tagSlug, err := uniqueSlug(db, req.Name, tag.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate slug"})
+			return
+		}
+		tag.Slug = tagSlug
+	}
+	tag.Name = strings.TrimSpace(req.Name)
+	tag.NormalizedName = normalizeName(req.Name)
+	tag.ParentID = req.ParentID
 
-	if err := database.GetDB().Save(&tag).Error; err != nil {
+	if err := db.Save(&tag).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update tag"})
 		return
 	}
@@ -120,17 +318,39 @@ func UpdateTagHandler(c *gin.Context) {
 }
 
 // @Summary Delete a tag
-// @Description Delete an existing tag (admin only)
+// @Description Delete an existing tag (admin only). Its children move up to the deleted tag's parent. A tag that is still used by events is only deleted with force=true; consider merging it instead.
 // @Tags tags
 // @Accept json
 // @Produce json
 // @Param id path string true "Tag ID"
+// @Param force query bool false "Unlink the tag from its events and delete it anyway"
 // @Security Bearer
 // @Success 200 {object} models.SuccessResponse
+// @Failure 409 {object} models.TagWithCount
 // @Router /tags/{id} [delete]
 func DeleteTagHandler(c *gin.Context) {
 	id := c.Param("id")
 
+	var tag models.Tag
+	if err := database.GetDB().First(&tag, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tag"})
+		return
+	}
+
+	count, err := eventCount(database.GetDB(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count tag usage"})
+		return
+	}
+	if count > 0 && c.Query("force") != "true" {
+		c.JSON(http.StatusConflict, gin.H{"error": "Tag is used by events", "eventCount": count})
+		return
+	}
+
 	// Start transaction
 	tx := database.GetDB().Begin()
 
@@ -141,6 +361,13 @@ func DeleteTagHandler(c *gin.Context) {
 		return
 	}
 
+	// Re-attach children to the grandparent
+	if err := tx.Model(&models.Tag{}).Where("parent_id = ?", id).Update("parent_id", tag.ParentID).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to move child tags"})
+		return
+	}
+
 	// Delete the tag
 	if err := tx.Where("id = ?", id).Delete(&models.Tag{}).Error; err != nil {
 		tx.Rollback()
@@ -150,4 +377,4 @@ func DeleteTagHandler(c *gin.Context) {
 
 	tx.Commit()
 	c.JSON(http.StatusOK, models.SuccessResponse{Message: "Tag deleted successfully"})
-} 
\ No newline at end of file
+}
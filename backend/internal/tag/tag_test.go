@@ -0,0 +1,222 @@
+package tag
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+)
+
+func setupTagTest(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&models.Event{}, &models.Tag{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	database.DB = db
+
+	r := gin.New()
+	r.GET("/api/tags/tree", GetTagTreeHandler)
+	r.GET("/api/tags/:id", GetTagHandler)
+	r.GET("/api/tags", GetAllTagsHandler)
+	r.GET("/api/tags/autocomplete", AutocompleteTagsHandler)
+	r.POST("/api/tags", CreateTagHandler)
+	r.PUT("/api/tags/:id", UpdateTagHandler)
+	r.DELETE("/api/tags/:id", DeleteTagHandler)
+	r.POST("/api/tags/:id/merge", MergeTagHandler)
+	return r
+}
+
+func createTag(t *testing.T, r *gin.Engine, name string, parentID *string) (models.Tag, int) {
+	t.Helper()
+	body, _ := json.Marshal(models.CreateTagRequest{Name: name, ParentID: parentID})
+	req := httptest.NewRequest(http.MethodPost, "/api/tags", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var tag models.Tag
+	json.Unmarshal(w.Body.Bytes(), &tag)
+	return tag, w.Code
+}
+
+func TestCreateTagSlugAndCaseInsensitiveName(t *testing.T) {
+	r := setupTagTest(t)
+
+	music, code := createTag(t, r, "Live Music", nil)
+	if code != http.StatusCreated || music.Slug != "live-music" {
+		t.Fatalf("create: got status %d, slug %q", code, music.Slug)
+	}
+
+	if _, code := createTag(t, r, "live music", nil); code != http.StatusConflict {
+		t.Errorf("duplicate name with different case: got status %d, want %d", code, http.StatusConflict)
+	}
+
+	other, code := createTag(t, r, "Live-Music!", nil)
+	if code != http.StatusCreated || other.Slug != "live-music-2" {
+		t.Errorf("colliding slug: got status %d, slug %q", code, other.Slug)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tags/live-music", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("get by slug: got status %d", w.Code)
+	}
+}
+
+func TestTagHierarchy(t *testing.T) {
+	r := setupTagTest(t)
+
+	music, _ := createTag(t, r, "Music", nil)
+	jazz, _ := createTag(t, r, "Jazz", &music.ID)
+	bebop, _ := createTag(t, r, "Bebop", &jazz.ID)
+	createTag(t, r, "Sports", nil)
+
+	ids, err := DescendantIDs(database.GetDB(), music.ID)
+	if err != nil {
+		t.Fatalf("DescendantIDs() error = %v", err)
+	}
+	if len(ids) != 3 {
+		t.Errorf("DescendantIDs() = %v, want music, jazz and bebop", ids)
+	}
+
+	// Moving a tag below its own descendant must be rejected
+	body, _ := json.Marshal(models.CreateTagRequest{Name: "Music", ParentID: &bebop.ID})
+	req := httptest.NewRequest(http.MethodPut, "/api/tags/"+music.ID, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("cyclic move: got status %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tags/tree", nil))
+	var tree []models.Tag
+	if err := json.Unmarshal(w.Body.Bytes(), &tree); err != nil {
+		t.Fatalf("failed to decode tree: %v", err)
+	}
+	if len(tree) != 2 || tree[0].Name != "Music" || len(tree[0].Children) != 1 || len(tree[0].Children[0].Children) != 1 {
+		t.Errorf("unexpected tree: %+v", tree)
+	}
+}
+
+func createTaggedEvent(t *testing.T, tags ...models.Tag) {
+	t.Helper()
+	event := models.Event{ID: uuid.New().String(), Name: "Event"}
+	if err := database.GetDB().Create(&event).Error; err != nil {
+		t.Fatalf("failed to create event: %v", err)
+	}
+	if err := database.GetDB().Model(&event).Association("Tags").Append(tags); err != nil {
+		t.Fatalf("failed to tag event: %v", err)
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	r := setupTagTest(t)
+
+	music, _ := createTag(t, r, "Music", nil)
+	musik, _ := createTag(t, r, "Musik", nil)
+	child, _ := createTag(t, r, "Techno", &musik.ID)
+	createTaggedEvent(t, music, musik)
+	createTaggedEvent(t, musik)
+
+	body, _ := json.Marshal(models.MergeTagRequest{TargetID: music.ID})
+	req := httptest.NewRequest(http.MethodPost, "/api/tags/"+musik.ID+"/merge", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("merge: got status %d, body %s", w.Code, w.Body.String())
+	}
+
+	var resp models.MergeTagResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.MovedEvents != 1 {
+		t.Errorf("movedEvents = %d, want 1", resp.MovedEvents)
+	}
+
+	if count, _ := eventCount(database.GetDB(), music.ID); count != 2 {
+		t.Errorf("target event count = %d, want 2", count)
+	}
+	if count, _ := eventCount(database.GetDB(), musik.ID); count != 0 {
+		t.Errorf("source event count = %d, want 0", count)
+	}
+
+	var moved models.Tag
+	database.GetDB().First(&moved, "id = ?", child.ID)
+	if moved.ParentID == nil || *moved.ParentID != music.ID {
+		t.Errorf("child tag was not moved below the target")
+	}
+	if _, err := FindTag(database.GetDB(), musik.ID); err == nil {
+		t.Error("source tag still exists after merge")
+	}
+}
+
+func TestTagCountsAndAutocomplete(t *testing.T) {
+	r := setupTagTest(t)
+
+	music, _ := createTag(t, r, "Music", nil)
+	museum, _ := createTag(t, r, "Museum", nil)
+	createTag(t, r, "Mud_Run", nil)
+	createTag(t, r, "Sports", nil)
+	createTaggedEvent(t, museum)
+	createTaggedEvent(t, museum)
+	createTaggedEvent(t, music)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tags?withCounts=true", nil))
+	var all []models.TagWithCount
+	json.Unmarshal(w.Body.Bytes(), &all)
+	counts := map[string]int64{}
+	for _, tag := range all {
+		counts[tag.Name] = tag.EventCount
+	}
+	if len(all) != 4 || counts["Museum"] != 2 || counts["Music"] != 1 || counts["Sports"] != 0 {
+		t.Errorf("unexpected counts: %v", counts)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tags/autocomplete?q=MU", nil))
+	var suggestions []models.TagWithCount
+	json.Unmarshal(w.Body.Bytes(), &suggestions)
+	if len(suggestions) != 3 || suggestions[0].Name != "Museum" || suggestions[1].Name != "Music" {
+		t.Errorf("unexpected suggestions: %+v", suggestions)
+	}
+
+	// Underscores in the prefix match literally
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tags/autocomplete?q=mu_", nil))
+	json.Unmarshal(w.Body.Bytes(), &suggestions)
+	if len(suggestions) != 0 {
+		t.Errorf("expected no suggestions for literal underscore, got %+v", suggestions)
+	}
+
+	// Deleting a tag in use requires force
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/tags/"+museum.ID, nil))
+	if w.Code != http.StatusConflict {
+		t.Errorf("delete used tag: got status %d, want %d", w.Code, http.StatusConflict)
+	}
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/tags/"+museum.ID+"?force=true", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("forced delete: got status %d, want %d", w.Code, http.StatusOK)
+	}
+}
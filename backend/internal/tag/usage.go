@@ -0,0 +1,155 @@
+package tag
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+)
+
+const (
+	defaultAutocompleteLimit = 10
+	maxAutocompleteLimit     = 50
+)
+
+// withCounts selects tags together with the number of events linked to each
+func withCounts(db *gorm.DB) *gorm.DB {
+	return db.Model(&models.Tag{}).
+		Select("tags.*, COUNT(events.id) AS event_count").
+		Joins("LEFT JOIN event_tags ON event_tags.tag_id = tags.id").
+		Joins("LEFT JOIN events ON events.id = event_tags.event_id AND events.deleted_at IS NULL").
+		Group("tags.id")
+}
+
+// eventCount returns how many events use the tag
+func eventCount(db *gorm.DB, id string) (int64, error) {
+	var count int64
+	err := db.Table("event_tags").
+		Joins("JOIN events ON events.id = event_tags.event_id AND events.deleted_at IS NULL").
+		Where("event_tags.tag_id = ?", id).
+		Count(&count).Error
+	return count, err
+}
+
+// @Summary Autocomplete tags
+// @Description Find tags whose name starts with the given prefix, most used first
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param q query string true "Name prefix"
+// @Param limit query int false "Maximum number of results (default 10, max 50)"
+// @Success 200 {array} models.TagWithCount
+// @Router /tags/autocomplete [get]
+func AutocompleteTagsHandler(c *gin.Context) {
+	prefix := normalizeName(c.Query("q"))
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultAutocompleteLimit)))
+	if err != nil || limit <= 0 {
+		limit = defaultAutocompleteLimit
+	}
+	if limit > maxAutocompleteLimit {
+		limit = maxAutocompleteLimit
+	}
+
+	// Escape LIKE wildcards so they match literally
+	pattern := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(prefix) + "%"
+
+	tags := []models.TagWithCount{}
+	if err := withCounts(database.GetDB()).
+		Where(`tags.normalized_name LIKE ? ESCAPE '\'`, pattern).
+		Order("event_count DESC, tags.name ASC").
+		Limit(limit).
+		Scan(&tags).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tags"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// @Summary Merge tags
+// @Description Move every event of a tag to the target tag and delete it, in one transaction (admin only). Child tags move below the target.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the tag to merge away"
+// @Param request body models.MergeTagRequest true "Target tag"
+// @Security Bearer
+// @Success 200 {object} models.MergeTagResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /tags/{id}/merge [post]
+func MergeTagHandler(c *gin.Context) {
+	id := c.Param("id")
+	var req models.MergeTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.TargetID == id {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A tag cannot be merged into itself"})
+		return
+	}
+
+	db := database.GetDB()
+	var source, target models.Tag
+	if err := db.First(&source, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tag"})
+		return
+	}
+	if err := db.First(&target, "id = ?", req.TargetID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Target tag not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tag"})
+		return
+	}
+
+	descendants, err := DescendantIDs(db, source.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tag hierarchy"})
+		return
+	}
+	for _, descendantID := range descendants {
+		if descendantID == target.ID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "A tag cannot be merged into one of its children"})
+			return
+		}
+	}
+
+	var moved int64
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// Link the source's events to the target, skipping events that already have it
+		result := tx.Exec(`INSERT INTO event_tags (event_id, tag_id)
+			SELECT event_id, ? FROM event_tags
+			WHERE tag_id = ? AND event_id NOT IN (SELECT event_id FROM event_tags WHERE tag_id = ?)`,
+			target.ID, source.ID, target.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		moved = result.RowsAffected
+
+		if err := tx.Table("event_tags").Where("tag_id = ?", source.ID).Delete(&struct{}{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Tag{}).Where("parent_id = ?", source.ID).Update("parent_id", target.ID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&source).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge tags"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.MergeTagResponse{Tag: target, MovedEvents: moved})
+}
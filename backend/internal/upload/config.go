@@ -0,0 +1,108 @@
+package upload
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultUploadExpiry = 24 * time.Hour
+	mb                  = 1024 * 1024
+)
+
+var (
+	// uploadRoot is served publicly under /uploads
+	uploadRoot = "./uploads"
+	// partialDir holds incomplete resumable uploads outside the public root
+	partialDir = "./data/tus"
+	// stagingDir holds direct uploads while they are scanned
+	stagingDir = "./data/staging"
+	// quarantineDir keeps files that failed the malware scan away from the public root
+	quarantineDir = "./data/quarantine"
+)
+
+// mediaKind describes a class of files that may be uploaded. The size limit
+// for each kind can be overridden with UPLOAD_MAX_<NAME>_MB.
+type mediaKind struct {
+	Name           string
+	Dir            string
+	Extensions     []string
+	DefaultMaxSize int64
+}
+
+var mediaKinds = []mediaKind{
+	{Name: "image", Dir: "images", Extensions: []string{".jpg", ".jpeg", ".png", ".gif"}, DefaultMaxSize: 3 * mb},
+	{Name: "video", Dir: "videos", Extensions: []string{".mp4", ".webm", ".mov"}, DefaultMaxSize: 500 * mb},
+	{Name: "document", Dir: "documents", Extensions: []string{".pdf"}, DefaultMaxSize: 50 * mb},
+}
+
+// MaxSize returns the configured size limit in bytes
+func (k mediaKind) MaxSize() int64 {
+	value := os.Getenv("UPLOAD_MAX_" + strings.ToUpper(k.Name) + "_MB")
+	if value == "" {
+		return k.DefaultMaxSize
+	}
+	size, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || size <= 0 {
+		return k.DefaultMaxSize
+	}
+	return size * mb
+}
+
+func (k mediaKind) allows(filename string) bool {
+	ext := strings.ToLower(filepath.Ext(filename))
+	for _, allowed := range k.Extensions {
+		if ext == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// kindForFile returns the media kind matching the file extension
+func kindForFile(filename string) (mediaKind, bool) {
+	for _, kind := range mediaKinds {
+		if kind.allows(filename) {
+			return kind, true
+		}
+	}
+	return mediaKind{}, false
+}
+
+func kindByName(name string) (mediaKind, bool) {
+	for _, kind := range mediaKinds {
+		if kind.Name == name {
+			return kind, true
+		}
+	}
+	return mediaKind{}, false
+}
+
+// maxUploadSize returns the largest limit across all media kinds
+func maxUploadSize() int64 {
+	var max int64
+	for _, kind := range mediaKinds {
+		if size := kind.MaxSize(); size > max {
+			max = size
+		}
+	}
+	return max
+}
+
+// uploadExpiry returns how long an incomplete resumable upload is kept,
+// configurable with UPLOAD_EXPIRY_HOURS
+func uploadExpiry() time.Duration {
+	hours, err := strconv.Atoi(os.Getenv("UPLOAD_EXPIRY_HOURS"))
+	if err != nil || hours <= 0 {
+		return defaultUploadExpiry
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+func formatSize(size int64) string {
+	return fmt.Sprintf("%dMB", size/mb)
+}
@@ -1,8 +1,10 @@
 package upload
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -10,29 +12,149 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-)
 
-const (
-	maxFileSize    = 3 * 1024 * 1024 // 3MB
-	uploadDir      = "./uploads/images"
-	allowedFormats = ".jpg,.jpeg,.png,.gif"
+	"online-task/pkg/scanner"
 )
 
 type UploadResponse struct {
-	ImageURL string `json:"imageUrl"`
+	ImageURL string         `json:"imageUrl"`
+	Scan     scanner.Result `json:"scan"`
 }
 
 type ErrorResponse struct {
 	Error string `json:"error"`
 }
 
-func validateFileType(filename string) bool {
-	ext := strings.ToLower(filepath.Ext(filename))
-	return strings.Contains(allowedFormats, ext)
+// ScanErrorResponse is returned when an upload is quarantined
+type ScanErrorResponse struct {
+	Error string         `json:"error" example:"File failed malware scan"`
+	Scan  scanner.Result `json:"scan"`
+}
+
+var (
+	errInfected   = errors.New("File failed malware scan")
+	errScanFailed = errors.New("Malware scan unavailable")
+)
+
+// fileScanner checks every upload before it is published
+var fileScanner scanner.Scanner = scanner.NoopScanner{}
+
+// SetScanner configures the malware scanner used for uploads
+func SetScanner(s scanner.Scanner) {
+	fileScanner = s
+}
+
+// validateFile checks the file against the extension list and size limit of its media kind
+func validateFile(kind mediaKind, filename string, size int64) error {
+	if !kind.allows(filename) {
+		return fmt.Errorf("Invalid file format. Allowed formats: %s", strings.ReplaceAll(strings.Join(kind.Extensions, ", "), ".", ""))
+	}
+	if size > kind.MaxSize() {
+		return fmt.Errorf("File size exceeds %s limit", formatSize(kind.MaxSize()))
+	}
+	return nil
+}
+
+// moveFile renames src to dst, falling back to a copy when they are on
+// different volumes
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	in.Close()
+	return os.Remove(src)
+}
+
+// storedFile is the outcome of publishing an upload
+type storedFile struct {
+	URL  string
+	Scan scanner.Result
+}
+
+// publishFile scans a staged file and moves it under the public upload
+// directory of its media kind. Files that are infected, or could not be
+// scanned, are moved to quarantine instead and never served.
+func publishFile(stagedPath string, kind mediaKind, filename string) (storedFile, error) {
+	var stored storedFile
+
+	file, err := os.Open(stagedPath)
+	if err != nil {
+		return stored, err
+	}
+	stored.Scan, err = fileScanner.Scan(file)
+	file.Close()
+
+	// Generate unique filename
+	newFilename := fmt.Sprintf("%d%s", time.Now().UnixNano(), strings.ToLower(filepath.Ext(filename)))
+
+	if err != nil || stored.Scan.Status == scanner.StatusInfected {
+		log.Printf("Quarantined upload %q as %s: scan status %s, signature %q, error %v",
+			filename, newFilename, stored.Scan.Status, stored.Scan.Signature, err)
+		if mkErr := os.MkdirAll(quarantineDir, os.ModePerm); mkErr != nil {
+			return stored, mkErr
+		}
+		if mvErr := moveFile(stagedPath, filepath.Join(quarantineDir, newFilename)); mvErr != nil {
+			return stored, mvErr
+		}
+		if err != nil {
+			return stored, errScanFailed
+		}
+		return stored, errInfected
+	}
+	log.Printf("Scanned upload %q with %s: %s", filename, stored.Scan.Engine, stored.Scan.Status)
+
+	dir := filepath.Join(uploadRoot, kind.Dir)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return stored, err
+	}
+	if err := moveFile(stagedPath, filepath.Join(dir, newFilename)); err != nil {
+		return stored, err
+	}
+
+	stored.URL = fmt.Sprintf("/uploads/%s/%s", kind.Dir, newFilename)
+	return stored, nil
+}
+
+// stageFile copies an incoming file outside the public root so it can be scanned
+func stageFile(src io.Reader) (string, error) {
+	if err := os.MkdirAll(stagingDir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	dst, err := os.CreateTemp(stagingDir, "upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, src); err != nil {
+		os.Remove(dst.Name())
+		return "", err
+	}
+	return dst.Name(), nil
 }
 
 // @Summary Upload an image
-// @Description Upload an image file (max 3MB, formats: jpg, jpeg, png, gif)
+// @Description Upload an image file (max 3MB by default, formats: jpg, jpeg, png, gif). Files are scanned for malware before they are published.
 // @Tags upload
 // @Accept multipart/form-data
 // @Produce json
@@ -40,15 +162,11 @@ func validateFileType(filename string) bool {
 // @Security Bearer
 // @Success 200 {object} UploadResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 422 {object} ScanErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ScanErrorResponse
 // @Router /upload/image [post]
 func UploadImageHandler(c *gin.Context) {
-	// Create uploads directory if it doesn't exist
-	if err := os.MkdirAll(uploadDir, os.ModePerm); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create upload directory"})
-		return
-	}
-
 	file, header, err := c.Request.FormFile("image")
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "No file uploaded"})
@@ -56,38 +174,31 @@ func UploadImageHandler(c *gin.Context) {
 	}
 	defer file.Close()
 
-	// Check file size
-	if header.Size > maxFileSize {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "File size exceeds 3MB limit"})
-		return
-	}
-
-	// Validate file type
-	if !validateFileType(header.Filename) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file format. Allowed formats: jpg, jpeg, png, gif"})
+	kind, _ := kindByName("image")
+	if err := validateFile(kind, header.Filename, header.Size); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Generate unique filename
-	ext := filepath.Ext(header.Filename)
-	newFilename := fmt.Sprintf("%d%s", time.Now().UnixNano(), ext)
-	filepath := filepath.Join(uploadDir, newFilename)
-
-	// Create new file
-	dst, err := os.Create(filepath)
+	stagedPath, err := stageFile(file)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create file"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save file"})
 		return
 	}
-	defer dst.Close()
 
-	// Copy file content
-	if _, err := io.Copy(dst, file); err != nil {
+	stored, err := publishFile(stagedPath, kind, header.Filename)
+	switch {
+	case err == errInfected:
+		c.JSON(http.StatusUnprocessableEntity, ScanErrorResponse{Error: err.Error(), Scan: stored.Scan})
+		return
+	case err == errScanFailed:
+		c.JSON(http.StatusServiceUnavailable, ScanErrorResponse{Error: err.Error(), Scan: stored.Scan})
+		return
+	case err != nil:
+		os.Remove(stagedPath)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save file"})
 		return
 	}
 
-	// Return the URL
-	imageURL := fmt.Sprintf("/uploads/images/%s", newFilename)
-	c.JSON(http.StatusOK, UploadResponse{ImageURL: imageURL})
+	c.JSON(http.StatusOK, UploadResponse{ImageURL: stored.URL, Scan: stored.Scan})
 }
@@ -0,0 +1,503 @@
+package upload
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+	"online-task/pkg/scanner"
+)
+
+// Resumable uploads following the tus 1.0.0 protocol (https://tus.io/protocols/resumable-upload)
+// with the creation, expiration and termination extensions.
+const (
+	tusVersion     = "1.0.0"
+	tusExtensions  = "creation,expiration,termination"
+	tusContentType = "application/offset+octet-stream"
+	tusBasePath    = "/api/upload/tus/"
+)
+
+// TusHeaders lists the request and response headers a browser client needs
+// to be allowed to send and read through CORS
+var TusHeaders = []string{
+	"Tus-Resumable", "Tus-Version", "Tus-Extension", "Tus-Max-Size",
+	"Upload-Length", "Upload-Offset", "Upload-Metadata", "Upload-Expires", "Location",
+}
+
+// uploadLocks serialises PATCH requests for the same upload
+var uploadLocks sync.Map
+
+func lockUpload(id string) func() {
+	value, _ := uploadLocks.LoadOrStore(id, &sync.Mutex{})
+	mu := value.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+func partialPath(id string) string {
+	return filepath.Join(partialDir, id+".part")
+}
+
+func tusError(c *gin.Context, status int, message string) {
+	c.Header("Tus-Resumable", tusVersion)
+	c.AbortWithStatusJSON(status, gin.H{"error": message})
+}
+
+// checkTusResumable rejects requests from clients speaking another protocol version
+func checkTusResumable(c *gin.Context) bool {
+	if c.GetHeader("Tus-Resumable") != tusVersion {
+		c.Header("Tus-Version", tusVersion)
+		tusError(c, http.StatusPreconditionFailed, "Unsupported tus version")
+		return false
+	}
+	return true
+}
+
+// parseUploadMetadata decodes the Upload-Metadata header ("key base64value,...")
+func parseUploadMetadata(header string) (map[string]string, error) {
+	metadata := make(map[string]string)
+	if strings.TrimSpace(header) == "" {
+		return metadata, nil
+	}
+	for _, pair := range strings.Split(header, ",") {
+		parts := strings.Fields(pair)
+		if len(parts) == 0 || len(parts) > 2 {
+			return nil, errors.New("malformed Upload-Metadata header")
+		}
+		value := ""
+		if len(parts) == 2 {
+			decoded, err := base64.StdEncoding.DecodeString(parts[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid base64 value for metadata key %q", parts[0])
+			}
+			value = string(decoded)
+		}
+		metadata[parts[0]] = value
+	}
+	return metadata, nil
+}
+
+// findUpload loads an upload and rejects ones that have expired
+func findUpload(c *gin.Context) (*models.Upload, bool) {
+	var upload models.Upload
+	if err := database.GetDB().First(&upload, "id = ?", c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			tusError(c, http.StatusNotFound, "Upload not found")
+			return nil, false
+		}
+		tusError(c, http.StatusInternalServerError, "Failed to fetch upload")
+		return nil, false
+	}
+	if upload.CompletedAt == nil && time.Now().After(upload.ExpiresAt) {
+		tusError(c, http.StatusGone, "Upload has expired")
+		return nil, false
+	}
+	return &upload, true
+}
+
+func setUploadHeaders(c *gin.Context, upload *models.Upload) {
+	c.Header("Tus-Resumable", tusVersion)
+	c.Header("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	c.Header("Upload-Length", strconv.FormatInt(upload.Length, 10))
+	if upload.CompletedAt == nil {
+		c.Header("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	}
+	c.Header("Cache-Control", "no-store")
+}
+
+// @Summary Resumable upload capabilities
+// @Description Report the supported tus version, extensions and maximum upload size
+// @Tags upload
+// @Success 204
+// @Router /upload/tus [options]
+func TusOptionsHandler(c *gin.Context) {
+	c.Header("Tus-Resumable", tusVersion)
+	c.Header("Tus-Version", tusVersion)
+	c.Header("Tus-Extension", tusExtensions)
+	c.Header("Tus-Max-Size", strconv.FormatInt(maxUploadSize(), 10))
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Create a resumable upload
+// @Description Start a tus upload. Upload-Metadata must contain a base64 encoded filename; its extension selects the media type and size limit.
+// @Tags upload
+// @Param Tus-Resumable header string true "Protocol version" default(1.0.0)
+// @Param Upload-Length header int true "Total size in bytes"
+// @Param Upload-Metadata header string true "tus metadata, e.g. filename dmlkZW8ubXA0"
+// @Security Bearer
+// @Success 201
+// @Failure 400 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Router /upload/tus [post]
+func TusCreateHandler(c *gin.Context) {
+	if !checkTusResumable(c) {
+		return
+	}
+
+	if c.GetHeader("Upload-Defer-Length") != "" {
+		tusError(c, http.StatusBadRequest, "Deferred upload length is not supported")
+		return
+	}
+	length, err := strconv.ParseInt(c.GetHeader("Upload-Length"), 10, 64)
+	if err != nil || length <= 0 {
+		tusError(c, http.StatusBadRequest, "A positive Upload-Length header is required")
+		return
+	}
+
+	metadata, err := parseUploadMetadata(c.GetHeader("Upload-Metadata"))
+	if err != nil {
+		tusError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	filename := filepath.Base(metadata["filename"])
+	if metadata["filename"] == "" {
+		tusError(c, http.StatusBadRequest, "Upload-Metadata must include a filename")
+		return
+	}
+
+	kind, ok := kindForFile(filename)
+	if !ok {
+		tusError(c, http.StatusBadRequest, "Unsupported file format")
+		return
+	}
+	if err := validateFile(kind, filename, length); err != nil {
+		tusError(c, http.StatusRequestEntityTooLarge, err.Error())
+		return
+	}
+
+	if err := os.MkdirAll(partialDir, os.ModePerm); err != nil {
+		tusError(c, http.StatusInternalServerError, "Failed to create upload directory")
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	upload := models.Upload{
+		ID:        uuid.New().String(),
+		UserID:    fmt.Sprint(userID),
+		Filename:  filename,
+		MediaType: kind.Name,
+		Length:    length,
+		Metadata:  c.GetHeader("Upload-Metadata"),
+		ExpiresAt: time.Now().Add(uploadExpiry()),
+	}
+
+	file, err := os.Create(partialPath(upload.ID))
+	if err != nil {
+		tusError(c, http.StatusInternalServerError, "Failed to create file")
+		return
+	}
+	file.Close()
+
+	if err := database.GetDB().Create(&upload).Error; err != nil {
+		os.Remove(partialPath(upload.ID))
+		tusError(c, http.StatusInternalServerError, "Failed to create upload")
+		return
+	}
+
+	c.Header("Location", tusBasePath+upload.ID)
+	setUploadHeaders(c, &upload)
+	c.Status(http.StatusCreated)
+}
+
+// @Summary Resumable upload status
+// @Description Return the current offset of a tus upload in the Upload-Offset header
+// @Tags upload
+// @Param id path string true "Upload ID"
+// @Param Tus-Resumable header string true "Protocol version" default(1.0.0)
+// @Security Bearer
+// @Success 200
+// @Failure 404 {object} ErrorResponse
+// @Failure 410 {object} ErrorResponse
+// @Router /upload/tus/{id} [head]
+func TusHeadHandler(c *gin.Context) {
+	if !checkTusResumable(c) {
+		return
+	}
+
+	upload, ok := findUpload(c)
+	if !ok {
+		return
+	}
+
+	setUploadHeaders(c, upload)
+	c.Status(http.StatusOK)
+}
+
+// @Summary Append to a resumable upload
+// @Description Write the request body at Upload-Offset. Once all bytes have arrived the file is validated and scanned, then published or quarantined. A file that no longer passes validation, e.g. after its size limit was lowered, is refused with 400 or 413 and the upload discarded.
+// @Tags upload
+// @Accept application/offset+octet-stream
+// @Param id path string true "Upload ID"
+// @Param Tus-Resumable header string true "Protocol version" default(1.0.0)
+// @Param Upload-Offset header int true "Offset the body starts at"
+// @Security Bearer
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 410 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Failure 422 {object} ScanErrorResponse
+// @Failure 503 {object} ScanErrorResponse
+// @Router /upload/tus/{id} [patch]
+func TusPatchHandler(c *gin.Context) {
+	if !checkTusResumable(c) {
+		return
+	}
+	if c.ContentType() != tusContentType {
+		tusError(c, http.StatusUnsupportedMediaType, "Content-Type must be "+tusContentType)
+		return
+	}
+	offset, err := strconv.ParseInt(c.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		tusError(c, http.StatusBadRequest, "A valid Upload-Offset header is required")
+		return
+	}
+
+	unlock := lockUpload(c.Param("id"))
+	defer unlock()
+
+	upload, ok := findUpload(c)
+	if !ok {
+		return
+	}
+	if offset != upload.Offset {
+		tusError(c, http.StatusConflict, fmt.Sprintf("Upload-Offset mismatch, current offset is %d", upload.Offset))
+		return
+	}
+	if upload.CompletedAt != nil {
+		setUploadHeaders(c, upload)
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	remaining := upload.Length - upload.Offset
+	if c.Request.ContentLength > remaining {
+		tusError(c, http.StatusRequestEntityTooLarge, "Request body exceeds Upload-Length")
+		return
+	}
+
+	file, err := os.OpenFile(partialPath(upload.ID), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		tusError(c, http.StatusInternalServerError, "Failed to open upload")
+		return
+	}
+	// Whatever arrived before a dropped connection is kept so the client can resume
+	written, copyErr := io.Copy(file, io.LimitReader(c.Request.Body, remaining))
+	file.Close()
+
+	upload.Offset += written
+	if err := database.GetDB().Model(upload).Update("offset", upload.Offset).Error; err != nil {
+		tusError(c, http.StatusInternalServerError, "Failed to update upload")
+		return
+	}
+	if copyErr != nil {
+		tusError(c, http.StatusInternalServerError, "Failed to save upload chunk")
+		return
+	}
+
+	if upload.Offset == upload.Length {
+		var rejected *rejectedUpload
+		scan, err := completeUpload(upload)
+		switch {
+		case err == errInfected || err == errScanFailed:
+			status := http.StatusUnprocessableEntity
+			if err == errScanFailed {
+				status = http.StatusServiceUnavailable
+			}
+			setUploadHeaders(c, upload)
+			c.JSON(status, ScanErrorResponse{Error: err.Error(), Scan: scan})
+			return
+		case errors.As(err, &rejected):
+			// The upload cannot be resumed into a valid file, so its data goes
+			discardUpload(upload)
+			tusError(c, rejected.status, err.Error())
+			return
+		case err != nil:
+			tusError(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	setUploadHeaders(c, upload)
+	c.Status(http.StatusNoContent)
+}
+
+// rejectedUpload is a finished upload that fails validation, e.g. because
+// its media kind's size limit was lowered while it was being sent
+type rejectedUpload struct {
+	status int
+	err    error
+}
+
+func (e *rejectedUpload) Error() string {
+	return e.err.Error()
+}
+
+// completeUpload moves a finished upload through the same validation, scanning
+// and storage path as direct uploads
+func completeUpload(upload *models.Upload) (scanner.Result, error) {
+	kind, ok := kindByName(upload.MediaType)
+	if !ok {
+		return scanner.Result{}, &rejectedUpload{http.StatusBadRequest, errors.New("Unsupported file format")}
+	}
+	if err := validateFile(kind, upload.Filename, upload.Length); err != nil {
+		status := http.StatusRequestEntityTooLarge
+		if !kind.allows(upload.Filename) {
+			status = http.StatusBadRequest
+		}
+		return scanner.Result{}, &rejectedUpload{status, err}
+	}
+
+	stored, publishErr := publishFile(partialPath(upload.ID), kind, upload.Filename)
+	if publishErr != nil && publishErr != errInfected && publishErr != errScanFailed {
+		return stored.Scan, errors.New("Failed to save file")
+	}
+
+	now := time.Now()
+	upload.CompletedAt = &now
+	upload.URL = stored.URL
+	upload.ScanStatus = stored.Scan.Status
+	upload.ScanSignature = stored.Scan.Signature
+	upload.Status = models.UploadStatusPublished
+	if publishErr != nil {
+		upload.Status = models.UploadStatusQuarantined
+	}
+
+	if err := database.GetDB().Model(upload).Updates(map[string]interface{}{
+		"status":         upload.Status,
+		"url":            upload.URL,
+		"scan_status":    upload.ScanStatus,
+		"scan_signature": upload.ScanSignature,
+		"completed_at":   upload.CompletedAt,
+	}).Error; err != nil {
+		return stored.Scan, errors.New("Failed to update upload")
+	}
+
+	return stored.Scan, publishErr
+}
+
+// discardUpload deletes an incomplete upload and its data. The caller holds
+// the upload's lock.
+func discardUpload(upload *models.Upload) error {
+	if err := database.GetDB().Delete(upload).Error; err != nil {
+		return err
+	}
+	os.Remove(partialPath(upload.ID))
+	uploadLocks.Delete(upload.ID)
+	return nil
+}
+
+// @Summary Terminate a resumable upload
+// @Description Discard an incomplete tus upload and its data
+// @Tags upload
+// @Param id path string true "Upload ID"
+// @Param Tus-Resumable header string true "Protocol version" default(1.0.0)
+// @Security Bearer
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Router /upload/tus/{id} [delete]
+func TusDeleteHandler(c *gin.Context) {
+	if !checkTusResumable(c) {
+		return
+	}
+
+	unlock := lockUpload(c.Param("id"))
+	defer unlock()
+
+	upload, ok := findUpload(c)
+	if !ok {
+		return
+	}
+	if upload.CompletedAt != nil {
+		tusError(c, http.StatusForbidden, "Completed uploads cannot be terminated")
+		return
+	}
+
+	if err := discardUpload(upload); err != nil {
+		tusError(c, http.StatusInternalServerError, "Failed to delete upload")
+		return
+	}
+
+	c.Header("Tus-Resumable", tusVersion)
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Get resumable upload
+// @Description Get progress of a tus upload and the URL of the stored file once complete
+// @Tags upload
+// @Produce json
+// @Param id path string true "Upload ID"
+// @Security Bearer
+// @Success 200 {object} models.Upload
+// @Failure 404 {object} ErrorResponse
+// @Router /upload/tus/{id} [get]
+func GetUploadHandler(c *gin.Context) {
+	var upload models.Upload
+	if err := database.GetDB().First(&upload, "id = ?", c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Upload not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch upload"})
+		return
+	}
+
+	c.JSON(http.StatusOK, upload)
+}
+
+// removeExpiredUploads deletes incomplete uploads past their expiry together with their data
+func removeExpiredUploads() (int, error) {
+	var expired []models.Upload
+	if err := database.GetDB().Where("completed_at IS NULL AND expires_at < ?", time.Now()).Find(&expired).Error; err != nil {
+		return 0, err
+	}
+
+	for _, upload := range expired {
+		unlock := lockUpload(upload.ID)
+		if err := database.GetDB().Delete(&upload).Error; err != nil {
+			unlock()
+			return 0, err
+		}
+		os.Remove(partialPath(upload.ID))
+		unlock()
+		uploadLocks.Delete(upload.ID)
+	}
+
+	return len(expired), nil
+}
+
+// StartExpiredUploadCleaner periodically removes expired incomplete uploads
+func StartExpiredUploadCleaner(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			removed, err := removeExpiredUploads()
+			if err != nil {
+				log.Printf("Failed to remove expired uploads: %v", err)
+				continue
+			}
+			if removed > 0 {
+				log.Printf("Removed %d expired uploads", removed)
+			}
+		}
+	}()
+}
@@ -0,0 +1,266 @@
+package upload
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+	"online-task/pkg/scanner"
+)
+
+// infectedScanner flags every file as infected
+type infectedScanner struct{}
+
+func (infectedScanner) Scan(r io.Reader) (scanner.Result, error) {
+	return scanner.Result{Status: scanner.StatusInfected, Signature: "Eicar-Test-Signature", Engine: "test"}, nil
+}
+
+func setupTusTest(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Upload{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	database.DB = db
+
+	dir := t.TempDir()
+	uploadRoot = filepath.Join(dir, "uploads")
+	partialDir = filepath.Join(dir, "tus")
+	quarantineDir = filepath.Join(dir, "quarantine")
+	SetScanner(scanner.NoopScanner{})
+
+	r := gin.New()
+	r.OPTIONS("/api/upload/tus", TusOptionsHandler)
+	r.POST("/api/upload/tus", TusCreateHandler)
+	r.HEAD("/api/upload/tus/:id", TusHeadHandler)
+	r.PATCH("/api/upload/tus/:id", TusPatchHandler)
+	r.DELETE("/api/upload/tus/:id", TusDeleteHandler)
+	r.GET("/api/upload/tus/:id", GetUploadHandler)
+	return r
+}
+
+func tusRequest(r *gin.Engine, method, path string, body []byte, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+	req.Header.Set("Tus-Resumable", tusVersion)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func createUpload(t *testing.T, r *gin.Engine, filename string, length int) string {
+	t.Helper()
+	w := tusRequest(r, http.MethodPost, "/api/upload/tus", nil, map[string]string{
+		"Upload-Length":   strconv.Itoa(length),
+		"Upload-Metadata": "filename " + base64.StdEncoding.EncodeToString([]byte(filename)),
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create: got status %d, body %s", w.Code, w.Body.String())
+	}
+	location := w.Header().Get("Location")
+	if location == "" {
+		t.Fatal("create: missing Location header")
+	}
+	return location
+}
+
+func TestTusResumableUpload(t *testing.T) {
+	r := setupTusTest(t)
+	content := bytes.Repeat([]byte("a"), 1000)
+	location := createUpload(t, r, "promo.mp4", len(content))
+
+	patch := func(offset int, chunk []byte) *httptest.ResponseRecorder {
+		return tusRequest(r, http.MethodPatch, location, chunk, map[string]string{
+			"Content-Type":  tusContentType,
+			"Upload-Offset": strconv.Itoa(offset),
+		})
+	}
+
+	if w := patch(0, content[:400]); w.Code != http.StatusNoContent || w.Header().Get("Upload-Offset") != "400" {
+		t.Fatalf("first chunk: got status %d, offset %q", w.Code, w.Header().Get("Upload-Offset"))
+	}
+
+	// Resuming from a stale offset must be rejected
+	if w := patch(0, content[:400]); w.Code != http.StatusConflict {
+		t.Fatalf("stale offset: got status %d, want %d", w.Code, http.StatusConflict)
+	}
+
+	w := tusRequest(r, http.MethodHead, location, nil, nil)
+	if w.Code != http.StatusOK || w.Header().Get("Upload-Offset") != "400" {
+		t.Fatalf("head: got status %d, offset %q", w.Code, w.Header().Get("Upload-Offset"))
+	}
+
+	if w := patch(400, content[400:]); w.Code != http.StatusNoContent || w.Header().Get("Upload-Offset") != "1000" {
+		t.Fatalf("last chunk: got status %d, offset %q", w.Code, w.Header().Get("Upload-Offset"))
+	}
+
+	var upload models.Upload
+	if err := database.GetDB().First(&upload).Error; err != nil {
+		t.Fatalf("failed to load upload: %v", err)
+	}
+	if upload.CompletedAt == nil || upload.URL == "" {
+		t.Fatalf("upload not completed: %+v", upload)
+	}
+	stored, err := os.ReadFile(filepath.Join(uploadRoot, "videos", filepath.Base(upload.URL)))
+	if err != nil {
+		t.Fatalf("stored file missing: %v", err)
+	}
+	if !bytes.Equal(stored, content) {
+		t.Error("stored file content does not match upload")
+	}
+	if _, err := os.Stat(partialPath(upload.ID)); !os.IsNotExist(err) {
+		t.Error("partial file was not removed")
+	}
+}
+
+func TestTusCreateValidation(t *testing.T) {
+	r := setupTusTest(t)
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    int
+	}{
+		{
+			name:    "missing tus version",
+			headers: map[string]string{"Tus-Resumable": "0.2.2", "Upload-Length": "10"},
+			want:    http.StatusPreconditionFailed,
+		},
+		{
+			name:    "missing filename",
+			headers: map[string]string{"Upload-Length": "10"},
+			want:    http.StatusBadRequest,
+		},
+		{
+			name: "unsupported format",
+			headers: map[string]string{
+				"Upload-Length":   "10",
+				"Upload-Metadata": "filename " + base64.StdEncoding.EncodeToString([]byte("run.exe")),
+			},
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "image over limit",
+			headers: map[string]string{
+				"Upload-Length":   strconv.Itoa(4 * mb),
+				"Upload-Metadata": "filename " + base64.StdEncoding.EncodeToString([]byte("map.png")),
+			},
+			want: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := tusRequest(r, http.MethodPost, "/api/upload/tus", nil, tt.headers)
+			if w.Code != tt.want {
+				t.Errorf("got status %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestTusUploadOverLoweredLimit(t *testing.T) {
+	r := setupTusTest(t)
+	content := bytes.Repeat([]byte("a"), 2*mb)
+	location := createUpload(t, r, "map.png", len(content))
+
+	// The limit drops below the upload's size while it is being sent
+	t.Setenv("UPLOAD_MAX_IMAGE_MB", "1")
+	w := tusRequest(r, http.MethodPatch, location, content, map[string]string{
+		"Content-Type":  tusContentType,
+		"Upload-Offset": "0",
+	})
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("got status %d, want %d, body %s", w.Code, http.StatusRequestEntityTooLarge, w.Body.String())
+	}
+
+	var uploads int64
+	database.GetDB().Model(&models.Upload{}).Count(&uploads)
+	entries, _ := os.ReadDir(partialDir)
+	if uploads != 0 || len(entries) != 0 {
+		t.Errorf("%d uploads and %d partial files left, want the upload discarded", uploads, len(entries))
+	}
+	if w := tusRequest(r, http.MethodHead, location, nil, nil); w.Code != http.StatusNotFound {
+		t.Errorf("head after rejection: got status %d, want 404", w.Code)
+	}
+}
+
+func TestMediaKindLimitFromEnv(t *testing.T) {
+	t.Setenv("UPLOAD_MAX_IMAGE_MB", "20")
+	kind, _ := kindByName("image")
+	if got := kind.MaxSize(); got != 20*mb {
+		t.Errorf("MaxSize() = %d, want %d", got, 20*mb)
+	}
+}
+
+func TestRemoveExpiredUploads(t *testing.T) {
+	r := setupTusTest(t)
+	location := createUpload(t, r, "agenda.pdf", 100)
+
+	database.GetDB().Model(&models.Upload{}).Where("1 = 1").Update("expires_at", time.Now().Add(-time.Minute))
+
+	if w := tusRequest(r, http.MethodHead, location, nil, nil); w.Code != http.StatusGone {
+		t.Fatalf("head on expired upload: got status %d, want %d", w.Code, http.StatusGone)
+	}
+
+	removed, err := removeExpiredUploads()
+	if err != nil || removed != 1 {
+		t.Fatalf("removeExpiredUploads() = %d, %v, want 1, nil", removed, err)
+	}
+	if w := tusRequest(r, http.MethodHead, location, nil, nil); w.Code != http.StatusNotFound {
+		t.Errorf("head after cleanup: got status %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestTusInfectedUploadIsQuarantined(t *testing.T) {
+	r := setupTusTest(t)
+	SetScanner(infectedScanner{})
+
+	content := []byte("not really a video")
+	location := createUpload(t, r, "promo.mp4", len(content))
+
+	w := tusRequest(r, http.MethodPatch, location, content, map[string]string{
+		"Content-Type":  tusContentType,
+		"Upload-Offset": "0",
+	})
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("patch: got status %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+
+	var upload models.Upload
+	if err := database.GetDB().First(&upload).Error; err != nil {
+		t.Fatalf("failed to load upload: %v", err)
+	}
+	if upload.Status != models.UploadStatusQuarantined || upload.URL != "" || upload.ScanSignature != "Eicar-Test-Signature" {
+		t.Errorf("upload not quarantined: %+v", upload)
+	}
+
+	published, _ := os.ReadDir(filepath.Join(uploadRoot, "videos"))
+	if len(published) != 0 {
+		t.Error("infected file was published")
+	}
+	quarantined, _ := os.ReadDir(quarantineDir)
+	if len(quarantined) != 1 {
+		t.Errorf("expected 1 quarantined file, got %d", len(quarantined))
+	}
+}
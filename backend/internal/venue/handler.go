@@ -0,0 +1,166 @@
+package venue
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"online-task/internal/models"
+	"online-task/pkg/database"
+)
+
+// fromRequest copies the request fields onto the venue
+func fromRequest(venue *models.Venue, req *models.CreateVenueRequest) {
+	venue.Name = req.Name
+	venue.Address = req.Address
+	venue.Latitude = *req.Latitude
+	venue.Longitude = *req.Longitude
+	venue.DefaultCapacity = req.DefaultCapacity
+	venue.Accessibility = req.Accessibility
+	venue.Photos = req.Photos
+	if venue.Photos == nil {
+		venue.Photos = []string{}
+	}
+}
+
+// @Summary Get all venues
+// @Description Get a list of all venues ordered by name
+// @Tags venues
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Venue
+// @Router /venues [get]
+func GetAllVenuesHandler(c *gin.Context) {
+	var venues []models.Venue
+	if err := database.GetDB().Order("name ASC").Find(&venues).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch venues"})
+		return
+	}
+
+	c.JSON(http.StatusOK, venues)
+}
+
+// @Summary Get venue by ID
+// @Description Get details of a specific venue
+// @Tags venues
+// @Accept json
+// @Produce json
+// @Param id path string true "Venue ID"
+// @Success 200 {object} models.Venue
+// @Failure 404 {object} models.ErrorResponse
+// @Router /venues/{id} [get]
+func GetVenueHandler(c *gin.Context) {
+	var venue models.Venue
+	if err := database.GetDB().First(&venue, "id = ?", c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Venue not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch venue"})
+		return
+	}
+
+	c.JSON(http.StatusOK, venue)
+}
+
+// @Summary Create a new venue
+// @Description Create a new venue (admin only)
+// @Tags venues
+// @Accept json
+// @Produce json
+// @Param request body models.CreateVenueRequest true "Venue details"
+// @Security Bearer
+// @Success 201 {object} models.Venue
+// @Router /venues [post]
+func CreateVenueHandler(c *gin.Context) {
+	var req models.CreateVenueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	venue := models.Venue{ID: uuid.New().String()}
+	fromRequest(&venue, &req)
+
+	if err := database.GetDB().Create(&venue).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create venue"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, venue)
+}
+
+// @Summary Update a venue
+// @Description Update an existing venue (admin only)
+// @Tags venues
+// @Accept json
+// @Produce json
+// @Param id path string true "Venue ID"
+// @Param request body models.CreateVenueRequest true "Venue details"
+// @Security Bearer
+// @Success 200 {object} models.Venue
+// @Failure 404 {object} models.ErrorResponse
+// @Router /venues/{id} [put]
+func UpdateVenueHandler(c *gin.Context) {
+	var req models.CreateVenueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var venue models.Venue
+	if err := database.GetDB().First(&venue, "id = ?", c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Venue not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch venue"})
+		return
+	}
+
+	fromRequest(&venue, &req)
+	if err := database.GetDB().Save(&venue).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update venue"})
+		return
+	}
+
+	c.JSON(http.StatusOK, venue)
+}
+
+// @Summary Delete a venue
+// @Description Delete an existing venue (admin only). Venues that events are still held at cannot be deleted.
+// @Tags venues
+// @Accept json
+// @Produce json
+// @Param id path string true "Venue ID"
+// @Security Bearer
+// @Success 200 {object} models.SuccessResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /venues/{id} [delete]
+func DeleteVenueHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	var eventCount int64
+	if err := database.GetDB().Model(&models.Event{}).Where("venue_id = ?", id).Count(&eventCount).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check venue events"})
+		return
+	}
+	if eventCount > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Venue is used by events", "eventCount": eventCount})
+		return
+	}
+
+	result := database.GetDB().Delete(&models.Venue{}, "id = ?", id)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete venue"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Venue not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: "Venue deleted successfully"})
+}
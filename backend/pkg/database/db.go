@@ -1,6 +1,7 @@
 package database
 
 import (
+	"fmt"
 	"log"
 	"os"
 
@@ -22,18 +23,41 @@ func Init() {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
-	// Auto Migrate the schemas
-	err = db.AutoMigrate(
+	if err := Migrate(db); err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	DB = db
+}
+
+// Migrate prepares existing data for new constraints, then creates or
+// updates the schema
+func Migrate(db *gorm.DB) error {
+	if err := runPreMigrations(db); err != nil {
+		return fmt.Errorf("migrate existing data: %w", err)
+	}
+
+	return db.AutoMigrate(
 		&models.User{},
 		&models.Event{},
+		&models.EventSeries{},
+		&models.Venue{},
+		&models.TicketType{},
 		&models.Tag{},
 		&models.Booking{},
+		&models.Attendee{},
+		&models.SeatHold{},
+		&models.SeatMap{},
+		&models.Seat{},
+		&models.SeatReservation{},
+		&models.Refund{},
+		&models.PromoCode{},
+		&models.TicketTransfer{},
+		&models.Upload{},
+		&models.EventMedia{},
+		&models.Notification{},
+		&models.IdempotencyKey{},
 	)
-	if err != nil {
-		log.Fatalf("Failed to migrate database: %v", err)
-	}
-
-	DB = db
 }
 
 func GetDB() *gorm.DB {
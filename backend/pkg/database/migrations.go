@@ -0,0 +1,326 @@
+package database
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"online-task/internal/models"
+	"online-task/pkg/money"
+	"online-task/pkg/slug"
+)
+
+// preMigrations prepare existing data before AutoMigrate adds constraints it would violate
+var preMigrations = []func(*gorm.DB) error{
+	migrateTagSlugs,
+	migrateTagUniqueness,
+	migrateEventStatus,
+	migrateEventEndDate,
+	migrateMoney,
+	migrateBookingPaymentIntent,
+	migrateEventReserved,
+}
+
+func runPreMigrations(db *gorm.DB) error {
+	for _, migrate := range preMigrations {
+		if err := migrate(db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateTagSlugs fills the slug and normalized name of tags created before
+// they existed. Tags whose names only differ by case are merged into the
+// oldest one so the case-insensitive unique index can be created.
+func migrateTagSlugs(db *gorm.DB) error {
+	m := db.Migrator()
+	if !m.HasTable(&models.Tag{}) || m.HasColumn(&models.Tag{}, "Slug") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, field := range []string{"NormalizedName", "Slug", "ParentID"} {
+			if !tx.Migrator().HasColumn(&models.Tag{}, field) {
+				if err := tx.Migrator().AddColumn(&models.Tag{}, field); err != nil {
+					return fmt.Errorf("add tag column %s: %w", field, err)
+				}
+			}
+		}
+
+		var tags []models.Tag
+		if err := tx.Order("created_at ASC").Find(&tags).Error; err != nil {
+			return err
+		}
+
+		hasEventTags := tx.Migrator().HasTable("event_tags")
+		keepers := make(map[string]string)
+		slugs := make(map[string]bool)
+		for _, tag := range tags {
+			normalized := strings.ToLower(strings.TrimSpace(tag.Name))
+
+			if keeperID, ok := keepers[normalized]; ok {
+				// Move event links to the surviving tag, then retire the duplicate
+				if hasEventTags {
+					if err := tx.Exec("INSERT OR IGNORE INTO event_tags (event_id, tag_id) SELECT event_id, ? FROM event_tags WHERE tag_id = ?", keeperID, tag.ID).Error; err != nil {
+						return err
+					}
+					if err := tx.Exec("DELETE FROM event_tags WHERE tag_id = ?", tag.ID).Error; err != nil {
+						return err
+					}
+				}
+				if err := tx.Delete(&models.Tag{}, "id = ?", tag.ID).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			keepers[normalized] = tag.ID
+
+			base := slug.Make(tag.Name)
+			if base == "" {
+				base = "tag"
+			}
+			tagSlug := base
+			for i := 2; slugs[tagSlug]; i++ {
+				tagSlug = fmt.Sprintf("%s-%d", base, i)
+			}
+			slugs[tagSlug] = true
+
+			if err := tx.Model(&models.Tag{}).Where("id = ?", tag.ID).Updates(map[string]interface{}{
+				"normalized_name": normalized,
+				"slug":            tagSlug,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// uniqueTagColumn matches a tag column declared UNIQUE in the table itself,
+// as the first tags table did for names. SQLite can only drop such a
+// constraint by rebuilding the table.
+var uniqueTagColumn = regexp.MustCompile("(`(?:name|normalized_name|slug)` text[^,]*?) UNIQUE")
+
+var createTagsTable = regexp.MustCompile("^CREATE TABLE [`\"]?tags[`\"]?")
+
+// migrateTagUniqueness leaves names and slugs unique only among tags that
+// were not deleted, so the name of a deleted or merged tag can be used again.
+// Tables whose columns are themselves UNIQUE are rebuilt without it, and the
+// partial unique indexes created before AutoMigrate looks at the columns,
+// which it would otherwise make UNIQUE again.
+func migrateTagUniqueness(db *gorm.DB) error {
+	m := db.Migrator()
+	if !m.HasTable(&models.Tag{}) {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		var ddl string
+		if err := tx.Raw("SELECT sql FROM sqlite_master WHERE type = 'table' AND name = 'tags'").Scan(&ddl).Error; err != nil {
+			return err
+		}
+		if rebuilt := uniqueTagColumn.ReplaceAllString(ddl, "$1"); rebuilt != ddl {
+			create := createTagsTable.ReplaceAllString(rebuilt, "CREATE TABLE `tags__temp`")
+			for _, statement := range []string{
+				create,
+				"INSERT INTO tags__temp SELECT * FROM tags",
+				"DROP TABLE tags",
+				"ALTER TABLE tags__temp RENAME TO tags",
+			} {
+				if err := tx.Exec(statement).Error; err != nil {
+					return fmt.Errorf("rebuild tags table: %w", err)
+				}
+			}
+		}
+
+		for _, index := range []string{"idx_tags_normalized_name", "idx_tags_slug"} {
+			if tx.Migrator().HasIndex(&models.Tag{}, index) {
+				continue
+			}
+			if err := tx.Migrator().CreateIndex(&models.Tag{}, index); err != nil {
+				return fmt.Errorf("create tag index %s: %w", index, err)
+			}
+		}
+		return nil
+	})
+}
+
+// migrateEventStatus publishes events created before the lifecycle existed,
+// since they were already public. New events start as drafts.
+func migrateEventStatus(db *gorm.DB) error {
+	m := db.Migrator()
+	if !m.HasTable(&models.Event{}) || m.HasColumn(&models.Event{}, "Status") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Migrator().AddColumn(&models.Event{}, "Status"); err != nil {
+			return fmt.Errorf("add event column Status: %w", err)
+		}
+		return tx.Exec("UPDATE events SET status = ?", models.EventStatusPublished).Error
+	})
+}
+
+// migrateEventEndDate gives events created before end times existed an end
+// equal to their start, in UTC
+func migrateEventEndDate(db *gorm.DB) error {
+	m := db.Migrator()
+	if !m.HasTable(&models.Event{}) || m.HasColumn(&models.Event{}, "EndDate") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, field := range []string{"EndDate", "TimeZone"} {
+			if !tx.Migrator().HasColumn(&models.Event{}, field) {
+				if err := tx.Migrator().AddColumn(&models.Event{}, field); err != nil {
+					return fmt.Errorf("add event column %s: %w", field, err)
+				}
+			}
+		}
+		return tx.Exec("UPDATE events SET end_date = date, time_zone = ?", "UTC").Error
+	})
+}
+
+// migrateMoney converts float prices into minor units of the default
+// currency. Bookings get a total of their unit price times quantity; those
+// made before unit prices were recorded take the price of their event.
+func migrateMoney(db *gorm.DB) error {
+	currency := money.DefaultCurrency()
+	exp, err := money.Exponent(currency)
+	if err != nil {
+		return err
+	}
+	factor := 1
+	for i := 0; i < exp; i++ {
+		factor *= 10
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		m := tx.Migrator()
+		for _, model := range []interface{}{&models.Event{}, &models.TicketType{}} {
+			if !m.HasTable(model) || !m.HasColumn(model, "price") {
+				continue
+			}
+			if err := addMoneyColumns(tx, model, "price_"); err != nil {
+				return err
+			}
+			if err := tx.Model(model).Session(&gorm.Session{AllowGlobalUpdate: true}).UpdateColumns(map[string]interface{}{
+				"price_amount":   gorm.Expr("CAST(ROUND(COALESCE(price, 0) * ?) AS INTEGER)", factor),
+				"price_currency": currency,
+			}).Error; err != nil {
+				return err
+			}
+			if err := m.DropColumn(model, "price"); err != nil {
+				return fmt.Errorf("drop price column: %w", err)
+			}
+		}
+
+		booking := &models.Booking{}
+		if !m.HasTable(booking) || m.HasColumn(booking, "unit_price_amount") {
+			return nil
+		}
+		hadUnitPrice := m.HasColumn(booking, "unit_price")
+		if err := addMoneyColumns(tx, booking, "unit_price_", "total_"); err != nil {
+			return err
+		}
+		if hadUnitPrice {
+			if err := tx.Exec("UPDATE bookings SET unit_price_amount = CAST(ROUND(COALESCE(unit_price, 0) * ?) AS INTEGER), unit_price_currency = ?", factor, currency).Error; err != nil {
+				return err
+			}
+			if err := m.DropColumn(booking, "unit_price"); err != nil {
+				return fmt.Errorf("drop unit_price column: %w", err)
+			}
+		} else if m.HasTable(&models.Event{}) {
+			if err := tx.Exec(`UPDATE bookings SET
+				unit_price_amount = COALESCE((SELECT price_amount FROM events WHERE events.id = bookings.event_id), 0),
+				unit_price_currency = COALESCE((SELECT price_currency FROM events WHERE events.id = bookings.event_id), ?)`, currency).Error; err != nil {
+				return err
+			}
+		}
+
+		quantity := "1"
+		if m.HasColumn(booking, "quantity") {
+			quantity = "COALESCE(quantity, 1)"
+		}
+		return tx.Exec("UPDATE bookings SET total_amount = unit_price_amount * " + quantity + ", total_currency = unit_price_currency").Error
+	})
+}
+
+// migrateBookingPaymentIntent adds the payment intent column to bookings
+// made before paid bookings existed. SQLite cannot add a UNIQUE column, so
+// the column is added plain and its unique index created on its own.
+func migrateBookingPaymentIntent(db *gorm.DB) error {
+	m := db.Migrator()
+	booking := &models.Booking{}
+	if !m.HasTable(booking) || m.HasColumn(booking, "PaymentIntentID") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("ALTER TABLE bookings ADD payment_intent_id text").Error; err != nil {
+			return fmt.Errorf("add booking column PaymentIntentID: %w", err)
+		}
+		return tx.Migrator().CreateIndex(booking, "PaymentIntentID")
+	})
+}
+
+// addMoneyColumns adds the amount and currency columns of embedded money fields
+func addMoneyColumns(tx *gorm.DB, model interface{}, prefixes ...string) error {
+	for _, prefix := range prefixes {
+		for _, column := range []string{prefix + "amount", prefix + "currency"} {
+			if tx.Migrator().HasColumn(model, column) {
+				continue
+			}
+			if err := tx.Migrator().AddColumn(model, column); err != nil {
+				return fmt.Errorf("add column %s: %w", column, err)
+			}
+		}
+	}
+	return nil
+}
+
+// migrateEventReserved counts the tickets already taken from each event's
+// capacity by active bookings and seat holds. Bookings made before
+// quantities existed hold one ticket, and before statuses existed they were
+// all active.
+func migrateEventReserved(db *gorm.DB) error {
+	m := db.Migrator()
+	if !m.HasTable(&models.Event{}) || m.HasColumn(&models.Event{}, "Reserved") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		m := tx.Migrator()
+		if err := m.AddColumn(&models.Event{}, "Reserved"); err != nil {
+			return fmt.Errorf("add event column Reserved: %w", err)
+		}
+		if booking := (&models.Booking{}); m.HasTable(booking) {
+			quantity := "1"
+			if m.HasColumn(booking, "quantity") {
+				quantity = "COALESCE(quantity, 1)"
+			}
+			where := ""
+			var args []interface{}
+			if m.HasColumn(booking, "status") {
+				where = " AND bookings.status <> ?"
+				args = append(args, models.BookingStatusCancelled)
+			}
+			if err := tx.Exec(`UPDATE events SET reserved = reserved + COALESCE((SELECT SUM(`+quantity+`) FROM bookings
+				WHERE bookings.event_id = events.id AND bookings.deleted_at IS NULL`+where+`), 0)`, args...).Error; err != nil {
+				return err
+			}
+		}
+		if m.HasTable(&models.SeatHold{}) {
+			if err := tx.Exec(`UPDATE events SET reserved = reserved + COALESCE((SELECT SUM(quantity) FROM seat_holds
+				WHERE seat_holds.event_id = events.id AND seat_holds.status = ?), 0)`,
+				models.HoldStatusActive).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
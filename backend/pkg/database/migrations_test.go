@@ -0,0 +1,108 @@
+package database
+
+import (
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"online-task/internal/models"
+	"online-task/pkg/money"
+)
+
+// baselineSchema is the schema of the first release, before bookings had
+// quantities, statuses or prices
+var baselineSchema = []string{
+	"CREATE TABLE `users` (`id` text,`username` text NOT NULL UNIQUE,`email` text NOT NULL UNIQUE,`password` text NOT NULL,`role` text DEFAULT \"user\",`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,PRIMARY KEY (`id`))",
+	"CREATE INDEX `idx_users_deleted_at` ON `users`(`deleted_at`)",
+	"CREATE TABLE `events` (`id` text,`name` text NOT NULL,`description` text,`category` text,`date` datetime,`location` text,`price` real,`image` text,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,PRIMARY KEY (`id`))",
+	"CREATE INDEX `idx_events_deleted_at` ON `events`(`deleted_at`)",
+	"CREATE TABLE `tags` (`id` text,`name` text NOT NULL UNIQUE,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,PRIMARY KEY (`id`))",
+	"CREATE INDEX `idx_tags_deleted_at` ON `tags`(`deleted_at`)",
+	"CREATE TABLE `event_tags` (`event_id` text,`tag_id` text,PRIMARY KEY (`event_id`,`tag_id`))",
+	"CREATE TABLE `bookings` (`id` text,`user_id` text NOT NULL,`event_id` text NOT NULL,`booking_date` datetime,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,PRIMARY KEY (`id`))",
+	"CREATE INDEX `idx_bookings_deleted_at` ON `bookings`(`deleted_at`)",
+}
+
+func TestMigrateBaselineDatabase(t *testing.T) {
+	t.Setenv("DEFAULT_CURRENCY", "USD")
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	statements := append(baselineSchema,
+		"INSERT INTO users VALUES ('ada', 'ada', 'ada@example.com', 'x', 'user', datetime(), datetime(), NULL)",
+		"INSERT INTO events VALUES ('concert', 'Concert', '', 'music', '2030-01-01 20:00:00', 'Hall', 25.5, '', datetime(), datetime(), NULL)",
+		"INSERT INTO events VALUES ('opera', 'Opera', '', 'music', '2030-02-01 20:00:00', 'Hall', 40, '', datetime(), datetime(), NULL)",
+		"INSERT INTO tags VALUES ('rock', 'Rock', datetime(), datetime(), NULL)",
+		"INSERT INTO event_tags VALUES ('concert', 'rock')",
+		"INSERT INTO bookings VALUES ('first', 'ada', 'concert', datetime(), datetime(), datetime(), NULL)",
+		"INSERT INTO bookings VALUES ('second', 'ada', 'concert', datetime(), datetime(), datetime(), NULL)",
+		"INSERT INTO bookings VALUES ('deleted', 'ada', 'concert', datetime(), datetime(), datetime(), datetime())",
+	)
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			t.Fatalf("failed to create baseline database: %v", err)
+		}
+	}
+
+	if err := Migrate(db); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	// Starting again on the upgraded database changes nothing
+	if err := Migrate(db); err != nil {
+		t.Fatalf("second Migrate() error = %v", err)
+	}
+
+	var events []models.Event
+	db.Order("id").Find(&events)
+	if len(events) != 2 {
+		t.Fatalf("found %d events, want 2", len(events))
+	}
+	for _, event := range events {
+		want := map[string]int{"concert": 2, "opera": 0}[event.ID]
+		if event.Reserved != want || event.Status != models.EventStatusPublished {
+			t.Errorf("event %s: reserved %d, status %s, want %d and published", event.ID, event.Reserved, event.Status, want)
+		}
+	}
+	if events[0].Price != money.New(2550, "USD") {
+		t.Errorf("concert price = %+v, want 25.50 USD", events[0].Price)
+	}
+
+	var bookings []models.Booking
+	db.Order("id").Find(&bookings)
+	for _, booking := range bookings {
+		if booking.Quantity != 1 || booking.Status != models.BookingStatusConfirmed || booking.Total != money.New(2550, "USD") {
+			t.Errorf("booking %s: %d %s %+v, want one confirmed ticket at 25.50 USD", booking.ID, booking.Quantity, booking.Status, booking.Total)
+		}
+	}
+
+	// Payment intents are unique once the column exists
+	if err := db.Exec("UPDATE bookings SET payment_intent_id = ?", "pi_1").Error; err == nil {
+		t.Error("two bookings share a payment intent")
+	}
+
+	var tag models.Tag
+	db.First(&tag, "id = ?", "rock")
+	if tag.Slug != "rock" || tag.NormalizedName != "rock" {
+		t.Errorf("tag = %+v, want slug and normalized name rock", tag)
+	}
+
+	// Names are only unique among tags that were not deleted
+	duplicate := models.Tag{ID: "rock-2", Name: "Rock", NormalizedName: "rock", Slug: "rock-2"}
+	if err := db.Create(&duplicate).Error; err == nil {
+		t.Error("created a second tag named Rock")
+	}
+	db.Delete(&tag)
+	if err := db.Create(&duplicate).Error; err != nil {
+		t.Errorf("failed to reuse the name of a deleted tag: %v", err)
+	}
+	var links int64
+	db.Table("event_tags").Count(&links)
+	if links != 1 {
+		t.Errorf("%d event tag links, want 1", links)
+	}
+}
@@ -0,0 +1,39 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		name       string
+		lat1, lng1 float64
+		lat2, lng2 float64
+		want       float64
+	}{
+		{name: "same point", lat1: 30.0444, lng1: 31.2357, lat2: 30.0444, lng2: 31.2357, want: 0},
+		{name: "Cairo to Alexandria", lat1: 30.0444, lng1: 31.2357, lat2: 31.2001, lng2: 29.9187, want: 179},
+		{name: "London to Paris", lat1: 51.5074, lng1: -0.1278, lat2: 48.8566, lng2: 2.3522, want: 344},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Distance(tt.lat1, tt.lng1, tt.lat2, tt.lng2)
+			if math.Abs(got-tt.want) > 2 {
+				t.Errorf("Distance() = %.1f km, want about %.0f km", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBoundingBoxContainsRadius(t *testing.T) {
+	lat, lng := 30.0444, 31.2357
+	minLat, maxLat, minLng, maxLng := BoundingBox(lat, lng, 50)
+
+	for _, p := range [][2]float64{{minLat, lng}, {maxLat, lng}, {lat, minLng}, {lat, maxLng}} {
+		if d := Distance(lat, lng, p[0], p[1]); d < 49.5 {
+			t.Errorf("box edge %v is only %.1f km away, box is too small", p, d)
+		}
+	}
+}
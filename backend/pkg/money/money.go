@@ -0,0 +1,155 @@
+// Package money represents amounts of money as integer minor units (such as
+// cents) with an ISO 4217 currency, so totals never suffer float rounding
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// FallbackCurrency is used when DEFAULT_CURRENCY is unset or unsupported
+const FallbackCurrency = "USD"
+
+// ErrCurrencyMismatch is returned when combining amounts in different currencies
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// exponents maps supported currencies to their number of minor unit digits
+var exponents = map[string]int{
+	"AED": 2, "AUD": 2, "BHD": 3, "CAD": 2, "CHF": 2, "CNY": 2, "DKK": 2,
+	"EGP": 2, "EUR": 2, "GBP": 2, "HKD": 2, "INR": 2, "JOD": 3, "JPY": 0,
+	"KRW": 0, "KWD": 3, "MAD": 2, "NOK": 2, "NZD": 2, "OMR": 3, "QAR": 2,
+	"SAR": 2, "SEK": 2, "SGD": 2, "TND": 3, "TRY": 2, "USD": 2, "ZAR": 2,
+}
+
+// Money is an amount in the minor units of its currency, e.g. 2550 USD is
+// $25.50. It is stored as two columns when embedded in a model.
+type Money struct {
+	Amount   int64  `json:"amount" binding:"min=0" example:"2550"`
+	Currency string `json:"currency" example:"USD"`
+	// Decimal is the amount in major units, filled in when encoding to JSON
+	Decimal string `gorm:"-" json:"decimal" example:"25.50"`
+}
+
+// New returns an amount of minor units in the given currency
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Exponent returns the number of minor unit digits of a currency
+func Exponent(currency string) (int, error) {
+	exp, ok := exponents[currency]
+	if !ok {
+		return 0, fmt.Errorf("unsupported currency %q", currency)
+	}
+	return exp, nil
+}
+
+// DefaultCurrency returns the currency from DEFAULT_CURRENCY, or USD
+func DefaultCurrency() string {
+	currency := strings.ToUpper(strings.TrimSpace(os.Getenv("DEFAULT_CURRENCY")))
+	if _, err := Exponent(currency); err != nil {
+		return FallbackCurrency
+	}
+	return currency
+}
+
+// FromMajor converts an amount in major units, such as a legacy float price,
+// rounding to the nearest minor unit
+func FromMajor(amount float64, currency string) (Money, error) {
+	exp, err := Exponent(currency)
+	if err != nil {
+		return Money{}, err
+	}
+	return New(int64(math.Round(amount*math.Pow10(exp))), currency), nil
+}
+
+// IsZero reports whether the amount is zero
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// Mul returns the amount multiplied by a quantity
+func (m Money) Mul(n int) Money {
+	return New(m.Amount*int64(n), m.Currency)
+}
+
+// Percent returns p percent of the amount, rounded down to a minor unit
+func (m Money) Percent(p int) Money {
+	return New(m.Amount*int64(p)/100, m.Currency)
+}
+
+// Add returns the sum of two amounts in the same currency
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+	return New(m.Amount+other.Amount, m.Currency), nil
+}
+
+// Sub returns the difference of two amounts in the same currency
+func (m Money) Sub(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+	return New(m.Amount-other.Amount, m.Currency), nil
+}
+
+// DecimalString formats the amount in major units, e.g. "25.50"
+func (m Money) DecimalString() string {
+	exp, err := Exponent(m.Currency)
+	if err != nil || exp == 0 {
+		return strconv.FormatInt(m.Amount, 10)
+	}
+
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	unit := int64(math.Pow10(exp))
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/unit, exp, amount%unit)
+}
+
+// String formats the amount with its currency, e.g. "25.50 USD"
+func (m Money) String() string {
+	return m.DecimalString() + " " + m.Currency
+}
+
+// MarshalJSON encodes the amount, currency and decimal representation
+func (m Money) MarshalJSON() ([]byte, error) {
+	type plain Money
+	m.Decimal = m.DecimalString()
+	return json.Marshal(plain(m))
+}
+
+// UnmarshalJSON decodes an amount in minor units. The currency defaults to
+// DefaultCurrency and must be supported; the decimal field is ignored.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var v struct {
+		Amount   *int64 `json:"amount"`
+		Currency string `json:"currency"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return errors.New("money must be an object with an integer amount in minor units and a currency")
+	}
+	if v.Amount == nil {
+		return errors.New("money amount is required")
+	}
+
+	currency := strings.ToUpper(strings.TrimSpace(v.Currency))
+	if currency == "" {
+		currency = DefaultCurrency()
+	}
+	if _, err := Exponent(currency); err != nil {
+		return err
+	}
+
+	*m = New(*v.Amount, currency)
+	return nil
+}
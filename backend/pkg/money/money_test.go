@@ -0,0 +1,118 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFromMajor(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   float64
+		currency string
+		want     int64
+		wantErr  bool
+	}{
+		{name: "cents", amount: 25.5, currency: "USD", want: 2550},
+		{name: "float noise is rounded", amount: 0.1 + 0.2, currency: "EUR", want: 30},
+		{name: "no minor units", amount: 1500, currency: "JPY", want: 1500},
+		{name: "three digits", amount: 1.234, currency: "KWD", want: 1234},
+		{name: "unsupported currency", amount: 1, currency: "XXX", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromMajor(tt.amount, tt.currency)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FromMajor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.Amount != tt.want {
+				t.Errorf("FromMajor() = %d, want %d", got.Amount, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecimalString(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{money: New(2550, "USD"), want: "25.50"},
+		{money: New(5, "EGP"), want: "0.05"},
+		{money: New(-1999, "EUR"), want: "-19.99"},
+		{money: New(1500, "JPY"), want: "1500"},
+		{money: New(1234, "KWD"), want: "1.234"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.DecimalString(); got != tt.want {
+			t.Errorf("%v DecimalString() = %q, want %q", tt.money.Amount, got, tt.want)
+		}
+	}
+}
+
+func TestAdd(t *testing.T) {
+	sum, err := New(1000, "USD").Add(New(250, "USD"))
+	if err != nil || sum.Amount != 1250 {
+		t.Errorf("Add() = %v, %v, want 1250", sum, err)
+	}
+	if _, err := New(1000, "USD").Add(New(250, "EUR")); err != ErrCurrencyMismatch {
+		t.Errorf("Add() across currencies error = %v, want ErrCurrencyMismatch", err)
+	}
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		amount  int64
+		percent int
+		want    int64
+	}{
+		{amount: 4000, percent: 100, want: 4000},
+		{amount: 4000, percent: 50, want: 2000},
+		{amount: 999, percent: 50, want: 499},
+		{amount: 4000, percent: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		if got := New(tt.amount, "USD").Percent(tt.percent); got.Amount != tt.want {
+			t.Errorf("%d Percent(%d) = %d, want %d", tt.amount, tt.percent, got.Amount, tt.want)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	t.Setenv("DEFAULT_CURRENCY", "egp")
+
+	tests := []struct {
+		name    string
+		input   string
+		want    Money
+		wantErr bool
+	}{
+		{name: "full", input: `{"amount":2550,"currency":"usd"}`, want: New(2550, "USD")},
+		{name: "default currency", input: `{"amount":100}`, want: New(100, "EGP")},
+		{name: "missing amount", input: `{"currency":"USD"}`, wantErr: true},
+		{name: "float amount", input: `{"amount":25.5,"currency":"USD"}`, wantErr: true},
+		{name: "bare number", input: `25`, wantErr: true},
+		{name: "unsupported currency", input: `{"amount":1,"currency":"ABC"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Money
+			err := json.Unmarshal([]byte(tt.input), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("Unmarshal() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	data, _ := json.Marshal(New(2550, "USD"))
+	if string(data) != `{"amount":2550,"currency":"USD","decimal":"25.50"}` {
+		t.Errorf("Marshal() = %s", data)
+	}
+}
@@ -0,0 +1,176 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"online-task/pkg/money"
+)
+
+// FakeDeclinedPaymentMethod makes the fake provider decline a payment
+const FakeDeclinedPaymentMethod = "fake_card_declined"
+
+// FakeProvider keeps payments in memory and signs its own webhooks, so the
+// whole payment flow can run locally without a real gateway
+type FakeProvider struct {
+	WebhookSecret string
+	// Deliver sends a signed webhook, normally to the webhook endpoint of this
+	// server. Webhooks are dropped when it is nil.
+	Deliver func(payload []byte, header http.Header)
+
+	mu          sync.Mutex
+	intents     map[string]*Intent
+	byReference map[string]string
+	refunded    map[string]int64
+	refunds     map[string]Refund
+}
+
+// NewFake returns a fake provider with a random webhook secret
+func NewFake() *FakeProvider {
+	return &FakeProvider{
+		WebhookSecret: "whsec_fake_" + randomID(),
+		intents:       make(map[string]*Intent),
+		byReference:   make(map[string]string),
+		refunded:      make(map[string]int64),
+		refunds:       make(map[string]Refund),
+	}
+}
+
+func randomID() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func (f *FakeProvider) Name() string {
+	return "fake"
+}
+
+func (f *FakeProvider) CreateIntent(ctx context.Context, amount money.Money, reference string) (Intent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if id, ok := f.byReference[reference]; ok {
+		return *f.intents[id], nil
+	}
+	intent := &Intent{
+		ID:           "fake_pi_" + randomID(),
+		Amount:       amount,
+		Status:       StatusRequiresPayment,
+		ClientSecret: "fake_secret_" + randomID(),
+	}
+	f.intents[intent.ID] = intent
+	f.byReference[reference] = intent.ID
+	return *intent, nil
+}
+
+// Confirm charges the intent immediately and delivers a succeeded webhook,
+// or a failed one for FakeDeclinedPaymentMethod
+func (f *FakeProvider) Confirm(ctx context.Context, intentID, paymentMethod string) (Intent, error) {
+	f.mu.Lock()
+	intent, ok := f.intents[intentID]
+	if !ok {
+		f.mu.Unlock()
+		return Intent{}, fmt.Errorf("unknown intent %s", intentID)
+	}
+	if intent.Status == StatusSucceeded {
+		f.mu.Unlock()
+		return Intent{}, errors.New("intent has already succeeded")
+	}
+
+	eventType := EventPaymentSucceeded
+	if paymentMethod == FakeDeclinedPaymentMethod {
+		eventType = EventPaymentFailed
+	} else {
+		intent.Status = StatusSucceeded
+	}
+	result := *intent
+	f.mu.Unlock()
+
+	if f.Deliver != nil {
+		f.Deliver(f.Webhook(eventType, intentID))
+	}
+	return result, nil
+}
+
+func (f *FakeProvider) Refund(ctx context.Context, intentID string, amount money.Money, reference string) (Refund, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if refund, ok := f.refunds[reference]; ok {
+		return refund, nil
+	}
+	intent, ok := f.intents[intentID]
+	if !ok || intent.Status != StatusSucceeded {
+		return Refund{}, fmt.Errorf("intent %s has not succeeded", intentID)
+	}
+	if amount.Currency != intent.Amount.Currency {
+		return Refund{}, money.ErrCurrencyMismatch
+	}
+	if f.refunded[intentID]+amount.Amount > intent.Amount.Amount {
+		return Refund{}, errors.New("refund exceeds the amount paid")
+	}
+	f.refunded[intentID] += amount.Amount
+
+	refund := Refund{ID: "fake_re_" + randomID(), IntentID: intentID, Amount: amount, Status: StatusSucceeded}
+	f.refunds[reference] = refund
+	return refund, nil
+}
+
+type fakeEvent struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	IntentID string `json:"intentId"`
+}
+
+// Webhook builds a signed webhook payload and headers for an intent
+func (f *FakeProvider) Webhook(eventType, intentID string) ([]byte, http.Header) {
+	payload, _ := json.Marshal(fakeEvent{ID: "fake_evt_" + randomID(), Type: eventType, IntentID: intentID})
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set(SignatureHeader, Sign(f.WebhookSecret, payload, time.Now()))
+	return payload, header
+}
+
+func (f *FakeProvider) VerifyWebhook(payload []byte, header http.Header) (Event, error) {
+	if err := VerifySignature(f.WebhookSecret, payload, header.Get(SignatureHeader), time.Now()); err != nil {
+		return Event{}, err
+	}
+
+	var event fakeEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return Event{}, fmt.Errorf("decode fake event: %w", err)
+	}
+	return Event{ID: event.ID, Type: event.Type, IntentID: event.IntentID}, nil
+}
+
+// PostWebhook returns a Deliver function that posts webhooks to url in the
+// background, as a real gateway would
+func PostWebhook(url string) func(payload []byte, header http.Header) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	return func(payload []byte, header http.Header) {
+		go func() {
+			req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+			if err != nil {
+				log.Printf("Failed to build fake payment webhook: %v", err)
+				return
+			}
+			req.Header = header
+			resp, err := client.Do(req)
+			if err != nil {
+				log.Printf("Failed to deliver fake payment webhook: %v", err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+}
@@ -0,0 +1,100 @@
+// Package payment takes payments through a pluggable provider. Stripe is
+// supported for production and a local fake provider for development and tests.
+package payment
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"online-task/pkg/money"
+)
+
+// Intent statuses
+const (
+	StatusRequiresPayment = "requires_payment"
+	StatusProcessing      = "processing"
+	StatusSucceeded       = "succeeded"
+	StatusCanceled        = "canceled"
+)
+
+// Webhook event types
+const (
+	EventPaymentSucceeded = "payment.succeeded"
+	EventPaymentFailed    = "payment.failed"
+)
+
+// ErrInvalidSignature is returned for webhooks that were not signed by the provider
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Intent is a payment the customer is asked to make
+type Intent struct {
+	ID     string      `json:"id" example:"pi_3MtwBwLkdIwHu7ix28a3tqPa"`
+	Amount money.Money `json:"amount"`
+	Status string      `json:"status" example:"requires_payment"`
+	// ClientSecret lets the client complete the payment with the provider
+	ClientSecret string `json:"clientSecret,omitempty"`
+}
+
+// Refund is money returned for a succeeded intent
+type Refund struct {
+	ID       string      `json:"id" example:"re_3MtwBwLkdIwHu7ix0Z0T1pCk"`
+	IntentID string      `json:"intentId"`
+	Amount   money.Money `json:"amount"`
+	Status   string      `json:"status" example:"succeeded"`
+}
+
+// Event is a verified webhook notification about an intent
+type Event struct {
+	ID       string
+	Type     string
+	IntentID string
+}
+
+// Provider creates and refunds payments and verifies the webhooks that
+// report their outcome
+type Provider interface {
+	Name() string
+	// CreateIntent starts a payment; reference identifies what is paid for
+	// and makes retries idempotent
+	CreateIntent(ctx context.Context, amount money.Money, reference string) (Intent, error)
+	// Confirm asks the provider to charge the payment method for an intent.
+	// The outcome is reported by webhook.
+	Confirm(ctx context.Context, intentID, paymentMethod string) (Intent, error)
+	// Refund returns amount of a succeeded intent to the customer; retries
+	// with the same reference do not refund twice
+	Refund(ctx context.Context, intentID string, amount money.Money, reference string) (Refund, error)
+	// VerifyWebhook checks the signature of a webhook request and decodes it
+	VerifyWebhook(payload []byte, header http.Header) (Event, error)
+}
+
+// Config selects and configures a provider
+type Config struct {
+	// Provider is "stripe" or "fake"; the fake provider is used when empty
+	Provider            string
+	StripeSecretKey     string
+	StripeWebhookSecret string
+	// StripeBaseURL overrides the API address, e.g. for stripe-mock
+	StripeBaseURL string
+	// FakeWebhookURL is where the fake provider delivers its webhooks
+	FakeWebhookURL string
+}
+
+// New returns the provider described by the config
+func New(config Config) (Provider, error) {
+	switch config.Provider {
+	case "stripe":
+		if config.StripeSecretKey == "" || config.StripeWebhookSecret == "" {
+			return nil, errors.New("stripe needs a secret key and a webhook secret")
+		}
+		return NewStripe(config.StripeSecretKey, config.StripeWebhookSecret, config.StripeBaseURL), nil
+	case "", "fake":
+		fake := NewFake()
+		if config.FakeWebhookURL != "" {
+			fake.Deliver = PostWebhook(config.FakeWebhookURL)
+		}
+		return fake, nil
+	default:
+		return nil, errors.New("unknown payment provider " + config.Provider)
+	}
+}
@@ -0,0 +1,137 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"online-task/pkg/money"
+)
+
+func TestVerifySignature(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	payload := []byte(`{"id":"evt_1"}`)
+	valid := Sign("secret", payload, now)
+
+	tests := []struct {
+		name    string
+		secret  string
+		payload []byte
+		header  string
+		now     time.Time
+		wantErr bool
+	}{
+		{name: "valid", secret: "secret", payload: payload, header: valid, now: now},
+		{name: "rotated secret", secret: "secret", payload: payload, header: valid + ",v1=00ff", now: now},
+		{name: "wrong secret", secret: "other", payload: payload, header: valid, now: now, wantErr: true},
+		{name: "tampered payload", secret: "secret", payload: []byte(`{"id":"evt_2"}`), header: valid, now: now, wantErr: true},
+		{name: "replayed too late", secret: "secret", payload: payload, header: valid, now: now.Add(10 * time.Minute), wantErr: true},
+		{name: "missing header", secret: "secret", payload: payload, header: "", now: now, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifySignature(tt.secret, tt.payload, tt.header, tt.now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifySignature() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFakeProvider(t *testing.T) {
+	fake := NewFake()
+	var delivered []Event
+	fake.Deliver = func(payload []byte, header http.Header) {
+		event, err := fake.VerifyWebhook(payload, header)
+		if err != nil {
+			t.Fatalf("VerifyWebhook() error = %v", err)
+		}
+		delivered = append(delivered, event)
+	}
+
+	ctx := context.Background()
+	intent, err := fake.CreateIntent(ctx, money.New(5000, "USD"), "booking-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := fake.CreateIntent(ctx, money.New(5000, "USD"), "booking-1"); again.ID != intent.ID {
+		t.Errorf("CreateIntent() is not idempotent: %s != %s", again.ID, intent.ID)
+	}
+
+	if _, err := fake.Confirm(ctx, intent.ID, FakeDeclinedPaymentMethod); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fake.Confirm(ctx, intent.ID, ""); err != nil {
+		t.Fatal(err)
+	}
+	if len(delivered) != 2 || delivered[0].Type != EventPaymentFailed || delivered[1].Type != EventPaymentSucceeded || delivered[1].IntentID != intent.ID {
+		t.Fatalf("delivered %+v, want a failed then a succeeded event", delivered)
+	}
+
+	first, err := fake.Refund(ctx, intent.ID, money.New(3000, "USD"), "refund-1")
+	if err != nil {
+		t.Errorf("Refund() error = %v", err)
+	}
+	if retry, err := fake.Refund(ctx, intent.ID, money.New(3000, "USD"), "refund-1"); err != nil || retry.ID != first.ID {
+		t.Errorf("retried Refund() = %+v, %v, want the first refund", retry, err)
+	}
+	if _, err := fake.Refund(ctx, intent.ID, money.New(3000, "USD"), "refund-2"); err == nil {
+		t.Error("Refund() beyond the amount paid succeeded")
+	}
+}
+
+func TestStripeProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, _, _ := r.BasicAuth(); user != "sk_test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		r.ParseForm()
+		switch r.URL.Path {
+		case "/v1/payment_intents":
+			if r.Form.Get("amount") != "2550" || r.Form.Get("currency") != "eur" || r.Header.Get("Idempotency-Key") == "" {
+				t.Errorf("unexpected intent request %v", r.Form)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"id": "pi_1", "amount": 2550, "currency": "eur", "status": "requires_payment_method", "client_secret": "pi_1_secret",
+			})
+		case "/v1/refunds":
+			if r.Form.Get("payment_intent") != "pi_1" || r.Form.Get("amount") != "1000" {
+				t.Errorf("unexpected refund request %v", r.Form)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": "re_1", "amount": 1000, "currency": "eur", "status": "succeeded"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	stripe := NewStripe("sk_test", "whsec_test", server.URL)
+	intent, err := stripe.CreateIntent(context.Background(), money.New(2550, "EUR"), "booking-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if intent.ID != "pi_1" || intent.Status != StatusRequiresPayment || intent.Amount != money.New(2550, "EUR") {
+		t.Errorf("CreateIntent() = %+v", intent)
+	}
+
+	refund, err := stripe.Refund(context.Background(), intent.ID, money.New(1000, "EUR"), "refund-1")
+	if err != nil || refund.Amount != money.New(1000, "EUR") {
+		t.Errorf("Refund() = %+v, %v", refund, err)
+	}
+
+	payload := []byte(`{"id":"evt_1","type":"payment_intent.succeeded","data":{"object":{"id":"pi_1"}}}`)
+	header := http.Header{}
+	header.Set(SignatureHeader, Sign("whsec_test", payload, time.Now()))
+	event, err := stripe.VerifyWebhook(payload, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Type != EventPaymentSucceeded || event.IntentID != "pi_1" {
+		t.Errorf("VerifyWebhook() = %+v", event)
+	}
+}
@@ -0,0 +1,72 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader carries webhook signatures in the Stripe format
+// "t=<unix time>,v1=<hex HMAC-SHA256 of "<time>.<payload>">"
+const SignatureHeader = "Stripe-Signature"
+
+// SignatureTolerance is how old a signed webhook may be, to limit replays
+const SignatureTolerance = 5 * time.Minute
+
+func computeSignature(secret string, timestamp int64, payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// Sign returns the signature header value for a payload
+func Sign(secret string, payload []byte, now time.Time) string {
+	timestamp := now.Unix()
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(computeSignature(secret, timestamp, payload)))
+}
+
+// VerifySignature checks a signature header value against the payload. Any
+// of several v1 signatures may match, which allows rotating secrets.
+func VerifySignature(secret string, payload []byte, header string, now time.Time) error {
+	var timestamp int64
+	var signatures [][]byte
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			t, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return ErrInvalidSignature
+			}
+			timestamp = t
+		case "v1":
+			if sig, err := hex.DecodeString(value); err == nil {
+				signatures = append(signatures, sig)
+			}
+		}
+	}
+	if timestamp == 0 || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+
+	age := now.Sub(time.Unix(timestamp, 0))
+	if age > SignatureTolerance || age < -SignatureTolerance {
+		return ErrInvalidSignature
+	}
+
+	expected := computeSignature(secret, timestamp, payload)
+	for _, sig := range signatures {
+		if hmac.Equal(sig, expected) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
@@ -0,0 +1,180 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"online-task/pkg/money"
+)
+
+const stripeDefaultBaseURL = "https://api.stripe.com"
+
+// StripeProvider talks to the Stripe REST API, or any server compatible
+// with it such as stripe-mock
+type StripeProvider struct {
+	SecretKey     string
+	WebhookSecret string
+	BaseURL       string
+	Client        *http.Client
+}
+
+// NewStripe returns a Stripe provider; baseURL may be empty for the live API
+func NewStripe(secretKey, webhookSecret, baseURL string) *StripeProvider {
+	if baseURL == "" {
+		baseURL = stripeDefaultBaseURL
+	}
+	return &StripeProvider{
+		SecretKey:     secretKey,
+		WebhookSecret: webhookSecret,
+		BaseURL:       strings.TrimRight(baseURL, "/"),
+		Client:        &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (s *StripeProvider) Name() string {
+	return "stripe"
+}
+
+type stripeIntent struct {
+	ID           string `json:"id"`
+	Amount       int64  `json:"amount"`
+	Currency     string `json:"currency"`
+	Status       string `json:"status"`
+	ClientSecret string `json:"client_secret"`
+}
+
+func (i stripeIntent) toIntent() Intent {
+	return Intent{
+		ID:           i.ID,
+		Amount:       money.New(i.Amount, strings.ToUpper(i.Currency)),
+		Status:       stripeStatus(i.Status),
+		ClientSecret: i.ClientSecret,
+	}
+}
+
+// stripeStatus maps Stripe's payment intent statuses onto ours
+func stripeStatus(status string) string {
+	switch status {
+	case "succeeded":
+		return StatusSucceeded
+	case "processing":
+		return StatusProcessing
+	case "canceled":
+		return StatusCanceled
+	default:
+		return StatusRequiresPayment
+	}
+}
+
+// post sends a form-encoded request and decodes the JSON response into out
+func (s *StripeProvider) post(ctx context.Context, path string, form url.Values, idempotencyKey string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.BaseURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(s.SecretKey, "")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("stripe request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var body struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&body)
+		return fmt.Errorf("stripe %s: %d %s", path, resp.StatusCode, body.Error.Message)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (s *StripeProvider) CreateIntent(ctx context.Context, amount money.Money, reference string) (Intent, error) {
+	form := url.Values{}
+	form.Set("amount", strconv.FormatInt(amount.Amount, 10))
+	form.Set("currency", strings.ToLower(amount.Currency))
+	form.Set("automatic_payment_methods[enabled]", "true")
+	form.Set("metadata[reference]", reference)
+
+	var intent stripeIntent
+	if err := s.post(ctx, "/v1/payment_intents", form, "intent-"+reference, &intent); err != nil {
+		return Intent{}, err
+	}
+	return intent.toIntent(), nil
+}
+
+func (s *StripeProvider) Confirm(ctx context.Context, intentID, paymentMethod string) (Intent, error) {
+	form := url.Values{}
+	if paymentMethod != "" {
+		form.Set("payment_method", paymentMethod)
+	}
+
+	var intent stripeIntent
+	if err := s.post(ctx, "/v1/payment_intents/"+url.PathEscape(intentID)+"/confirm", form, "", &intent); err != nil {
+		return Intent{}, err
+	}
+	return intent.toIntent(), nil
+}
+
+func (s *StripeProvider) Refund(ctx context.Context, intentID string, amount money.Money, reference string) (Refund, error) {
+	form := url.Values{}
+	form.Set("payment_intent", intentID)
+	form.Set("amount", strconv.FormatInt(amount.Amount, 10))
+
+	var refund struct {
+		ID       string `json:"id"`
+		Amount   int64  `json:"amount"`
+		Currency string `json:"currency"`
+		Status   string `json:"status"`
+	}
+	if err := s.post(ctx, "/v1/refunds", form, "refund-"+reference, &refund); err != nil {
+		return Refund{}, err
+	}
+	return Refund{
+		ID:       refund.ID,
+		IntentID: intentID,
+		Amount:   money.New(refund.Amount, strings.ToUpper(refund.Currency)),
+		Status:   refund.Status,
+	}, nil
+}
+
+func (s *StripeProvider) VerifyWebhook(payload []byte, header http.Header) (Event, error) {
+	if err := VerifySignature(s.WebhookSecret, payload, header.Get(SignatureHeader), time.Now()); err != nil {
+		return Event{}, err
+	}
+
+	var event struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+		Data struct {
+			Object struct {
+				ID string `json:"id"`
+			} `json:"object"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return Event{}, fmt.Errorf("decode stripe event: %w", err)
+	}
+
+	eventType := event.Type
+	switch event.Type {
+	case "payment_intent.succeeded":
+		eventType = EventPaymentSucceeded
+	case "payment_intent.payment_failed":
+		eventType = EventPaymentFailed
+	}
+	return Event{ID: event.ID, Type: eventType, IntentID: event.Data.Object.ID}, nil
+}
@@ -0,0 +1,95 @@
+package scanner
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+const clamdChunkSize = 64 * 1024
+
+// ClamAVScanner streams files to a clamd daemon over TCP using the INSTREAM command
+type ClamAVScanner struct {
+	Address string
+	Timeout time.Duration
+}
+
+func (s *ClamAVScanner) Scan(r io.Reader) (Result, error) {
+	conn, err := net.DialTimeout("tcp", s.Address, s.Timeout)
+	if err != nil {
+		return Result{Status: StatusError, Engine: "clamav"}, fmt.Errorf("connect to clamd: %w", err)
+	}
+	defer conn.Close()
+
+	if s.Timeout > 0 {
+		conn.SetDeadline(time.Now().Add(s.Timeout))
+	}
+
+	if err := s.stream(conn, r); err != nil {
+		return Result{Status: StatusError, Engine: "clamav"}, err
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && err != io.EOF {
+		return Result{Status: StatusError, Engine: "clamav"}, fmt.Errorf("read clamd reply: %w", err)
+	}
+	return parseClamdReply(reply)
+}
+
+// stream sends the content as length-prefixed chunks terminated by a zero-length chunk
+func (s *ClamAVScanner) stream(conn net.Conn, r io.Reader) error {
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return fmt.Errorf("send clamd command: %w", err)
+	}
+
+	buf := make([]byte, clamdChunkSize)
+	size := make([]byte, 4)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, err := conn.Write(size); err != nil {
+				return fmt.Errorf("send chunk to clamd: %w", err)
+			}
+			if _, err := conn.Write(buf[:n]); err != nil {
+				return fmt.Errorf("send chunk to clamd: %w", err)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("read file: %w", err)
+		}
+	}
+
+	binary.BigEndian.PutUint32(size, 0)
+	if _, err := conn.Write(size); err != nil {
+		return fmt.Errorf("finish clamd stream: %w", err)
+	}
+	return nil
+}
+
+// parseClamdReply interprets replies such as "stream: OK" and
+// "stream: Eicar-Test-Signature FOUND"
+func parseClamdReply(reply string) (Result, error) {
+	reply = strings.TrimSpace(strings.TrimRight(reply, "\x00"))
+	result := Result{Engine: "clamav"}
+
+	switch {
+	case strings.HasSuffix(reply, " OK"):
+		result.Status = StatusClean
+		return result, nil
+	case strings.HasSuffix(reply, " FOUND"):
+		result.Status = StatusInfected
+		result.Signature = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(reply, "stream:"), "FOUND"))
+		return result, nil
+	default:
+		result.Status = StatusError
+		return result, fmt.Errorf("clamd: %s", reply)
+	}
+}
@@ -0,0 +1,116 @@
+package scanner
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startFakeClamd serves the INSTREAM command and reports any stream
+// containing the EICAR marker as infected
+func startFakeClamd(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go handleFakeClamd(conn)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func handleFakeClamd(conn net.Conn) {
+	defer conn.Close()
+
+	command := make([]byte, len("zINSTREAM\x00"))
+	if _, err := io.ReadFull(conn, command); err != nil || string(command) != "zINSTREAM\x00" {
+		conn.Write([]byte("UNKNOWN COMMAND\x00"))
+		return
+	}
+
+	var data bytes.Buffer
+	size := make([]byte, 4)
+	for {
+		if _, err := io.ReadFull(conn, size); err != nil {
+			return
+		}
+		n := binary.BigEndian.Uint32(size)
+		if n == 0 {
+			break
+		}
+		if _, err := io.CopyN(&data, conn, int64(n)); err != nil {
+			return
+		}
+	}
+
+	if strings.Contains(data.String(), "EICAR-STANDARD-ANTIVIRUS-TEST-FILE") {
+		conn.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+		return
+	}
+	conn.Write([]byte("stream: OK\x00"))
+}
+
+func TestClamAVScanner(t *testing.T) {
+	address := startFakeClamd(t)
+	s := &ClamAVScanner{Address: address, Timeout: 5 * time.Second}
+
+	tests := []struct {
+		name          string
+		content       []byte
+		wantStatus    string
+		wantSignature string
+	}{
+		{
+			name:       "clean file",
+			content:    bytes.Repeat([]byte("x"), 3*clamdChunkSize+17),
+			wantStatus: StatusClean,
+		},
+		{
+			name:          "infected file",
+			content:       []byte(`X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`),
+			wantStatus:    StatusInfected,
+			wantSignature: "Eicar-Test-Signature",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := s.Scan(bytes.NewReader(tt.content))
+			if err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+			if result.Status != tt.wantStatus || result.Signature != tt.wantSignature {
+				t.Errorf("Scan() = %+v, want status %q signature %q", result, tt.wantStatus, tt.wantSignature)
+			}
+		})
+	}
+}
+
+func TestClamAVScannerUnavailable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	s := &ClamAVScanner{Address: address, Timeout: time.Second}
+	result, err := s.Scan(strings.NewReader("data"))
+	if err == nil || result.Status != StatusError {
+		t.Errorf("Scan() = %+v, %v, want error status", result, err)
+	}
+}
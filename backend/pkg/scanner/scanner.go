@@ -0,0 +1,42 @@
+package scanner
+
+import (
+	"io"
+	"time"
+)
+
+// Scan statuses
+const (
+	StatusClean    = "clean"
+	StatusInfected = "infected"
+	StatusSkipped  = "skipped"
+	StatusError    = "error"
+)
+
+// Result describes the outcome of scanning a file
+type Result struct {
+	Status    string `json:"status" example:"clean"`
+	Signature string `json:"signature,omitempty" example:"Eicar-Test-Signature"`
+	Engine    string `json:"engine" example:"clamav"`
+}
+
+// Scanner inspects file content for malware
+type Scanner interface {
+	Scan(r io.Reader) (Result, error)
+}
+
+// NoopScanner accepts every file without inspecting it
+type NoopScanner struct{}
+
+func (NoopScanner) Scan(r io.Reader) (Result, error) {
+	return Result{Status: StatusSkipped, Engine: "none"}, nil
+}
+
+// New returns a ClamAV scanner when a clamd address is given and a no-op
+// scanner otherwise
+func New(clamdAddress string) Scanner {
+	if clamdAddress == "" {
+		return NoopScanner{}
+	}
+	return &ClamAVScanner{Address: clamdAddress, Timeout: 60 * time.Second}
+}
@@ -0,0 +1,25 @@
+package slug
+
+import "testing"
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "simple", in: "Music", want: "music"},
+		{name: "spaces and symbols", in: "  Live Music & Arts ", want: "live-music-arts"},
+		{name: "digits", in: "Tech 2024", want: "tech-2024"},
+		{name: "unicode letters", in: "Café Concerts", want: "café-concerts"},
+		{name: "only symbols", in: "!!!", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Make(tt.in); got != tt.want {
+				t.Errorf("Make(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
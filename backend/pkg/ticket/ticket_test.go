@@ -0,0 +1,102 @@
+package ticket
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	signer, err := GenerateSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, _ := GenerateSigner()
+
+	claims := Claims{BookingID: "booking-1", EventID: "event-1", Quantity: 2, IssuedAt: 1700000000}
+	token, err := signer.Sign(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, signature, _ := strings.Cut(token, ".")
+	forged, _ := other.Sign(Claims{BookingID: "booking-2"})
+	forgedPayload, _, _ := strings.Cut(forged, ".")
+
+	tests := []struct {
+		name    string
+		key     ed25519.PublicKey
+		token   string
+		wantErr bool
+	}{
+		{name: "valid", key: signer.PublicKey(), token: token},
+		{name: "surrounding whitespace", key: signer.PublicKey(), token: " " + token + "\n"},
+		{name: "other key", key: other.PublicKey(), token: token, wantErr: true},
+		{name: "swapped payload", key: signer.PublicKey(), token: forgedPayload + "." + signature, wantErr: true},
+		{name: "no signature", key: signer.PublicKey(), token: payload, wantErr: true},
+		{name: "garbage", key: signer.PublicKey(), token: "not.a-ticket", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Verify(tt.key, tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != claims {
+				t.Errorf("Verify() = %+v, want %+v", got, claims)
+			}
+		})
+	}
+}
+
+func TestSignaturesAreBoundToTheirContext(t *testing.T) {
+	signer, err := GenerateSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A manifest shaped like claims must not pass as a ticket
+	payload, signature := signer.SignPayload(ContextManifest, []byte(`{"b":"booking-1","e":"event-1","q":1}`))
+	if _, err := Verify(signer.PublicKey(), payload+"."+signature); err == nil {
+		t.Error("Verify() accepted a manifest signature as a ticket")
+	}
+	if _, err := VerifyPayload(signer.PublicKey(), ContextManifest, payload, signature); err != nil {
+		t.Errorf("VerifyPayload() manifest error = %v", err)
+	}
+
+	// Nor a ticket as a manifest
+	token, _ := signer.Sign(Claims{BookingID: "booking-1", EventID: "event-1", Quantity: 1})
+	payload, signature, _ = strings.Cut(token, ".")
+	if _, err := VerifyPayload(signer.PublicKey(), ContextManifest, payload, signature); err == nil {
+		t.Error("VerifyPayload() accepted a ticket signature as a manifest")
+	}
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	key := ed25519.NewKeyFromSeed(seed)
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{name: "seed", input: base64.StdEncoding.EncodeToString(seed)},
+		{name: "private key", input: base64.StdEncoding.EncodeToString(key)},
+		{name: "wrong length", input: base64.StdEncoding.EncodeToString(seed[:16]), wantErr: true},
+		{name: "not base64", input: "***", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePrivateKey(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePrivateKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.Equal(key) {
+				t.Error("ParsePrivateKey() returned a different key")
+			}
+		})
+	}
+}
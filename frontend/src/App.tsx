@@ -13,6 +13,7 @@ const Login = lazy(() => import('./pages/Login'));
 const Register = lazy(() => import('./pages/Register'));
 const Dashboard = lazy(() => import('./pages/admin/Dashboard'));
 const TagsManagement = lazy(() => import('./pages/admin/TagsManagement'));
+const VenuesManagement = lazy(() => import('./pages/admin/VenuesManagement'));
 const ProtectedRoute = lazy(() => import('./components/ProtectedRoute'));
 
 function App() {
@@ -36,6 +37,7 @@ function App() {
                 >
                   <Route path="dashboard" element={<Dashboard />} />
                   <Route path="tags" element={<TagsManagement />} />
+                  <Route path="venues" element={<VenuesManagement />} />
                 </Route>
               </Route>
             </Routes>
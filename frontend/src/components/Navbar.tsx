@@ -167,6 +167,9 @@ const Navbar = ({ children }: NavbarProps) => {
               <MenuItem onClick={() => handleNavigate('/admin/tags')}>
                 Tag Management
               </MenuItem>
+              <MenuItem onClick={() => handleNavigate('/admin/venues')}>
+                Venue Management
+              </MenuItem>
             </>
           )}
           <MenuItem onClick={logout}>Logout</MenuItem>
@@ -195,6 +198,9 @@ const Navbar = ({ children }: NavbarProps) => {
                   <MenuItem onClick={() => handleNavigate('/admin/tags')}>
                     Tag Management
                   </MenuItem>
+                  <MenuItem onClick={() => handleNavigate('/admin/venues')}>
+                    Venue Management
+                  </MenuItem>
                 </>
               )}
               <MenuItem onClick={logout}>Logout</MenuItem>
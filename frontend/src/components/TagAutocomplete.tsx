@@ -0,0 +1,56 @@
+import { useEffect, useState } from 'react';
+import { Autocomplete, TextField } from '@mui/material';
+import type { TagWithCount } from '../types';
+import api from '../services/api';
+
+interface TagAutocompleteProps {
+  label: string;
+  value: TagWithCount | null;
+  onChange: (tag: TagWithCount | null) => void;
+  excludeIds?: string[];
+}
+
+const TagAutocomplete = ({ label, value, onChange, excludeIds = [] }: TagAutocompleteProps) => {
+  const [input, setInput] = useState('');
+  const [options, setOptions] = useState<TagWithCount[]>([]);
+
+  useEffect(() => {
+    let active = true;
+    // Debounce so typing does not send a request per keystroke
+    const timer = setTimeout(async () => {
+      try {
+        const data = await api.tags.autocomplete(input);
+        if (active) {
+          setOptions(data.filter((tag) => !excludeIds.includes(tag.id)));
+        }
+      } catch (error) {
+        console.error('Error fetching tag suggestions:', error);
+      }
+    }, 250);
+
+    return () => {
+      active = false;
+      clearTimeout(timer);
+    };
+  }, [input, excludeIds.join(',')]);
+
+  return (
+    <Autocomplete
+      value={value}
+      options={options}
+      filterOptions={(x) => x}
+      getOptionLabel={(tag) => tag.name}
+      renderOption={(props, tag) => (
+        <li {...props} key={tag.id}>
+          {tag.name} ({tag.eventCount})
+        </li>
+      )}
+      isOptionEqualToValue={(option, selected) => option.id === selected.id}
+      onChange={(_, tag) => onChange(tag)}
+      onInputChange={(_, text) => setInput(text)}
+      renderInput={(params) => <TextField {...params} margin="normal" label={label} />}
+    />
+  );
+};
+
+export default TagAutocomplete;
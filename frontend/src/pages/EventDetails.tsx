@@ -13,11 +13,14 @@ import {
   DialogTitle,
   DialogContent,
   DialogActions,
+  MenuItem,
+  TextField,
 } from '@mui/material';
 import { useSelector } from 'react-redux';
 import type { RootState } from '../store';
-import type { Event } from '../types';
+import type { BookingQuote, Event, Money } from '../types';
 import api from '../services/api';
+import { formatMoney } from '../utils/money';
 
 const EventDetails = () => {
   const { id } = useParams<{ id: string }>();
@@ -26,6 +29,12 @@ const EventDetails = () => {
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState<string | null>(null);
   const [showSuccess, setShowSuccess] = useState(false);
+  const [pendingPayment, setPendingPayment] = useState<{ id: string; total: Money; expiresAt?: string } | null>(null);
+  const [ticketTypeId, setTicketTypeId] = useState('');
+  const [quantity, setQuantity] = useState(1);
+  const [promoCode, setPromoCode] = useState('');
+  const [quote, setQuote] = useState<BookingQuote | null>(null);
+  const [promoError, setPromoError] = useState<string | null>(null);
   const { isAuthenticated } = useSelector((state: RootState) => state.auth);
 
   useEffect(() => {
@@ -33,6 +42,7 @@ const EventDetails = () => {
       try {
         const data = await api.events.getById(id!);
         setEvent(data);
+        setTicketTypeId(data.ticketTypes?.[0]?.id ?? '');
         setError(null);
       } catch (error) {
         console.error('Error fetching event:', error);
@@ -45,6 +55,25 @@ const EventDetails = () => {
     fetchEvent();
   }, [id]);
 
+  // A quote is only valid for the selection it was made for
+  useEffect(() => {
+    setQuote(null);
+  }, [ticketTypeId, quantity, promoCode]);
+
+  const handleApplyPromo = async () => {
+    if (!isAuthenticated) {
+      navigate('/login');
+      return;
+    }
+
+    try {
+      setQuote(await api.bookings.quote(id!, ticketTypeId || undefined, quantity, promoCode));
+      setPromoError(null);
+    } catch (error) {
+      setPromoError(error instanceof Error ? error.message : 'Invalid promo code');
+    }
+  };
+
   const handleBooking = async () => {
     if (!isAuthenticated) {
       navigate('/login');
@@ -52,8 +81,12 @@ const EventDetails = () => {
     }
 
     try {
-      await api.bookings.create(id!);
-      setShowSuccess(true);
+      const booking = await api.bookings.create(id!, ticketTypeId || undefined, quantity, promoCode || undefined);
+      if (booking.status === 'pending_payment') {
+        setPendingPayment({ id: booking.id, total: booking.total, expiresAt: booking.paymentExpiresAt });
+      } else {
+        setShowSuccess(true);
+      }
       setError(null);
     } catch (error) {
       console.error('Error booking event:', error);
@@ -61,6 +94,17 @@ const EventDetails = () => {
     }
   };
 
+  const handlePayment = async () => {
+    try {
+      await api.bookings.pay(pendingPayment!.id);
+      setPendingPayment(null);
+      setShowSuccess(true);
+    } catch (error) {
+      console.error('Error paying for booking:', error);
+      setError(error instanceof Error ? error.message : 'Payment failed');
+    }
+  };
+
   if (loading) {
     return (
       <Box sx={{ textAlign: 'center', py: 4 }}>
@@ -114,8 +158,14 @@ const EventDetails = () => {
             {event.name}
           </Typography>
           <Typography variant="h6" color="text.secondary" gutterBottom>
-            {new Date(event.date).toLocaleDateString()} at {event.location}
+            {new Date(event.date).toLocaleString(undefined, { timeZone: event.timeZone })} –{' '}
+            {new Date(event.endDate).toLocaleString(undefined, { timeZone: event.timeZone })} ({event.timeZone}) at {event.location}
           </Typography>
+          {event.venue?.accessibility && (
+            <Typography variant="body2" color="text.secondary" gutterBottom>
+              Accessibility: {event.venue.accessibility}
+            </Typography>
+          )}
           <Box sx={{ my: 2 }}>
             {event.tags?.map((tag) => (
               <Chip
@@ -129,9 +179,57 @@ const EventDetails = () => {
           <Typography variant="body1" paragraph>
             {event.description}
           </Typography>
-          <Typography variant="h6" gutterBottom>
-            Price: ${event.price}
-          </Typography>
+          {event.ticketTypes && event.ticketTypes.length > 0 ? (
+            <TextField
+              select
+              fullWidth
+              label="Ticket type"
+              value={ticketTypeId}
+              onChange={(e) => setTicketTypeId(e.target.value)}
+              sx={{ mb: 2 }}
+            >
+              {event.ticketTypes.map((ticketType) => (
+                <MenuItem
+                  key={ticketType.id}
+                  value={ticketType.id}
+                  disabled={ticketType.quota > 0 && ticketType.sold >= ticketType.quota}
+                >
+                  {ticketType.name} – {formatMoney(ticketType.price)}
+                  {ticketType.quota > 0 && ` (${Math.max(ticketType.quota - ticketType.sold, 0)} left)`}
+                </MenuItem>
+              ))}
+            </TextField>
+          ) : (
+            <Typography variant="h6" gutterBottom>
+              Price: {formatMoney(event.price)}
+            </Typography>
+          )}
+          <TextField
+            type="number"
+            label="Quantity"
+            value={quantity}
+            onChange={(e) => setQuantity(Math.min(Math.max(Number(e.target.value) || 1, 1), 20))}
+            inputProps={{ min: 1, max: 20 }}
+            sx={{ width: 120 }}
+          />
+          <Box sx={{ display: 'flex', gap: 1, mt: 2 }}>
+            <TextField
+              label="Promo code"
+              value={promoCode}
+              onChange={(e) => setPromoCode(e.target.value.toUpperCase())}
+              error={promoError !== null}
+              helperText={promoError}
+              size="small"
+            />
+            <Button variant="outlined" onClick={handleApplyPromo} disabled={!promoCode}>
+              Apply
+            </Button>
+          </Box>
+          {quote && (
+            <Typography variant="body1" sx={{ mt: 1 }}>
+              {formatMoney(quote.subtotal)} − {formatMoney(quote.discount)} = <strong>{formatMoney(quote.total)}</strong>
+            </Typography>
+          )}
           <Button
             variant="contained"
             size="large"
@@ -144,6 +242,23 @@ const EventDetails = () => {
         </Grid>
       </Grid>
 
+      <Dialog open={pendingPayment !== null} onClose={() => setPendingPayment(null)}>
+        <DialogTitle>Complete your payment</DialogTitle>
+        <DialogContent>
+          <Typography>
+            Your tickets are held until{' '}
+            {pendingPayment?.expiresAt ? new Date(pendingPayment.expiresAt).toLocaleTimeString() : 'the payment times out'}.
+            Pay {pendingPayment && formatMoney(pendingPayment.total)} to confirm the booking.
+          </Typography>
+        </DialogContent>
+        <DialogActions>
+          <Button onClick={() => setPendingPayment(null)}>Later</Button>
+          <Button onClick={handlePayment} variant="contained">
+            Pay now
+          </Button>
+        </DialogActions>
+      </Dialog>
+
       <Dialog open={showSuccess} onClose={() => setShowSuccess(false)}>
         <DialogTitle>Booking Successful!</DialogTitle>
         <DialogContent>
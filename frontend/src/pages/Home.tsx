@@ -19,6 +19,7 @@ import {
 import { useNavigate } from 'react-router-dom';
 import type { Event, Tag } from '../types';
 import api from '../services/api';
+import { formatMoney } from '../utils/money';
 import LoadingSpinner from '../components/LoadingSpinner';
 import ErrorMessage from '../components/ErrorMessage';
 
@@ -186,7 +187,7 @@ const Home = () => {
                 <Box sx={{ mt: 2, display: 'flex', flexWrap: 'wrap', gap: 1 }}>
                   <Chip label={event.category} size="small" color="primary" />
                   <Chip
-                    label={`$${event.price}`}
+                    label={formatMoney(event.price)}
                     size="small"
                     color="secondary"
                   />
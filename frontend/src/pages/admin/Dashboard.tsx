@@ -27,8 +27,9 @@ import {
 import { PhotoCamera } from '@mui/icons-material';
 import { useFormik } from 'formik';
 import * as yup from 'yup';
-import type { Event, Tag } from '../../types';
+import type { Event, EventSeries, Tag, Venue } from '../../types';
 import api from '../../services/api';
+import { formatMoney, toMoney } from '../../utils/money';
 
 const MAX_FILE_SIZE = 3 * 1024 * 1024; // 3MB
 const ALLOWED_FILE_TYPES = ['image/jpeg', 'image/png', 'image/gif'];
@@ -38,8 +39,21 @@ const validationSchema = yup.object({
   description: yup.string().required('Description is required'),
   category: yup.string().required('Category is required'),
   date: yup.string().required('Date is required'),
-  location: yup.string().required('Location is required'),
-  price: yup.number().required('Price is required').min(0, 'Price must be positive'),
+  endDate: yup
+    .string()
+    .required('End date is required')
+    .test('after-start', 'End date must be after the start', function (value) {
+      return !value || !this.parent.date || new Date(value) > new Date(this.parent.date);
+    }),
+  timeZone: yup.string().required('Time zone is required'),
+  location: yup.string().when('venueId', {
+    is: (venueId: string) => !venueId,
+    then: (schema) => schema.required('Location is required'),
+  }),
+  price: yup.string()
+    .required('Price is required')
+    .matches(/^\d+(\.\d+)?$/, 'Price must be a positive amount such as 25.50'),
+  currency: yup.string().required('Currency is required').matches(/^[A-Za-z]{3}$/, 'Use a three-letter ISO 4217 code'),
   image: yup.string().required('Image URL is required'),
   tagIds: yup.array().of(yup.string()).min(1, 'At least one tag is required'),
 });
@@ -49,12 +63,16 @@ const Dashboard = () => {
   const [tags, setTags] = useState<Tag[]>([]);
   const [open, setOpen] = useState(false);
   const [editingEvent, setEditingEvent] = useState<Event | null>(null);
+  const [editScope, setEditScope] = useState<'this' | 'following' | 'all'>('this');
+  const [venues, setVenues] = useState<Venue[]>([]);
   const [error, setError] = useState<string | null>(null);
+  const [warning, setWarning] = useState<string | null>(null);
   const [uploadProgress, setUploadProgress] = useState<boolean>(false);
 
   useEffect(() => {
     fetchEvents();
     fetchTags();
+    fetchVenues();
   }, []);
 
   const fetchEvents = async () => {
@@ -66,6 +84,15 @@ const Dashboard = () => {
     }
   };
 
+  const fetchVenues = async () => {
+    try {
+      const data = await api.venues.getAll();
+      setVenues(data);
+    } catch (error) {
+      setError(error instanceof Error ? error.message : 'Failed to fetch venues');
+    }
+  };
+
   const fetchTags = async () => {
     try {
       const data = await api.tags.getAll();
@@ -78,6 +105,7 @@ const Dashboard = () => {
   const handleClose = () => {
     setOpen(false);
     setEditingEvent(null);
+    setEditScope('this');
     setError(null);
     formik.resetForm();
   };
@@ -121,26 +149,52 @@ const Dashboard = () => {
       description: '',
       category: '',
       date: '',
+      endDate: '',
+      timeZone: Intl.DateTimeFormat().resolvedOptions().timeZone,
       location: '',
+      venueId: '',
       price: '',
+      currency: 'USD',
       image: '',
+      salesStartAt: '',
+      salesEndAt: '',
+      capacity: '0',
+      rrule: '',
       tagIds: [] as string[],
     },
     validationSchema: validationSchema,
     onSubmit: async (values) => {
       try {
+        const { rrule, currency, ...rest } = values;
         const formattedData = {
-          ...values,
-          price: Number(values.price),
+          ...rest,
+          price: toMoney(values.price, currency.toUpperCase()),
+          capacity: Number(values.capacity),
           date: formatDateToUTC(values.date),
+          endDate: formatDateToUTC(values.endDate),
+          salesStartAt: values.salesStartAt ? formatDateToUTC(values.salesStartAt) : undefined,
+          salesEndAt: values.salesEndAt ? formatDateToUTC(values.salesEndAt) : undefined,
+          venueId: values.venueId || undefined,
           tagIds: values.tagIds,
         };
 
-        if (editingEvent) {
-          await api.events.update(editingEvent.id, formattedData);
+        let saved: Event | EventSeries;
+        if (editingEvent && editScope === 'all' && editingEvent.seriesId) {
+          saved = await api.series.update(editingEvent.seriesId, formattedData);
+        } else if (editingEvent && editScope === 'following') {
+          saved = await api.events.updateFollowing(editingEvent.id, formattedData);
+        } else if (editingEvent) {
+          saved = await api.events.update(editingEvent.id, formattedData);
         } else {
-          await api.events.create(formattedData);
+          saved = await api.events.create({ ...formattedData, rrule: rrule || undefined });
         }
+
+        // Overlaps at the same venue are allowed but worth pointing out
+        const savedEvents = 'rrule' in saved ? saved.events ?? [] : [saved];
+        const conflicts = savedEvents.flatMap((e) => e.venueConflicts ?? []);
+        setWarning(conflicts.length > 0
+          ? `Overlaps with other events at this venue: ${[...new Set(conflicts.map((c) => c.name))].join(', ')}`
+          : null);
         handleClose();
         fetchEvents();
       } catch (error) {
@@ -156,14 +210,39 @@ const Dashboard = () => {
       description: event.description,
       category: event.category,
       date: new Date(event.date).toISOString().slice(0, 16),
+      endDate: new Date(event.endDate).toISOString().slice(0, 16),
+      timeZone: event.timeZone,
       location: event.location,
-      price: event.price.toString(),
+      venueId: event.venueId ?? '',
+      price: event.price.decimal ?? '',
+      currency: event.price.currency,
       image: event.image,
+      salesStartAt: event.salesStartAt ? new Date(event.salesStartAt).toISOString().slice(0, 16) : '',
+      salesEndAt: event.salesEndAt ? new Date(event.salesEndAt).toISOString().slice(0, 16) : '',
+      capacity: event.capacity.toString(),
+      rrule: '',
       tagIds: event.tags?.map(tag => tag.id) || [],
     });
     setOpen(true);
   };
 
+  const handleStatusChange = async (event: Event, action: 'publish' | 'unpublish' | 'cancel') => {
+    let note: string | undefined;
+    if (action === 'cancel') {
+      const reason = window.prompt(`Cancel "${event.name}"? Bookings will be cancelled and attendees notified. Reason (optional):`);
+      if (reason === null) {
+        return;
+      }
+      note = reason;
+    }
+    try {
+      await api.events.changeStatus(event.id, action, note);
+      fetchEvents();
+    } catch (error) {
+      setError(error instanceof Error ? error.message : 'Failed to update event status');
+    }
+  };
+
   const handleDelete = async (id: string) => {
     if (window.confirm('Are you sure you want to delete this event?')) {
       try {
@@ -178,6 +257,11 @@ const Dashboard = () => {
   return (
     <Container>
       <Box sx={{ mt: 4, mb: 4 }}>
+        {warning && (
+          <Alert severity="warning" sx={{ mb: 2 }} onClose={() => setWarning(null)}>
+            {warning}
+          </Alert>
+        )}
         {error && (
           <Alert severity="error" sx={{ mb: 2 }} onClose={() => setError(null)}>
             {error}
@@ -198,6 +282,7 @@ const Dashboard = () => {
             <TableHead>
               <TableRow>
                 <TableCell>Name</TableCell>
+                <TableCell>Status</TableCell>
                 <TableCell>Category</TableCell>
                 <TableCell>Date</TableCell>
                 <TableCell>Location</TableCell>
@@ -211,12 +296,13 @@ const Dashboard = () => {
               {events.map((event) => (
                 <TableRow key={event.id}>
                   <TableCell>{event.name}</TableCell>
+                  <TableCell>{event.status}</TableCell>
                   <TableCell>{event.category}</TableCell>
                   <TableCell>
-                    {new Date(event.date).toLocaleString()}
+                    {new Date(event.date).toLocaleString(undefined, { timeZone: event.timeZone })} ({event.timeZone})
                   </TableCell>
                   <TableCell>{event.location}</TableCell>
-                  <TableCell>${event.price}</TableCell>
+                  <TableCell>{formatMoney(event.price)}</TableCell>
                   <TableCell>
                     {event.tags?.map(tag => tag.name).join(', ')}
                   </TableCell>
@@ -235,6 +321,21 @@ const Dashboard = () => {
                     >
                       Edit
                     </Button>
+                    {(event.status === 'draft' || event.status === 'postponed') && (
+                      <Button size="small" onClick={() => handleStatusChange(event, 'publish')} sx={{ mr: 1 }}>
+                        Publish
+                      </Button>
+                    )}
+                    {event.status === 'published' && (
+                      <Button size="small" onClick={() => handleStatusChange(event, 'unpublish')} sx={{ mr: 1 }}>
+                        Unpublish
+                      </Button>
+                    )}
+                    {event.status !== 'cancelled' && event.status !== 'completed' && (
+                      <Button size="small" color="warning" onClick={() => handleStatusChange(event, 'cancel')} sx={{ mr: 1 }}>
+                        Cancel
+                      </Button>
+                    )}
                     <Button
                       size="small"
                       color="error"
@@ -255,6 +356,20 @@ const Dashboard = () => {
           </DialogTitle>
           <form onSubmit={formik.handleSubmit}>
             <DialogContent>
+              {editingEvent?.seriesId && (
+                <FormControl fullWidth margin="normal">
+                  <InputLabel>Apply changes to</InputLabel>
+                  <Select
+                    value={editScope}
+                    label="Apply changes to"
+                    onChange={(e) => setEditScope(e.target.value as 'this' | 'following' | 'all')}
+                  >
+                    <MenuItem value="this">This occurrence</MenuItem>
+                    <MenuItem value="following">This and following occurrences</MenuItem>
+                    <MenuItem value="all">All occurrences</MenuItem>
+                  </Select>
+                </FormControl>
+              )}
               <TextField
                 fullWidth
                 margin="normal"
@@ -301,6 +416,55 @@ const Dashboard = () => {
                   shrink: true,
                 }}
               />
+              <TextField
+                fullWidth
+                margin="normal"
+                name="endDate"
+                label="End date"
+                type="datetime-local"
+                value={formik.values.endDate}
+                onChange={formik.handleChange}
+                error={formik.touched.endDate && Boolean(formik.errors.endDate)}
+                helperText={formik.touched.endDate && formik.errors.endDate}
+                InputLabelProps={{
+                  shrink: true,
+                }}
+              />
+              <TextField
+                fullWidth
+                margin="normal"
+                name="timeZone"
+                label="Time zone"
+                placeholder="Europe/Berlin"
+                value={formik.values.timeZone}
+                onChange={formik.handleChange}
+                error={formik.touched.timeZone && Boolean(formik.errors.timeZone)}
+                helperText={(formik.touched.timeZone && formik.errors.timeZone) || 'IANA name used to show the event in local time'}
+              />
+              <TextField
+                fullWidth
+                margin="normal"
+                name="salesStartAt"
+                label="Sales start (optional)"
+                type="datetime-local"
+                value={formik.values.salesStartAt}
+                onChange={formik.handleChange}
+                InputLabelProps={{
+                  shrink: true,
+                }}
+              />
+              <TextField
+                fullWidth
+                margin="normal"
+                name="salesEndAt"
+                label="Sales end (optional)"
+                type="datetime-local"
+                value={formik.values.salesEndAt}
+                onChange={formik.handleChange}
+                InputLabelProps={{
+                  shrink: true,
+                }}
+              />
               <TextField
                 fullWidth
                 margin="normal"
@@ -309,19 +473,67 @@ const Dashboard = () => {
                 value={formik.values.location}
                 onChange={formik.handleChange}
                 error={formik.touched.location && Boolean(formik.errors.location)}
-                helperText={formik.touched.location && formik.errors.location}
+                helperText={(formik.touched.location && formik.errors.location) || (formik.values.venueId && 'Leave empty to use the venue address')}
               />
+              <FormControl fullWidth margin="normal">
+                <InputLabel>Venue</InputLabel>
+                <Select
+                  name="venueId"
+                  value={formik.values.venueId}
+                  label="Venue"
+                  onChange={formik.handleChange}
+                >
+                  <MenuItem value="">No venue</MenuItem>
+                  {venues.map((venue) => (
+                    <MenuItem key={venue.id} value={venue.id}>
+                      {venue.name}
+                    </MenuItem>
+                  ))}
+                </Select>
+              </FormControl>
               <TextField
                 fullWidth
                 margin="normal"
                 name="price"
                 label="Price"
-                type="number"
+                inputProps={{ inputMode: 'decimal' }}
                 value={formik.values.price}
                 onChange={formik.handleChange}
                 error={formik.touched.price && Boolean(formik.errors.price)}
                 helperText={formik.touched.price && formik.errors.price}
               />
+              <TextField
+                fullWidth
+                margin="normal"
+                name="currency"
+                label="Currency"
+                value={formik.values.currency}
+                onChange={formik.handleChange}
+                error={formik.touched.currency && Boolean(formik.errors.currency)}
+                helperText={(formik.touched.currency && formik.errors.currency) || 'ISO 4217 code, e.g. USD or EGP'}
+              />
+              <TextField
+                fullWidth
+                margin="normal"
+                name="capacity"
+                label="Capacity"
+                type="number"
+                value={formik.values.capacity}
+                onChange={formik.handleChange}
+                helperText="0 for unlimited"
+              />
+              {!editingEvent && (
+                <TextField
+                  fullWidth
+                  margin="normal"
+                  name="rrule"
+                  label="Repeat (optional)"
+                  placeholder="FREQ=WEEKLY;BYDAY=TU;COUNT=10"
+                  value={formik.values.rrule}
+                  onChange={formik.handleChange}
+                  helperText="RFC 5545 recurrence rule; creates one event per occurrence"
+                />
+              )}
               <Box sx={{ mt: 2, mb: 2 }}>
                 <input
                   accept="image/*"
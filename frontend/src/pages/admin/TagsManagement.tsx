@@ -19,17 +19,21 @@ import {
 } from '@mui/material';
 import { useFormik } from 'formik';
 import * as yup from 'yup';
-import type { Tag } from '../../types';
+import type { TagWithCount } from '../../types';
 import api from '../../services/api';
+import TagAutocomplete from '../../components/TagAutocomplete';
 
 const validationSchema = yup.object({
   name: yup.string().required('Name is required'),
 });
 
 const TagsManagement = () => {
-  const [tags, setTags] = useState<Tag[]>([]);
+  const [tags, setTags] = useState<TagWithCount[]>([]);
   const [open, setOpen] = useState(false);
-  const [editingTag, setEditingTag] = useState<Tag | null>(null);
+  const [editingTag, setEditingTag] = useState<TagWithCount | null>(null);
+  const [parentTag, setParentTag] = useState<TagWithCount | null>(null);
+  const [mergingTag, setMergingTag] = useState<TagWithCount | null>(null);
+  const [mergeTarget, setMergeTarget] = useState<TagWithCount | null>(null);
 
   useEffect(() => {
     fetchTags();
@@ -37,7 +41,7 @@ const TagsManagement = () => {
 
   const fetchTags = async () => {
     try {
-      const data = await api.tags.getAll();
+      const data = await api.tags.getAllWithCounts();
       setTags(data);
     } catch (error) {
       console.error('Error fetching tags:', error);
@@ -47,6 +51,7 @@ const TagsManagement = () => {
   const handleClose = () => {
     setOpen(false);
     setEditingTag(null);
+    setParentTag(null);
     formik.resetForm();
   };
 
@@ -57,10 +62,11 @@ const TagsManagement = () => {
     validationSchema: validationSchema,
     onSubmit: async (values) => {
       try {
+        const tagData = { ...values, parentId: parentTag?.id ?? null };
         if (editingTag) {
-          await api.tags.update(editingTag.id, values);
+          await api.tags.update(editingTag.id, tagData);
         } else {
-          await api.tags.create(values);
+          await api.tags.create(tagData);
         }
         handleClose();
         fetchTags();
@@ -70,18 +76,22 @@ const TagsManagement = () => {
     },
   });
 
-  const handleEdit = (tag: Tag) => {
+  const handleEdit = (tag: TagWithCount) => {
     setEditingTag(tag);
+    setParentTag(tags.find((t) => t.id === tag.parentId) ?? null);
     formik.setValues({
       name: tag.name,
     });
     setOpen(true);
   };
 
-  const handleDelete = async (id: string) => {
-    if (window.confirm('Are you sure you want to delete this tag?')) {
+  const handleDelete = async (tag: TagWithCount) => {
+    const message = tag.eventCount > 0
+      ? `"${tag.name}" is used by ${tag.eventCount} event(s) and will be removed from them. Consider merging it instead. Delete anyway?`
+      : 'Are you sure you want to delete this tag?';
+    if (window.confirm(message)) {
       try {
-        await api.tags.delete(id);
+        await api.tags.delete(tag.id, tag.eventCount > 0);
         fetchTags();
       } catch (error) {
         console.error('Error deleting tag:', error);
@@ -89,6 +99,24 @@ const TagsManagement = () => {
     }
   };
 
+  const handleMergeClose = () => {
+    setMergingTag(null);
+    setMergeTarget(null);
+  };
+
+  const handleMerge = async () => {
+    if (!mergingTag || !mergeTarget) {
+      return;
+    }
+    try {
+      await api.tags.merge(mergingTag.id, mergeTarget.id);
+      handleMergeClose();
+      fetchTags();
+    } catch (error) {
+      console.error('Error merging tags:', error);
+    }
+  };
+
   return (
     <Container>
       <Box sx={{ mt: 4, mb: 4 }}>
@@ -106,6 +134,7 @@ const TagsManagement = () => {
             <TableHead>
               <TableRow>
                 <TableCell>Name</TableCell>
+                <TableCell>Events</TableCell>
                 <TableCell>Actions</TableCell>
               </TableRow>
             </TableHead>
@@ -113,6 +142,7 @@ const TagsManagement = () => {
               {tags.map((tag) => (
                 <TableRow key={tag.id}>
                   <TableCell>{tag.name}</TableCell>
+                  <TableCell>{tag.eventCount}</TableCell>
                   <TableCell>
                     <Button
                       size="small"
@@ -121,10 +151,17 @@ const TagsManagement = () => {
                     >
                       Edit
                     </Button>
+                    <Button
+                      size="small"
+                      onClick={() => setMergingTag(tag)}
+                      sx={{ mr: 1 }}
+                    >
+                      Merge
+                    </Button>
                     <Button
                       size="small"
                       color="error"
-                      onClick={() => handleDelete(tag.id)}
+                      onClick={() => handleDelete(tag)}
                     >
                       Delete
                     </Button>
@@ -151,6 +188,12 @@ const TagsManagement = () => {
                 error={formik.touched.name && Boolean(formik.errors.name)}
                 helperText={formik.touched.name && formik.errors.name}
               />
+              <TagAutocomplete
+                label="Parent Tag"
+                value={parentTag}
+                onChange={setParentTag}
+                excludeIds={editingTag ? [editingTag.id] : []}
+              />
             </DialogContent>
             <DialogActions>
               <Button onClick={handleClose}>Cancel</Button>
@@ -160,6 +203,28 @@ const TagsManagement = () => {
             </DialogActions>
           </form>
         </Dialog>
+
+        <Dialog open={Boolean(mergingTag)} onClose={handleMergeClose} maxWidth="sm" fullWidth>
+          <DialogTitle>Merge "{mergingTag?.name}"</DialogTitle>
+          <DialogContent>
+            <Typography variant="body2" color="text.secondary">
+              All {mergingTag?.eventCount ?? 0} event(s) tagged "{mergingTag?.name}" will be moved to the
+              selected tag, and "{mergingTag?.name}" will be deleted.
+            </Typography>
+            <TagAutocomplete
+              label="Merge into"
+              value={mergeTarget}
+              onChange={setMergeTarget}
+              excludeIds={mergingTag ? [mergingTag.id] : []}
+            />
+          </DialogContent>
+          <DialogActions>
+            <Button onClick={handleMergeClose}>Cancel</Button>
+            <Button variant="contained" onClick={handleMerge} disabled={!mergeTarget}>
+              Merge
+            </Button>
+          </DialogActions>
+        </Dialog>
       </Box>
     </Container>
   );
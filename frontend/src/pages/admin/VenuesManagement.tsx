@@ -0,0 +1,270 @@
+import { useState, useEffect } from 'react';
+import {
+  Alert,
+  Box,
+  Button,
+  Container,
+  Paper,
+  Table,
+  TableBody,
+  TableCell,
+  TableContainer,
+  TableHead,
+  TableRow,
+  Typography,
+  Dialog,
+  DialogTitle,
+  DialogContent,
+  DialogActions,
+  TextField,
+} from '@mui/material';
+import { useFormik } from 'formik';
+import * as yup from 'yup';
+import type { Venue } from '../../types';
+import api from '../../services/api';
+
+const validationSchema = yup.object({
+  name: yup.string().required('Name is required'),
+  address: yup.string().required('Address is required'),
+  latitude: yup.number().required('Latitude is required').min(-90).max(90),
+  longitude: yup.number().required('Longitude is required').min(-180).max(180),
+  defaultCapacity: yup.number().min(0, 'Capacity must be positive'),
+});
+
+const VenuesManagement = () => {
+  const [venues, setVenues] = useState<Venue[]>([]);
+  const [open, setOpen] = useState(false);
+  const [editingVenue, setEditingVenue] = useState<Venue | null>(null);
+  const [error, setError] = useState<string | null>(null);
+
+  useEffect(() => {
+    fetchVenues();
+  }, []);
+
+  const fetchVenues = async () => {
+    try {
+      const data = await api.venues.getAll();
+      setVenues(data);
+    } catch (error) {
+      console.error('Error fetching venues:', error);
+    }
+  };
+
+  const handleClose = () => {
+    setOpen(false);
+    setEditingVenue(null);
+    setError(null);
+    formik.resetForm();
+  };
+
+  const formik = useFormik({
+    initialValues: {
+      name: '',
+      address: '',
+      latitude: '',
+      longitude: '',
+      defaultCapacity: '0',
+      accessibility: '',
+      photos: '',
+    },
+    validationSchema: validationSchema,
+    onSubmit: async (values) => {
+      try {
+        const venueData = {
+          ...values,
+          latitude: Number(values.latitude),
+          longitude: Number(values.longitude),
+          defaultCapacity: Number(values.defaultCapacity),
+          photos: values.photos.split('\n').map((url) => url.trim()).filter(Boolean),
+        };
+        if (editingVenue) {
+          await api.venues.update(editingVenue.id, venueData);
+        } else {
+          await api.venues.create(venueData);
+        }
+        handleClose();
+        fetchVenues();
+      } catch (error) {
+        setError(error instanceof Error ? error.message : 'Failed to save venue');
+      }
+    },
+  });
+
+  const handleEdit = (venue: Venue) => {
+    setEditingVenue(venue);
+    formik.setValues({
+      name: venue.name,
+      address: venue.address,
+      latitude: venue.latitude.toString(),
+      longitude: venue.longitude.toString(),
+      defaultCapacity: venue.defaultCapacity.toString(),
+      accessibility: venue.accessibility,
+      photos: venue.photos.join('\n'),
+    });
+    setOpen(true);
+  };
+
+  const handleDelete = async (venue: Venue) => {
+    if (window.confirm(`Are you sure you want to delete "${venue.name}"?`)) {
+      try {
+        await api.venues.delete(venue.id);
+        fetchVenues();
+      } catch (error) {
+        window.alert(error instanceof Error ? error.message : 'Failed to delete venue');
+      }
+    }
+  };
+
+  return (
+    <Container>
+      <Box sx={{ mt: 4, mb: 4 }}>
+        <Box sx={{ display: 'flex', justifyContent: 'space-between', mb: 3 }}>
+          <Typography variant="h4" component="h1">
+            Venue Management
+          </Typography>
+          <Button variant="contained" onClick={() => setOpen(true)}>
+            Add New Venue
+          </Button>
+        </Box>
+
+        <TableContainer component={Paper}>
+          <Table>
+            <TableHead>
+              <TableRow>
+                <TableCell>Name</TableCell>
+                <TableCell>Address</TableCell>
+                <TableCell>Capacity</TableCell>
+                <TableCell>Accessibility</TableCell>
+                <TableCell>Actions</TableCell>
+              </TableRow>
+            </TableHead>
+            <TableBody>
+              {venues.map((venue) => (
+                <TableRow key={venue.id}>
+                  <TableCell>{venue.name}</TableCell>
+                  <TableCell>{venue.address}</TableCell>
+                  <TableCell>{venue.defaultCapacity || 'Unlimited'}</TableCell>
+                  <TableCell>{venue.accessibility}</TableCell>
+                  <TableCell>
+                    <Button
+                      size="small"
+                      onClick={() => handleEdit(venue)}
+                      sx={{ mr: 1 }}
+                    >
+                      Edit
+                    </Button>
+                    <Button
+                      size="small"
+                      color="error"
+                      onClick={() => handleDelete(venue)}
+                    >
+                      Delete
+                    </Button>
+                  </TableCell>
+                </TableRow>
+              ))}
+            </TableBody>
+          </Table>
+        </TableContainer>
+
+        <Dialog open={open} onClose={handleClose} maxWidth="sm" fullWidth>
+          <DialogTitle>
+            {editingVenue ? 'Edit Venue' : 'Add New Venue'}
+          </DialogTitle>
+          <form onSubmit={formik.handleSubmit}>
+            <DialogContent>
+              {error && (
+                <Alert severity="error" sx={{ mb: 2 }}>
+                  {error}
+                </Alert>
+              )}
+              <TextField
+                fullWidth
+                margin="normal"
+                name="name"
+                label="Venue Name"
+                value={formik.values.name}
+                onChange={formik.handleChange}
+                error={formik.touched.name && Boolean(formik.errors.name)}
+                helperText={formik.touched.name && formik.errors.name}
+              />
+              <TextField
+                fullWidth
+                margin="normal"
+                name="address"
+                label="Address"
+                value={formik.values.address}
+                onChange={formik.handleChange}
+                error={formik.touched.address && Boolean(formik.errors.address)}
+                helperText={formik.touched.address && formik.errors.address}
+              />
+              <Box sx={{ display: 'flex', gap: 2 }}>
+                <TextField
+                  fullWidth
+                  margin="normal"
+                  name="latitude"
+                  label="Latitude"
+                  type="number"
+                  value={formik.values.latitude}
+                  onChange={formik.handleChange}
+                  error={formik.touched.latitude && Boolean(formik.errors.latitude)}
+                  helperText={formik.touched.latitude && formik.errors.latitude}
+                />
+                <TextField
+                  fullWidth
+                  margin="normal"
+                  name="longitude"
+                  label="Longitude"
+                  type="number"
+                  value={formik.values.longitude}
+                  onChange={formik.handleChange}
+                  error={formik.touched.longitude && Boolean(formik.errors.longitude)}
+                  helperText={formik.touched.longitude && formik.errors.longitude}
+                />
+              </Box>
+              <TextField
+                fullWidth
+                margin="normal"
+                name="defaultCapacity"
+                label="Default Capacity"
+                type="number"
+                value={formik.values.defaultCapacity}
+                onChange={formik.handleChange}
+                error={formik.touched.defaultCapacity && Boolean(formik.errors.defaultCapacity)}
+                helperText={(formik.touched.defaultCapacity && formik.errors.defaultCapacity) || '0 for unlimited'}
+              />
+              <TextField
+                fullWidth
+                margin="normal"
+                name="accessibility"
+                label="Accessibility"
+                multiline
+                value={formik.values.accessibility}
+                onChange={formik.handleChange}
+              />
+              <TextField
+                fullWidth
+                margin="normal"
+                name="photos"
+                label="Photo URLs"
+                multiline
+                rows={3}
+                value={formik.values.photos}
+                onChange={formik.handleChange}
+                helperText="One URL per line"
+              />
+            </DialogContent>
+            <DialogActions>
+              <Button onClick={handleClose}>Cancel</Button>
+              <Button type="submit" variant="contained">
+                {editingVenue ? 'Update' : 'Create'}
+              </Button>
+            </DialogActions>
+          </form>
+        </Dialog>
+      </Box>
+    </Container>
+  );
+};
+
+export default VenuesManagement;
@@ -1,4 +1,4 @@
-import type { Event, User, Tag } from '../types';
+import type { Attendee, AttendeeDetails, BookingQuote, CancellationRule, CheckIn, CheckInStats, Event, EventSeries, GuestDetails, Money, OfflineScan, PaymentIntent, PromoCode, Refund, Seat, SeatAvailability, SeatHold, SeatMap, SeatMapData, SignedManifest, SyncResponse, User, Tag, TagWithCount, TicketTransfer, TicketType, Venue } from '../types';
 import axiosInstance from './axiosInstance';
 
 interface LoginCredentials {
@@ -8,6 +8,9 @@ interface LoginCredentials {
 
 interface RegisterCredentials extends LoginCredentials {
   username: string;
+  // Token of a guest booking's magic link, to bring the guest's bookings
+  // into the new account
+  guestToken?: string;
 }
 
 interface AuthResponse {
@@ -24,24 +27,96 @@ interface CreateEventData {
   description: string;
   category: string;
   date: string;
+  endDate: string;
+  timeZone?: string;
   location: string;
-  price: number;
+  price: Money;
   image: string;
+  status?: 'draft' | 'published';
+  publishAt?: string;
+  salesStartAt?: string;
+  salesEndAt?: string;
+  capacity: number;
+  venueId?: string;
+  rrule?: string;
+  exdates?: string[];
+  cancellationPolicy?: CancellationRule[];
   tagIds: string[];
 }
 
+interface CreateTicketTypeData {
+  name: string;
+  description: string;
+  price: Money;
+  quota: number;
+  salesStartAt?: string;
+  salesEndAt?: string;
+  position: number;
+}
+
+interface CreateVenueData {
+  name: string;
+  address: string;
+  latitude: number;
+  longitude: number;
+  defaultCapacity: number;
+  accessibility: string;
+  photos: string[];
+}
+
 interface CreateTagData {
   name: string;
+  parentId?: string | null;
+}
+
+interface MergeTagResponse {
+  tag: Tag;
+  movedEvents: number;
 }
 
 interface BookingResponse {
   id: string;
   userId: string;
+  guestName?: string;
+  guestEmail?: string;
   eventId: string;
+  status: 'pending_payment' | 'confirmed' | 'cancelled' | 'attended';
   event: Event;
+  payment?: PaymentIntent;
+  paymentExpiresAt?: string;
+  paidAt?: string;
+  ticketType?: TicketType;
+  quantity: number;
+  unitPrice: Money;
+  discount: Money;
+  total: Money;
+  refunds?: Refund[];
+  ticket?: string;
+  attendees?: Attendee[];
+  seats?: Seat[];
   createdAt: string;
+  cancelledAt?: string;
+  checkedInAt?: string;
+  // Only returned when a guest booking is created
+  guestToken?: string;
+}
+
+interface AdminBooking extends BookingResponse {
+  holderName: string;
+  holderEmail: string;
+}
+
+interface AdminBookingList {
+  bookings: AdminBooking[];
+  total: number;
+  page: number;
+  pageSize: number;
 }
 
+type AdminBookingStatus = BookingResponse['status'];
+
+type PromoCodeData = Omit<PromoCode, 'id' | 'uses' | 'createdAt' | 'updatedAt'>;
+
 interface UploadResponse {
   imageUrl: string;
 }
@@ -53,6 +128,22 @@ const handleApiError = (error: any): never => {
   throw new Error('An unexpected error occurred');
 };
 
+// postOnce sends a request that must not be repeated with an Idempotency-Key,
+// and retries it once with the same key when no response arrives
+const postOnce = async <T>(url: string, body: unknown, headers: Record<string, string> = {}): Promise<T> => {
+  const config = { headers: { ...headers, 'Idempotency-Key': crypto.randomUUID() } };
+  try {
+    const { data } = await axiosInstance.post<T>(url, body, config);
+    return data;
+  } catch (error: any) {
+    if (error.response) {
+      throw error;
+    }
+    const { data } = await axiosInstance.post<T>(url, body, config);
+    return data;
+  }
+};
+
 const api = {
   auth: {
     login: async (credentials: LoginCredentials): Promise<AuthResponse> => {
@@ -93,9 +184,10 @@ const api = {
       }
     },
 
-    create: async (eventData: CreateEventData): Promise<Event> => {
+    // Returns the series instead of an event when eventData.rrule is set
+    create: async (eventData: CreateEventData): Promise<Event | EventSeries> => {
       try {
-        const { data } = await axiosInstance.post<Event>('/events', eventData);
+        const { data } = await axiosInstance.post<Event | EventSeries>('/events', eventData);
         return data;
       } catch (error) {
         throw handleApiError(error);
@@ -118,12 +210,134 @@ const api = {
         throw handleApiError(error);
       }
     },
+
+    updateFollowing: async (id: string, eventData: Partial<CreateEventData>): Promise<EventSeries> => {
+      try {
+        const { data } = await axiosInstance.put<EventSeries>(`/events/${id}/following`, eventData);
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    changeStatus: async (
+      id: string,
+      action: 'publish' | 'unpublish' | 'cancel' | 'postpone' | 'complete',
+      note?: string,
+    ): Promise<Event> => {
+      try {
+        const { data } = await axiosInstance.post<Event>(`/events/${id}/${action}`, note ? { note } : undefined);
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+  },
+
+  ticketTypes: {
+    getByEvent: async (eventId: string): Promise<TicketType[]> => {
+      try {
+        const { data } = await axiosInstance.get<TicketType[]>(`/events/${eventId}/ticket-types`);
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    create: async (eventId: string, ticketTypeData: CreateTicketTypeData): Promise<TicketType> => {
+      try {
+        const { data } = await axiosInstance.post<TicketType>(`/events/${eventId}/ticket-types`, ticketTypeData);
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    update: async (eventId: string, id: string, ticketTypeData: CreateTicketTypeData): Promise<TicketType> => {
+      try {
+        const { data } = await axiosInstance.put<TicketType>(`/events/${eventId}/ticket-types/${id}`, ticketTypeData);
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    delete: async (eventId: string, id: string): Promise<void> => {
+      try {
+        await axiosInstance.delete(`/events/${eventId}/ticket-types/${id}`);
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
   },
 
   bookings: {
-    create: async (eventId: string): Promise<BookingResponse> => {
+    // With attendees each ticket is issued to a named person; a holdId books
+    // the tickets of a seat hold
+    create: async (eventId: string, ticketTypeId?: string, quantity = 1, promoCode?: string, attendees?: AttendeeDetails[], holdId?: string, seats?: string[]): Promise<BookingResponse> => {
+      try {
+        return await postOnce<BookingResponse>('/bookings', { eventId, ticketTypeId, quantity, promoCode, attendees, holdId, seats });
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    // Previews the total with a promo code applied without booking
+    quote: async (eventId: string, ticketTypeId?: string, quantity = 1, promoCode?: string): Promise<BookingQuote> => {
+      try {
+        const { data } = await axiosInstance.post<BookingQuote>('/bookings/quote', { eventId, ticketTypeId, quantity, promoCode });
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    // Keeps tickets aside while the user checks out
+    hold: async (eventId: string, ticketTypeId?: string, quantity = 1, seats?: string[]): Promise<SeatHold> => {
+      try {
+        const { data } = await axiosInstance.post<SeatHold>('/bookings/holds', { eventId, ticketTypeId, quantity, seats });
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    releaseHold: async (id: string): Promise<SeatHold> => {
+      try {
+        const { data } = await axiosInstance.delete<SeatHold>(`/bookings/holds/${id}`);
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    // The booking is confirmed once the provider's webhook arrives
+    pay: async (id: string, paymentMethod?: string): Promise<BookingResponse> => {
+      try {
+        return await postOnce<BookingResponse>(`/bookings/${id}/pay`, { paymentMethod });
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    // Paid bookings are refunded according to the event's cancellation policy
+    cancel: async (id: string): Promise<BookingResponse> => {
+      try {
+        const { data } = await axiosInstance.delete<BookingResponse>(`/bookings/${id}`);
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    // E-tickets need the auth header, so they are fetched as blobs to show
+    // with URL.createObjectURL
+    getTicket: async (id: string, format: 'png' | 'pdf', attendeeId?: string): Promise<Blob> => {
       try {
-        const { data } = await axiosInstance.post<BookingResponse>('/bookings', { eventId });
+        const { data } = await axiosInstance.get<Blob>(`/bookings/${id}/ticket.${format}`, {
+          responseType: 'blob',
+          params: attendeeId ? { attendee: attendeeId } : undefined,
+        });
         return data;
       } catch (error) {
         throw handleApiError(error);
@@ -138,6 +352,359 @@ const api = {
         throw handleApiError(error);
       }
     },
+
+    // The recipient is emailed a code to accept the ticket with
+    transfer: async (id: string, email: string): Promise<TicketTransfer> => {
+      try {
+        const { data } = await axiosInstance.post<TicketTransfer>(`/bookings/${id}/transfer`, { email });
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    getTransfers: async (id: string): Promise<TicketTransfer[]> => {
+      try {
+        const { data } = await axiosInstance.get<TicketTransfer[]>(`/bookings/${id}/transfers`);
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+  },
+
+  // Guest bookings are managed with the token from their magic link
+  guestBookings: {
+    create: async (guest: GuestDetails, eventId: string, ticketTypeId?: string, quantity = 1, promoCode?: string, attendees?: AttendeeDetails[], seats?: string[]): Promise<BookingResponse> => {
+      try {
+        // Not retried: without an account there is nothing to scope an Idempotency-Key to
+        const { data } = await axiosInstance.post<BookingResponse>('/guest/bookings', { ...guest, eventId, ticketTypeId, quantity, promoCode, attendees, seats });
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    quote: async (guest: GuestDetails, eventId: string, ticketTypeId?: string, quantity = 1, promoCode?: string): Promise<BookingQuote> => {
+      try {
+        const { data } = await axiosInstance.post<BookingQuote>('/guest/bookings/quote', { ...guest, eventId, ticketTypeId, quantity, promoCode });
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    // Emails fresh links to the guest's bookings; earlier links stop working
+    resendLinks: async (email: string): Promise<void> => {
+      try {
+        await axiosInstance.post('/guest/bookings/link', { email });
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    get: async (id: string, token: string): Promise<BookingResponse> => {
+      try {
+        const { data } = await axiosInstance.get<BookingResponse>(`/guest/bookings/${id}`, { params: { token } });
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    pay: async (id: string, token: string, paymentMethod?: string): Promise<BookingResponse> => {
+      try {
+        return await postOnce<BookingResponse>(`/guest/bookings/${id}/pay`, { paymentMethod }, { 'X-Booking-Token': token });
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    cancel: async (id: string, token: string): Promise<BookingResponse> => {
+      try {
+        const { data } = await axiosInstance.delete<BookingResponse>(`/guest/bookings/${id}`, { params: { token } });
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    getTicket: async (id: string, token: string, format: 'png' | 'pdf', attendeeId?: string): Promise<Blob> => {
+      try {
+        const { data } = await axiosInstance.get<Blob>(`/guest/bookings/${id}/ticket.${format}`, {
+          responseType: 'blob',
+          params: attendeeId ? { token, attendee: attendeeId } : { token },
+        });
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+  },
+
+  transfers: {
+    getAll: async (): Promise<TicketTransfer[]> => {
+      try {
+        const { data } = await axiosInstance.get<TicketTransfer[]>('/transfers');
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    // Returns the booking with its reissued ticket
+    accept: async (token: string): Promise<BookingResponse> => {
+      try {
+        const { data } = await axiosInstance.post<BookingResponse>('/transfers/accept', { token });
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    cancel: async (id: string): Promise<TicketTransfer> => {
+      try {
+        const { data } = await axiosInstance.delete<TicketTransfer>(`/transfers/${id}`);
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+  },
+
+  // Staff only; refused scans reject with the server's reason
+  checkin: {
+    scan: async (token: string, eventId?: string): Promise<CheckIn> => {
+      try {
+        const { data } = await axiosInstance.post<CheckIn>('/checkin', { token, eventId });
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    getStats: async (eventId: string): Promise<CheckInStats> => {
+      try {
+        const { data } = await axiosInstance.get<CheckInStats>(`/events/${eventId}/checkin/stats`);
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    getManifest: async (eventId: string): Promise<SignedManifest> => {
+      try {
+        const { data } = await axiosInstance.get<SignedManifest>(`/events/${eventId}/checkin/manifest`);
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    sync: async (eventId: string, deviceId: string, scans: OfflineScan[]): Promise<SyncResponse> => {
+      try {
+        const { data } = await axiosInstance.post<SyncResponse>(`/events/${eventId}/checkin/sync`, { deviceId, scans });
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+  },
+
+  promoCodes: {
+    getAll: async (): Promise<PromoCode[]> => {
+      try {
+        const { data } = await axiosInstance.get<PromoCode[]>('/promo-codes');
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    create: async (promo: PromoCodeData): Promise<PromoCode> => {
+      try {
+        const { data } = await axiosInstance.post<PromoCode>('/promo-codes', promo);
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    update: async (id: string, promo: PromoCodeData): Promise<PromoCode> => {
+      try {
+        const { data } = await axiosInstance.put<PromoCode>(`/promo-codes/${id}`, promo);
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    delete: async (id: string): Promise<void> => {
+      try {
+        await axiosInstance.delete(`/promo-codes/${id}`);
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+  },
+
+  series: {
+    getById: async (id: string): Promise<EventSeries> => {
+      try {
+        const { data } = await axiosInstance.get<EventSeries>(`/series/${id}`);
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    update: async (id: string, eventData: Partial<CreateEventData>): Promise<EventSeries> => {
+      try {
+        const { data } = await axiosInstance.put<EventSeries>(`/series/${id}`, eventData);
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+  },
+
+  venues: {
+    getAll: async (): Promise<Venue[]> => {
+      try {
+        const { data } = await axiosInstance.get<Venue[]>('/venues');
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    create: async (venueData: CreateVenueData): Promise<Venue> => {
+      try {
+        const { data } = await axiosInstance.post<Venue>('/venues', venueData);
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    update: async (id: string, venueData: CreateVenueData): Promise<Venue> => {
+      try {
+        const { data } = await axiosInstance.put<Venue>(`/venues/${id}`, venueData);
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    delete: async (id: string): Promise<void> => {
+      try {
+        await axiosInstance.delete(`/venues/${id}`);
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+  },
+
+  // Seated events sell the seats of their own seat map or their venue's
+  seating: {
+    getVenueMap: async (venueId: string): Promise<SeatMap> => {
+      try {
+        const { data } = await axiosInstance.get<SeatMap>(`/venues/${venueId}/seat-map`);
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    saveVenueMap: async (venueId: string, seatMap: SeatMapData): Promise<SeatMap> => {
+      try {
+        const { data } = await axiosInstance.put<SeatMap>(`/venues/${venueId}/seat-map`, seatMap);
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    deleteVenueMap: async (venueId: string): Promise<void> => {
+      try {
+        await axiosInstance.delete(`/venues/${venueId}/seat-map`);
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    saveEventMap: async (eventId: string, seatMap: SeatMapData): Promise<SeatMap> => {
+      try {
+        const { data } = await axiosInstance.put<SeatMap>(`/events/${eventId}/seat-map`, seatMap);
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    deleteEventMap: async (eventId: string): Promise<void> => {
+      try {
+        await axiosInstance.delete(`/events/${eventId}/seat-map`);
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    getSeats: async (eventId: string): Promise<SeatAvailability> => {
+      try {
+        const { data } = await axiosInstance.get<SeatAvailability>(`/events/${eventId}/seats`);
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+  },
+
+  // Booking management for the organisers of an event (admin only)
+  admin: {
+    listBookings: async (
+      eventId: string,
+      params?: { status?: AdminBookingStatus; q?: string; page?: number; pageSize?: number }
+    ): Promise<AdminBookingList> => {
+      try {
+        const { data } = await axiosInstance.get<AdminBookingList>(`/admin/events/${eventId}/bookings`, { params });
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    exportBookings: async (
+      eventId: string,
+      format: 'csv' | 'xlsx' = 'csv',
+      params?: { status?: AdminBookingStatus; q?: string }
+    ): Promise<Blob> => {
+      try {
+        const { data } = await axiosInstance.get<Blob>(`/admin/events/${eventId}/bookings/export`, {
+          params: { ...params, format },
+          responseType: 'blob',
+        });
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    cancelBooking: async (id: string, reason?: string, refundPercent?: number): Promise<AdminBooking> => {
+      try {
+        return await postOnce<AdminBooking>(`/admin/bookings/${id}/cancel`, { reason, refundPercent });
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    moveBooking: async (id: string, eventId: string, ticketTypeId?: string, seats?: string[]): Promise<AdminBooking> => {
+      try {
+        return await postOnce<AdminBooking>(`/admin/bookings/${id}/move`, { eventId, ticketTypeId, seats });
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
   },
 
   tags: {
@@ -150,6 +717,24 @@ const api = {
       }
     },
 
+    getAllWithCounts: async (): Promise<TagWithCount[]> => {
+      try {
+        const { data } = await axiosInstance.get<TagWithCount[]>('/tags', { params: { withCounts: true } });
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    autocomplete: async (query: string, limit = 10): Promise<TagWithCount[]> => {
+      try {
+        const { data } = await axiosInstance.get<TagWithCount[]>('/tags/autocomplete', { params: { q: query, limit } });
+        return data;
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
     getById: async (id: string): Promise<Tag> => {
       try {
         const { data } = await axiosInstance.get<Tag>(`/tags/${id}`);
@@ -177,9 +762,18 @@ const api = {
       }
     },
 
-    delete: async (id: string): Promise<void> => {
+    delete: async (id: string, force = false): Promise<void> => {
       try {
-        await axiosInstance.delete(`/tags/${id}`);
+        await axiosInstance.delete(`/tags/${id}`, { params: force ? { force: true } : undefined });
+      } catch (error) {
+        throw handleApiError(error);
+      }
+    },
+
+    merge: async (id: string, targetId: string): Promise<MergeTagResponse> => {
+      try {
+        const { data } = await axiosInstance.post<MergeTagResponse>(`/tags/${id}/merge`, { targetId });
+        return data;
       } catch (error) {
         throw handleApiError(error);
       }
@@ -2,31 +2,284 @@ export interface User {
   id: string;
   username: string;
   email: string;
-  role: 'admin' | 'user';
+  role: 'admin' | 'staff' | 'user';
 }
 
+// Money amounts are in minor units of the currency, e.g. 2550 USD is $25.50
+export interface Money {
+  amount: number;
+  currency: string;
+  decimal?: string;
+}
+
+export interface PaymentIntent {
+  id: string;
+  amount: Money;
+  status: 'requires_payment' | 'processing' | 'succeeded' | 'canceled';
+  clientSecret?: string;
+}
+
+export type EventStatus = 'draft' | 'published' | 'cancelled' | 'postponed' | 'completed';
+
 export interface Event {
   id: string;
   name: string;
   description: string;
   category: string;
   date: string;
+  endDate: string;
+  timeZone: string;
+  localDate: string;
+  localEndDate: string;
+  durationMinutes: number;
   location: string;
-  price: number;
+  price: Money;
   image: string;
+  status: EventStatus;
+  statusNote?: string;
+  publishAt?: string;
+  salesStartAt?: string;
+  salesEndAt?: string;
+  seriesId?: string;
+  capacity: number;
+  venueId?: string;
+  venue?: Venue;
+  distanceKm?: number;
+  venueConflicts?: VenueConflict[];
+  ticketTypes?: TicketType[];
+  cancellationPolicy?: CancellationRule[];
+  transfersDisabled?: boolean;
+  maxTicketsPerOrder?: number;
+  maxTicketsPerUser?: number;
   tags?: Tag[];
 }
 
+export interface CancellationRule {
+  hoursBefore: number;
+  refundPercent: number;
+}
+
+export interface PromoCode {
+  id: string;
+  code: string;
+  type: 'percent' | 'fixed';
+  percentOff?: number;
+  amountOff?: Money;
+  maxUses: number;
+  maxUsesPerUser: number;
+  uses: number;
+  validFrom?: string;
+  validUntil?: string;
+  eventIds: string[];
+  tagIds: string[];
+  active: boolean;
+  createdAt: string;
+  updatedAt: string;
+}
+
+export interface BookingQuote {
+  eventId: string;
+  ticketTypeId?: string;
+  quantity: number;
+  unitPrice: Money;
+  subtotal: Money;
+  promoCode?: string;
+  discount: Money;
+  total: Money;
+}
+
+export interface Refund {
+  id: string;
+  bookingId: string;
+  amount: Money;
+  percent: number;
+  reason: 'customer_cancelled' | 'event_cancelled' | 'late_payment';
+  status: 'pending' | 'succeeded' | 'failed';
+  createdAt: string;
+}
+
+export interface TicketTransfer {
+  id: string;
+  bookingId: string;
+  eventId: string;
+  fromUserId: string;
+  toEmail: string;
+  toUserId?: string;
+  status: 'pending' | 'accepted' | 'cancelled' | 'expired';
+  expiresAt: string;
+  acceptedAt?: string;
+  cancelledAt?: string;
+  createdAt: string;
+  event?: Event;
+}
+
+export interface TicketType {
+  id: string;
+  eventId: string;
+  name: string;
+  description: string;
+  price: Money;
+  quota: number;
+  sold: number;
+  // Limits the ticket type to seats of the seat map's price zone
+  zone?: string;
+  salesStartAt?: string;
+  salesEndAt?: string;
+  position: number;
+}
+
+export interface Venue {
+  id: string;
+  name: string;
+  address: string;
+  latitude: number;
+  longitude: number;
+  defaultCapacity: number;
+  accessibility: string;
+  photos: string[];
+}
+
+export interface VenueConflict {
+  eventId: string;
+  name: string;
+  date: string;
+  endDate: string;
+}
+
+export interface EventSeries {
+  id: string;
+  rrule: string;
+  exdates?: string[];
+  timeZone: string;
+  events?: Event[];
+}
+
 export interface Booking {
   id: string;
+  // Empty for guest bookings until the guest registers
   userId: string;
+  guestName?: string;
+  guestEmail?: string;
   eventId: string;
+  ticketTypeId?: string;
+  quantity: number;
+  unitPrice: Money;
+  promoCodeId?: string;
+  discount: Money;
+  total: Money;
+  status: 'pending_payment' | 'confirmed' | 'cancelled' | 'attended';
+  checkedInAt?: string;
+  checkedInDevice?: string;
+  ticketVersion: number;
+  paymentExpiresAt?: string;
+  paidAt?: string;
+  refunds?: Refund[];
+  ticket?: string;
+  attendees?: Attendee[];
+  seats?: SeatReservation[];
   bookingDate: string;
 }
 
+// A SeatHold keeps tickets aside during checkout until expiresAt
+export interface SeatHold {
+  id: string;
+  eventId: string;
+  ticketTypeId?: string;
+  userId: string;
+  quantity: number;
+  status: 'active' | 'consumed' | 'released' | 'expired';
+  expiresAt: string;
+  bookingId?: string;
+  seats?: SeatReservation[];
+  createdAt: string;
+}
+
+export interface Seat {
+  id: string;
+  section: string;
+  row: string;
+  number: string;
+  accessible: boolean;
+  // The price zone; ticket types with the same zone sell the seat
+  zone?: string;
+}
+
+// A SeatMap lays out a venue, or an event whose layout differs from its venue's
+export interface SeatMap {
+  id: string;
+  venueId?: string;
+  eventId?: string;
+  name: string;
+  seats: Seat[];
+  createdAt: string;
+  updatedAt: string;
+}
+
+export interface SeatReservation {
+  id: string;
+  eventId: string;
+  seatId: string;
+  bookingId?: string;
+  holdId?: string;
+  seat?: Seat;
+  createdAt: string;
+}
+
+export interface SeatState extends Seat {
+  state: 'available' | 'held' | 'booked';
+}
+
+export interface SeatAvailability {
+  eventId: string;
+  seatMapId: string;
+  name: string;
+  available: number;
+  seats: SeatState[];
+}
+
+// Rows set either a count of seats numbered from 1 or their seats
+export interface SeatMapData {
+  name: string;
+  sections: {
+    name: string;
+    zone?: string;
+    rows: {
+      label: string;
+      count?: number;
+      seats?: Pick<Seat, 'number' | 'accessible' | 'zone'>[];
+      accessible?: boolean;
+      zone?: string;
+    }[];
+  }[];
+}
+
+// GuestDetails book without an account
+export interface GuestDetails {
+  name: string;
+  email: string;
+}
+
+// Each attendee of a group booking has their own ticket
+export interface Attendee {
+  id: string;
+  name: string;
+  email: string;
+  checkedInAt?: string;
+  ticket?: string;
+}
+
+export type AttendeeDetails = Pick<Attendee, 'name' | 'email'>;
+
 export interface Tag {
   id: string;
   name: string;
+  slug?: string;
+  parentId?: string | null;
+  children?: Tag[];
+}
+
+export interface TagWithCount extends Tag {
+  eventCount: number;
 }
 
 export interface AuthState {
@@ -35,4 +288,70 @@ export interface AuthState {
   isAuthenticated: boolean;
   isLoading: boolean;
   error: string | null;
-} 
\ No newline at end of file
+} 
+
+export interface CheckIn {
+  bookingId: string;
+  attendeeId?: string;
+  eventId: string;
+  attendee: string;
+  ticketType?: string;
+  quantity: number;
+  checkedInAt: string;
+  checkedInBy: string;
+}
+
+export interface CheckInStats {
+  eventId: string;
+  bookings: number;
+  tickets: number;
+  checkedInBookings: number;
+  checkedInTickets: number;
+  remainingTickets: number;
+  lastCheckInAt?: string;
+}
+
+// payload is base64url JSON of a Manifest, signed with the ticket key
+export interface SignedManifest {
+  payload: string;
+  signature: string;
+}
+
+export interface ManifestEntry {
+  bookingId: string;
+  ticketVersion: number;
+  status: string;
+  quantity: number;
+  attendee: string;
+  ticketType?: string;
+  checkedInAt?: string;
+  attendees?: { id: string; name: string; checkedInAt?: string }[];
+}
+
+export interface Manifest {
+  eventId: string;
+  generatedAt: string;
+  entries: ManifestEntry[];
+}
+
+export interface OfflineScan {
+  token: string;
+  scannedAt: string;
+}
+
+export interface SyncResult {
+  token: string;
+  bookingId?: string;
+  result: 'accepted' | 'duplicate' | 'rejected';
+  code?: string;
+  error?: string;
+  checkedInAt?: string;
+  deviceId?: string;
+}
+
+export interface SyncResponse {
+  accepted: number;
+  duplicates: number;
+  rejected: number;
+  results: SyncResult[];
+}
@@ -0,0 +1,18 @@
+import type { Money } from '../types';
+
+// Number of minor unit digits of a currency, e.g. 2 for USD and 0 for JPY
+export const currencyDigits = (currency: string): number =>
+  new Intl.NumberFormat('en', { style: 'currency', currency }).resolvedOptions().maximumFractionDigits ?? 2;
+
+export const formatMoney = (money: Money): string =>
+  new Intl.NumberFormat(undefined, { style: 'currency', currency: money.currency }).format(
+    money.amount / 10 ** currencyDigits(money.currency),
+  );
+
+// Converts a decimal string such as "25.50" to minor units without going through floats
+export const toMoney = (value: string, currency: string): Money => {
+  const digits = currencyDigits(currency);
+  const [whole, fraction = ''] = value.trim().split('.');
+  const minor = (fraction + '0'.repeat(digits)).slice(0, digits);
+  return { amount: Number(whole || '0') * 10 ** digits + Number(minor || '0'), currency };
+};